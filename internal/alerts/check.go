@@ -0,0 +1,92 @@
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kaze/xrv/internal/domain"
+	"github.com/kaze/xrv/internal/service"
+)
+
+type CheckOptions struct {
+	EndDate  time.Time
+	UseCache bool
+	Rebase   bool
+}
+
+// Check fetches enough fixings up to opts.EndDate for every rule, one request
+// per base currency, and returns the alerts of the rules that fire on their
+// latest fixing, in rule order.
+func Check(ctx context.Context, src service.Fetcher, rules []Rule, opts CheckOptions) ([]Alert, error) {
+	end := opts.EndDate
+	if end.IsZero() {
+		end = time.Now()
+	}
+
+	type group struct {
+		targets      []domain.Currency
+		seen         map[domain.Currency]bool
+		observations int
+	}
+	groups := make(map[domain.Currency]*group)
+	var bases []domain.Currency
+	for _, rule := range rules {
+		g, exists := groups[rule.Base]
+		if !exists {
+			g = &group{seen: make(map[domain.Currency]bool)}
+			groups[rule.Base] = g
+			bases = append(bases, rule.Base)
+		}
+		if !g.seen[rule.Target] {
+			g.seen[rule.Target] = true
+			g.targets = append(g.targets, rule.Target)
+		}
+		g.observations = max(g.observations, rule.Observations())
+	}
+
+	series := make(map[domain.Currency]*domain.TimeSeriesData, len(groups))
+	for _, base := range bases {
+		g := groups[base]
+		data, err := src.FetchTimeSeriesData(ctx, service.FetchOptions{
+			Base:      base,
+			Targets:   g.targets,
+			StartDate: end.AddDate(0, 0, -lookbackDays(g.observations)),
+			EndDate:   end,
+			UseCache:  opts.UseCache,
+			Rebase:    opts.Rebase,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch %s rates: %w", base, err)
+		}
+		series[base] = data
+	}
+
+	var alerts []Alert
+	for _, rule := range rules {
+		dates, rates := Observed(series[rule.Base], rule.Target)
+		if alert, fired := rule.Evaluate(dates, rates); fired {
+			alerts = append(alerts, alert)
+		}
+	}
+	return alerts, nil
+}
+
+// Observed returns the dates and rates of target's fixings in data.
+func Observed(data *domain.TimeSeriesData, target domain.Currency) ([]time.Time, []float64) {
+	var dates []time.Time
+	var rates []float64
+	for _, dp := range data.DataPoints {
+		if rate, exists := dp.Rates[target]; exists {
+			dates = append(dates, dp.Date)
+			rates = append(rates, rate)
+		}
+	}
+	return dates, rates
+}
+
+// lookbackDays covers the given number of fixings with room for weekends and
+// holidays.
+func lookbackDays(observations int) int {
+	return observations*7/5 + 14
+}
@@ -0,0 +1,65 @@
+package alerts
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kaze/xrv/internal/domain"
+	"github.com/kaze/xrv/internal/service"
+)
+
+type fakeSource struct {
+	fetched []service.FetchOptions
+	rates   map[domain.Currency][]float64
+}
+
+func (f *fakeSource) FetchTimeSeriesData(ctx context.Context, opts service.FetchOptions) (*domain.TimeSeriesData, error) {
+	f.fetched = append(f.fetched, opts)
+	data := &domain.TimeSeriesData{Base: opts.Base, Targets: opts.Targets}
+	for i, date := range dates(3) {
+		dp := domain.DataPoint{Date: date, Rates: make(map[domain.Currency]float64)}
+		for _, target := range opts.Targets {
+			dp.Rates[target] = f.rates[target][i]
+		}
+		data.DataPoints = append(data.DataPoints, dp)
+	}
+	return data, nil
+}
+
+func TestCheck(t *testing.T) {
+	src := &fakeSource{rates: map[domain.Currency][]float64{
+		"HUF": {399, 399.5, 401},
+		"USD": {1.08, 1.09, 1.085},
+		"EUR": {0.9, 0.91, 0.95},
+	}}
+	rules := []Rule{
+		{Base: "EUR", Target: "HUF", Type: RuleThreshold, Above: level(400)},
+		{Base: "EUR", Target: "USD", Type: RuleChange, Percent: 1, Days: 2},
+		{Base: "EUR", Target: "HUF", Type: RuleChange, Percent: 0.1, Days: 1},
+		{Base: "GBP", Target: "EUR", Type: RuleHighLow, Days: 2},
+	}
+
+	end := time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC)
+	alerts, err := Check(context.Background(), src, rules, CheckOptions{EndDate: end, UseCache: true})
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+
+	if len(src.fetched) != 2 {
+		t.Fatalf("fetched %d times, want once per base", len(src.fetched))
+	}
+	if eur := src.fetched[0]; eur.Base != "EUR" || len(eur.Targets) != 2 || !eur.EndDate.Equal(end) || !eur.UseCache {
+		t.Errorf("EUR fetch = %+v, want HUF and USD up to %s", eur, end)
+	}
+	if start := src.fetched[0].StartDate; !start.Before(end.AddDate(0, 0, -4)) {
+		t.Errorf("EUR fetch starts %s, want room for 3 fixings", start)
+	}
+
+	if len(alerts) != 3 {
+		t.Fatalf("alerts = %+v, want the HUF threshold and move and the GBP high", alerts)
+	}
+	if alerts[0].Rule.Type != RuleThreshold || alerts[1].Rule.Target != "HUF" || alerts[2].Rule.Base != "GBP" {
+		t.Errorf("alerts = %+v, want rule order", alerts)
+	}
+}
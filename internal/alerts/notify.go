@@ -0,0 +1,135 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+// Notifier delivers the alerts of one check; it is not called when no rule
+// fired.
+type Notifier interface {
+	Notify(ctx context.Context, alerts []Alert) error
+}
+
+// Notifiers delivers to each notifier in turn and returns every failure.
+type Notifiers []Notifier
+
+func (n Notifiers) Notify(ctx context.Context, alerts []Alert) error {
+	var errs []error
+	for _, notifier := range n {
+		if err := notifier.Notify(ctx, alerts); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+type writerNotifier struct {
+	w io.Writer
+}
+
+// NewWriterNotifier prints one line per alert, e.g. to stdout.
+func NewWriterNotifier(w io.Writer) Notifier {
+	return writerNotifier{w: w}
+}
+
+func (n writerNotifier) Notify(ctx context.Context, alerts []Alert) error {
+	for _, alert := range alerts {
+		if _, err := fmt.Fprintln(n.w, formatAlert(alert)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type fileNotifier struct {
+	path string
+}
+
+// NewFileNotifier appends one line per alert to the file at path.
+func NewFileNotifier(path string) Notifier {
+	return fileNotifier{path: path}
+}
+
+func (n fileNotifier) Notify(ctx context.Context, alerts []Alert) error {
+	f, err := os.OpenFile(n.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open alert log: %w", err)
+	}
+	if err := (writerNotifier{w: f}).Notify(ctx, alerts); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write alert log: %w", err)
+	}
+	return f.Close()
+}
+
+type webhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookNotifier posts the alerts of a check as one JSON document:
+//
+//	{"alerts": [{"rule": "...", "pair": "EUR/HUF", "type": "threshold",
+//	  "date": "2024-03-01", "rate": 401.2, "message": "..."}]}
+func NewWebhookNotifier(url string, timeout time.Duration) Notifier {
+	return webhookNotifier{url: url, client: &http.Client{Timeout: timeout}}
+}
+
+func (n webhookNotifier) Notify(ctx context.Context, alerts []Alert) error {
+	type alertJSON struct {
+		Rule    string  `json:"rule"`
+		Pair    string  `json:"pair"`
+		Type    string  `json:"type"`
+		Date    string  `json:"date"`
+		Rate    float64 `json:"rate"`
+		Message string  `json:"message"`
+	}
+
+	payload := struct {
+		Alerts []alertJSON `json:"alerts"`
+	}{Alerts: make([]alertJSON, len(alerts))}
+	for i, alert := range alerts {
+		payload.Alerts[i] = alertJSON{
+			Rule:    alert.Rule.String(),
+			Pair:    alert.Rule.Pair(),
+			Type:    string(alert.Rule.Type),
+			Date:    alert.Date.Format("2006-01-02"),
+			Rate:    alert.Rate,
+			Message: alert.Message,
+		}
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid webhook: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook failed: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+func formatAlert(alert Alert) string {
+	return fmt.Sprintf("%s  %s: %s", alert.Date.Format("2006-01-02"), alert.Rule.String(), alert.Message)
+}
@@ -0,0 +1,84 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testAlerts() []Alert {
+	return []Alert{{
+		Rule:    Rule{Name: "Forint above 400", Base: "EUR", Target: "HUF", Type: RuleThreshold, Above: level(400)},
+		Date:    time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		Rate:    401.2,
+		Message: "EUR/HUF at 401.2000 rose above 400",
+	}}
+}
+
+func TestWriterAndFileNotifier(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewWriterNotifier(&buf).Notify(context.Background(), testAlerts()); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	want := "2024-03-01  Forint above 400: EUR/HUF at 401.2000 rose above 400\n"
+	if buf.String() != want {
+		t.Errorf("stdout = %q, want %q", buf.String(), want)
+	}
+
+	path := filepath.Join(t.TempDir(), "alerts.log")
+	notifier := NewFileNotifier(path)
+	for i := 0; i < 2; i++ {
+		if err := notifier.Notify(context.Background(), testAlerts()); err != nil {
+			t.Fatalf("Notify() error = %v", err)
+		}
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != want+want {
+		t.Errorf("log = %q, want both checks appended", content)
+	}
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	var received struct {
+		Alerts []struct {
+			Rule string  `json:"rule"`
+			Pair string  `json:"pair"`
+			Date string  `json:"date"`
+			Rate float64 `json:"rate"`
+		} `json:"alerts"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("request = %s %s", r.Method, r.Header.Get("Content-Type"))
+		}
+		json.NewDecoder(r.Body).Decode(&received)
+	}))
+	defer server.Close()
+
+	if err := NewWebhookNotifier(server.URL, time.Second).Notify(context.Background(), testAlerts()); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if len(received.Alerts) != 1 || received.Alerts[0].Pair != "EUR/HUF" || received.Alerts[0].Date != "2024-03-01" || received.Alerts[0].Rate != 401.2 {
+		t.Errorf("received = %+v", received)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer failing.Close()
+
+	err := Notifiers{NewWriterNotifier(&bytes.Buffer{}), NewWebhookNotifier(failing.URL, time.Second)}.Notify(context.Background(), testAlerts())
+	if err == nil || !strings.Contains(err.Error(), "502") {
+		t.Errorf("Notify() error = %v, want the webhook status", err)
+	}
+}
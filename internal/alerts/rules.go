@@ -0,0 +1,247 @@
+package alerts
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+
+	"github.com/kaze/xrv/internal/domain"
+	"github.com/kaze/xrv/internal/statistics"
+)
+
+type RuleType string
+
+const (
+	// RuleThreshold fires when the rate crosses Above upwards or Below
+	// downwards between the last two fixings.
+	RuleThreshold RuleType = "threshold"
+	// RuleChange fires when the rate moved at least Percent over the last
+	// Days fixings, in either direction.
+	RuleChange RuleType = "change"
+	// RuleSMACross fires when the rate crosses its Period-fixing simple
+	// moving average between the last two fixings.
+	RuleSMACross RuleType = "sma_cross"
+	// RuleHighLow fires when the last fixing is above or below every one of
+	// the Days fixings before it.
+	RuleHighLow RuleType = "high_low"
+)
+
+// Rule watches the rate of Target in Base. Days and Period count fixings,
+// i.e. business days.
+type Rule struct {
+	Name    string
+	Base    domain.Currency
+	Target  domain.Currency
+	Type    RuleType
+	Above   *float64
+	Below   *float64
+	Percent float64
+	Days    int
+	Period  int
+}
+
+// Alert is a rule that fired on the fixing of Date.
+type Alert struct {
+	Rule    Rule
+	Date    time.Time
+	Rate    float64
+	Message string
+}
+
+// Pair returns the rule's rate as BASE/TARGET.
+func (r Rule) Pair() string {
+	return fmt.Sprintf("%s/%s", r.Base, r.Target)
+}
+
+// String returns the rule's name, or describes it when it has none.
+func (r Rule) String() string {
+	if r.Name != "" {
+		return r.Name
+	}
+
+	switch r.Type {
+	case RuleThreshold:
+		var levels []string
+		if r.Above != nil {
+			levels = append(levels, fmt.Sprintf("above %g", *r.Above))
+		}
+		if r.Below != nil {
+			levels = append(levels, fmt.Sprintf("below %g", *r.Below))
+		}
+		return fmt.Sprintf("%s %s", r.Pair(), strings.Join(levels, " or "))
+	case RuleChange:
+		return fmt.Sprintf("%s moves %g%% in %d days", r.Pair(), r.Percent, r.Days)
+	case RuleSMACross:
+		return fmt.Sprintf("%s crosses SMA(%d)", r.Pair(), r.Period)
+	case RuleHighLow:
+		return fmt.Sprintf("%s %d-day high/low", r.Pair(), r.Days)
+	default:
+		return r.Pair()
+	}
+}
+
+func (r Rule) Validate() error {
+	if r.Base == "" || r.Target == "" {
+		return fmt.Errorf("rule %q: pair must be BASE/TARGET", r.String())
+	}
+
+	switch r.Type {
+	case RuleThreshold:
+		if r.Above == nil && r.Below == nil {
+			return fmt.Errorf("rule %q: threshold needs above or below", r.String())
+		}
+	case RuleChange:
+		if r.Percent <= 0 || r.Days <= 0 {
+			return fmt.Errorf("rule %q: change needs a positive percent and days", r.String())
+		}
+	case RuleSMACross:
+		if r.Period < 2 {
+			return fmt.Errorf("rule %q: sma_cross needs a period of at least 2", r.String())
+		}
+	case RuleHighLow:
+		if r.Days <= 0 {
+			return fmt.Errorf("rule %q: high_low needs positive days", r.String())
+		}
+	default:
+		return fmt.Errorf("rule %q: unknown type %q (use 'threshold', 'change', 'sma_cross' or 'high_low')", r.String(), r.Type)
+	}
+	return nil
+}
+
+// Observations returns how many of the latest fixings the rule looks at.
+func (r Rule) Observations() int {
+	switch r.Type {
+	case RuleChange, RuleHighLow:
+		return r.Days + 1
+	case RuleSMACross:
+		return r.Period + 1
+	default:
+		return 2
+	}
+}
+
+// Evaluate checks the rule against the target's fixings in date order and
+// reports an alert for the last one.
+func (r Rule) Evaluate(dates []time.Time, rates []float64) (Alert, bool) {
+	n := len(rates)
+	if n < r.Observations() || n != len(dates) {
+		return Alert{}, false
+	}
+
+	last, previous := rates[n-1], rates[n-2]
+	alert := Alert{Rule: r, Date: dates[n-1], Rate: last}
+
+	switch r.Type {
+	case RuleThreshold:
+		if r.Above != nil && previous <= *r.Above && last > *r.Above {
+			alert.Message = fmt.Sprintf("rose above %g", *r.Above)
+		} else if r.Below != nil && previous >= *r.Below && last < *r.Below {
+			alert.Message = fmt.Sprintf("fell below %g", *r.Below)
+		}
+	case RuleChange:
+		change := (last/rates[n-1-r.Days] - 1) * 100
+		if math.Abs(change) >= r.Percent {
+			alert.Message = fmt.Sprintf("moved %+.2f%% over %d days", change, r.Days)
+		}
+	case RuleSMACross:
+		sma := statistics.CalculateSMA(rates, r.Period)
+		before, after := previous-sma[n-2], last-sma[n-1]
+		if before <= 0 && after > 0 {
+			alert.Message = fmt.Sprintf("crossed above its SMA(%d) of %.4f", r.Period, sma[n-1])
+		} else if before >= 0 && after < 0 {
+			alert.Message = fmt.Sprintf("crossed below its SMA(%d) of %.4f", r.Period, sma[n-1])
+		}
+	case RuleHighLow:
+		high, low := math.Inf(-1), math.Inf(1)
+		for _, rate := range rates[n-1-r.Days : n-1] {
+			high, low = math.Max(high, rate), math.Min(low, rate)
+		}
+		if last > high {
+			alert.Message = fmt.Sprintf("set a new %d-day high", r.Days)
+		} else if last < low {
+			alert.Message = fmt.Sprintf("set a new %d-day low", r.Days)
+		}
+	}
+
+	if alert.Message == "" {
+		return Alert{}, false
+	}
+	alert.Message = fmt.Sprintf("%s at %.4f %s", r.Pair(), last, alert.Message)
+	return alert, true
+}
+
+// LoadRules reads a rules file.
+func LoadRules(path string) ([]Rule, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open rules: %w", err)
+	}
+	defer f.Close()
+
+	rules, err := ParseRules(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return rules, nil
+}
+
+// ParseRules reads
+//
+//	rules:
+//	  - name: Forint above 400
+//	    pair: EUR/HUF
+//	    type: threshold
+//	    above: 400
+//	  - pair: EUR/HUF
+//	    type: change
+//	    percent: 1
+//	    days: 1
+func ParseRules(r io.Reader) ([]Rule, error) {
+	var file struct {
+		Rules []struct {
+			Name    string   `yaml:"name"`
+			Pair    string   `yaml:"pair"`
+			Type    string   `yaml:"type"`
+			Above   *float64 `yaml:"above"`
+			Below   *float64 `yaml:"below"`
+			Percent float64  `yaml:"percent"`
+			Days    int      `yaml:"days"`
+			Period  int      `yaml:"period"`
+		} `yaml:"rules"`
+	}
+
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	rules := make([]Rule, 0, len(file.Rules))
+	for _, entry := range file.Rules {
+		base, target, _ := strings.Cut(strings.ToUpper(strings.TrimSpace(entry.Pair)), "/")
+		rule := Rule{
+			Name:    entry.Name,
+			Base:    domain.Currency(strings.TrimSpace(base)),
+			Target:  domain.Currency(strings.TrimSpace(target)),
+			Type:    RuleType(strings.ToLower(strings.TrimSpace(entry.Type))),
+			Above:   entry.Above,
+			Below:   entry.Below,
+			Percent: entry.Percent,
+			Days:    entry.Days,
+			Period:  entry.Period,
+		}
+		if err := rule.Validate(); err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	if len(rules) == 0 {
+		return nil, fmt.Errorf("no rules")
+	}
+	return rules, nil
+}
@@ -0,0 +1,95 @@
+package alerts
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func dates(n int) []time.Time {
+	result := make([]time.Time, n)
+	for i := range result {
+		result[i] = time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, i)
+	}
+	return result
+}
+
+func level(value float64) *float64 {
+	return &value
+}
+
+func TestRule_Evaluate(t *testing.T) {
+	tests := []struct {
+		name        string
+		rule        Rule
+		rates       []float64
+		wantMessage string
+	}{
+		{"crosses above", Rule{Type: RuleThreshold, Above: level(400)}, []float64{398, 399.5, 401.2}, "rose above 400"},
+		{"stays above", Rule{Type: RuleThreshold, Above: level(400)}, []float64{401, 402}, ""},
+		{"crosses below", Rule{Type: RuleThreshold, Above: level(400), Below: level(390)}, []float64{391, 389}, "fell below 390"},
+		{"daily move", Rule{Type: RuleChange, Percent: 1, Days: 1}, []float64{400, 395}, "moved -1.25% over 1 days"},
+		{"small move", Rule{Type: RuleChange, Percent: 1, Days: 2}, []float64{400, 402, 403}, ""},
+		{"crosses SMA", Rule{Type: RuleSMACross, Period: 3}, []float64{5, 4, 3, 2, 5}, "crossed above its SMA(3) of 3.3333"},
+		{"below SMA", Rule{Type: RuleSMACross, Period: 3}, []float64{5, 4, 3, 2, 1}, ""},
+		{"new high", Rule{Type: RuleHighLow, Days: 3}, []float64{9, 1, 2, 3, 4}, "set a new 3-day high"},
+		{"new low", Rule{Type: RuleHighLow, Days: 2}, []float64{2, 3, 1}, "set a new 2-day low"},
+		{"not enough fixings", Rule{Type: RuleHighLow, Days: 5}, []float64{1, 2, 3}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.rule.Base, tt.rule.Target = "EUR", "HUF"
+			alert, fired := tt.rule.Evaluate(dates(len(tt.rates)), tt.rates)
+			if fired != (tt.wantMessage != "") {
+				t.Fatalf("Evaluate() fired = %v, message %q", fired, alert.Message)
+			}
+			if fired && !strings.HasSuffix(alert.Message, tt.wantMessage) {
+				t.Errorf("Message = %q, want it to end with %q", alert.Message, tt.wantMessage)
+			}
+			if fired && (alert.Rate != tt.rates[len(tt.rates)-1] || !alert.Date.Equal(dates(len(tt.rates))[len(tt.rates)-1])) {
+				t.Errorf("alert = %+v, want the last fixing", alert)
+			}
+		})
+	}
+}
+
+func TestParseRules(t *testing.T) {
+	input := `rules:
+  - name: Forint above 400
+    pair: eur/huf
+    type: threshold
+    above: 400
+  - pair: EUR/USD
+    type: SMA_CROSS
+    period: 50
+`
+	rules, err := ParseRules(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseRules() error = %v", err)
+	}
+
+	if len(rules) != 2 {
+		t.Fatalf("rules = %+v, want 2", rules)
+	}
+	if r := rules[0]; r.Base != "EUR" || r.Target != "HUF" || r.Type != RuleThreshold || *r.Above != 400 || r.Below != nil {
+		t.Errorf("first rule = %+v", r)
+	}
+	if got := rules[1].String(); got != "EUR/USD crosses SMA(50)" {
+		t.Errorf("String() = %q, want a description for an unnamed rule", got)
+	}
+
+	for _, input := range []string{
+		"rules:\n  - pair: EURHUF\n    type: threshold\n    above: 1\n",
+		"rules:\n  - pair: EUR/HUF\n    type: threshold\n",
+		"rules:\n  - pair: EUR/HUF\n    type: change\n    percent: 1\n",
+		"rules:\n  - pair: EUR/HUF\n    type: sma_cross\n    period: 1\n",
+		"rules:\n  - pair: EUR/HUF\n    type: spike\n",
+		"rules:\n  - pair: EUR/HUF\n    type: high_low\n    dayz: 5\n",
+		"rules: []\n",
+	} {
+		if _, err := ParseRules(strings.NewReader(input)); err == nil {
+			t.Errorf("ParseRules(%q) should fail", input)
+		}
+	}
+}
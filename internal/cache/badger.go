@@ -1,16 +1,20 @@
 package cache
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io/fs"
 	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/dgraph-io/badger/v4"
 )
 
 type BadgerCache struct {
-	db *badger.DB
+	db  *badger.DB
+	dir string
 }
 
 func NewBadgerCache(dir string) (*BadgerCache, error) {
@@ -26,7 +30,7 @@ func NewBadgerCache(dir string) (*BadgerCache, error) {
 		return nil, fmt.Errorf("failed to open badger database: %w", err)
 	}
 
-	return &BadgerCache{db: db}, nil
+	return &BadgerCache{db: db, dir: dir}, nil
 }
 
 func (c *BadgerCache) Get(ctx context.Context, key string) ([]byte, error) {
@@ -71,6 +75,59 @@ func (c *BadgerCache) Set(ctx context.Context, key string, value []byte, ttl tim
 	return nil
 }
 
+func (c *BadgerCache) GetMany(ctx context.Context, keys []string) (map[string][]byte, error) {
+	values := make(map[string][]byte, len(keys))
+
+	err := c.db.View(func(txn *badger.Txn) error {
+		for _, key := range keys {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			item, err := txn.Get([]byte(key))
+			if err == badger.ErrKeyNotFound {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			value, err := item.ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			values[key] = value
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get values: %w", err)
+	}
+
+	return values, nil
+}
+
+func (c *BadgerCache) SetMany(ctx context.Context, items []Item) error {
+	wb := c.db.NewWriteBatch()
+	for _, item := range items {
+		entry := badger.NewEntry([]byte(item.Key), item.Value)
+		if item.TTL > 0 {
+			entry = entry.WithTTL(item.TTL)
+		}
+		if err := wb.SetEntry(entry); err != nil {
+			wb.Cancel()
+			return fmt.Errorf("failed to set values: %w", err)
+		}
+	}
+
+	if err := wb.Flush(); err != nil {
+		return fmt.Errorf("failed to set values: %w", err)
+	}
+
+	return nil
+}
+
 func (c *BadgerCache) Delete(ctx context.Context, key string) error {
 	err := c.db.Update(func(txn *badger.Txn) error {
 		return txn.Delete([]byte(key))
@@ -98,3 +155,142 @@ func (c *BadgerCache) Close() error {
 	}
 	return nil
 }
+
+func (c *BadgerCache) Iterate(ctx context.Context, prefix string, fn func(Entry) error) error {
+	return c.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.Prefix = []byte(prefix)
+
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		for it.Seek(opts.Prefix); it.ValidForPrefix(opts.Prefix); it.Next() {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			item := it.Item()
+			value, err := item.ValueCopy(nil)
+			if err != nil {
+				return fmt.Errorf("failed to read value for %s: %w", item.Key(), err)
+			}
+
+			entry := Entry{
+				Key:   string(item.KeyCopy(nil)),
+				Value: value,
+				Size:  item.EstimatedSize(),
+			}
+			if expiresAt := item.ExpiresAt(); expiresAt > 0 {
+				entry.ExpiresAt = time.Unix(int64(expiresAt), 0)
+			}
+
+			if err := fn(entry); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (c *BadgerCache) Stats(ctx context.Context) (Stats, error) {
+	var stats Stats
+
+	err := c.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		for it.Rewind(); it.Valid(); it.Next() {
+			stats.Entries++
+		}
+		return nil
+	})
+	if err != nil {
+		return Stats{}, fmt.Errorf("failed to count entries: %w", err)
+	}
+
+	err = filepath.WalkDir(c.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		stats.Size += diskUsage(info)
+		return nil
+	})
+	if err != nil {
+		return Stats{}, fmt.Errorf("failed to measure cache directory: %w", err)
+	}
+
+	return stats, nil
+}
+
+func (c *BadgerCache) PurgeExpired(ctx context.Context) (int, error) {
+	now := uint64(time.Now().Unix())
+	var expired [][]byte
+
+	err := c.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+		opts.AllVersions = true
+
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		var lastKey []byte
+		for it.Rewind(); it.Valid(); it.Next() {
+			item := it.Item()
+			if bytes.Equal(item.Key(), lastKey) {
+				continue
+			}
+			lastKey = item.KeyCopy(nil)
+
+			if expiresAt := item.ExpiresAt(); expiresAt > 0 && expiresAt <= now {
+				expired = append(expired, lastKey)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to scan expired entries: %w", err)
+	}
+
+	if len(expired) > 0 {
+		wb := c.db.NewWriteBatch()
+		for _, key := range expired {
+			if err := wb.Delete(key); err != nil {
+				wb.Cancel()
+				return 0, fmt.Errorf("failed to purge expired entries: %w", err)
+			}
+		}
+		if err := wb.Flush(); err != nil {
+			return 0, fmt.Errorf("failed to purge expired entries: %w", err)
+		}
+	}
+
+	if err := c.db.Flatten(1); err != nil {
+		return len(expired), fmt.Errorf("failed to compact cache: %w", err)
+	}
+	for {
+		if err := c.db.RunValueLogGC(0.5); err != nil {
+			break
+		}
+	}
+
+	return len(expired), nil
+}
+
+func (c *BadgerCache) Verify(ctx context.Context) error {
+	if err := c.db.VerifyChecksum(); err != nil {
+		return fmt.Errorf("cache checksum verification failed: %w", err)
+	}
+	return nil
+}
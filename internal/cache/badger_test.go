@@ -199,3 +199,99 @@ func TestBadgerCache_CreateDirectory(t *testing.T) {
 		t.Error("Directory was not created")
 	}
 }
+
+func TestBadgerCache_Iterate(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := NewBadgerCache(dir)
+	if err != nil {
+		t.Fatalf("NewBadgerCache() error = %v", err)
+	}
+	defer cache.Close()
+
+	ctx := context.Background()
+	cache.Set(ctx, "a:1", []byte("one"), 0)
+	cache.Set(ctx, "a:2", []byte("two"), time.Hour)
+	cache.Set(ctx, "b:1", []byte("three"), 0)
+
+	var keys []string
+	err = cache.Iterate(ctx, "a:", func(entry Entry) error {
+		keys = append(keys, entry.Key)
+		if entry.Key == "a:2" && entry.ExpiresAt.IsZero() {
+			t.Error("Expected expiration for a:2")
+		}
+		if entry.Key == "a:1" && string(entry.Value) != "one" {
+			t.Errorf("Value = %s, want one", entry.Value)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Iterate() error = %v", err)
+	}
+
+	if len(keys) != 2 || keys[0] != "a:1" || keys[1] != "a:2" {
+		t.Errorf("Iterate() keys = %v, want [a:1 a:2]", keys)
+	}
+}
+
+func TestBadgerCache_GetManySetMany(t *testing.T) {
+	cache, err := NewBadgerCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewBadgerCache() error = %v", err)
+	}
+	defer cache.Close()
+
+	ctx := context.Background()
+	err = cache.SetMany(ctx, []Item{
+		{Key: "a:1", Value: []byte("one")},
+		{Key: "a:2", Value: []byte("two"), TTL: time.Hour},
+	})
+	if err != nil {
+		t.Fatalf("SetMany() error = %v", err)
+	}
+
+	values, err := cache.GetMany(ctx, []string{"a:1", "a:2", "a:3"})
+	if err != nil {
+		t.Fatalf("GetMany() error = %v", err)
+	}
+	if len(values) != 2 || string(values["a:1"]) != "one" || string(values["a:2"]) != "two" {
+		t.Errorf("GetMany() = %q, want a:1 and a:2 without the missing key", values)
+	}
+}
+
+func TestBadgerCache_StatsAndPurge(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := NewBadgerCache(dir)
+	if err != nil {
+		t.Fatalf("NewBadgerCache() error = %v", err)
+	}
+	defer cache.Close()
+
+	ctx := context.Background()
+	cache.Set(ctx, "key1", []byte("value1"), 0)
+	cache.Set(ctx, "key2", []byte("value2"), 1*time.Second)
+
+	stats, err := cache.Stats(ctx)
+	if err != nil {
+		t.Fatalf("Stats() error = %v", err)
+	}
+	if stats.Entries != 2 {
+		t.Errorf("Entries = %d, want 2", stats.Entries)
+	}
+	if stats.Size <= 0 {
+		t.Errorf("Size = %d, want > 0", stats.Size)
+	}
+
+	time.Sleep(2 * time.Second)
+
+	purged, err := cache.PurgeExpired(ctx)
+	if err != nil {
+		t.Fatalf("PurgeExpired() error = %v", err)
+	}
+	if purged != 1 {
+		t.Errorf("PurgeExpired() = %d, want 1", purged)
+	}
+
+	if err := cache.Verify(ctx); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+}
@@ -17,6 +17,45 @@ type Cache interface {
 	Close() error
 }
 
+type Entry struct {
+	Key       string
+	Value     []byte
+	Size      int64
+	ExpiresAt time.Time
+}
+
+type Stats struct {
+	Entries int
+	Size    int64
+}
+
+type Inspector interface {
+	Iterate(ctx context.Context, prefix string, fn func(Entry) error) error
+
+	Stats(ctx context.Context) (Stats, error)
+
+	PurgeExpired(ctx context.Context) (int, error)
+
+	Verify(ctx context.Context) error
+}
+
+// Item is a value to store with SetMany.
+type Item struct {
+	Key   string
+	Value []byte
+	TTL   time.Duration
+}
+
+// Batcher reads and writes many keys in one transaction, so that a long date
+// range costs one round trip instead of one per key.
+type Batcher interface {
+	// GetMany returns the values of the keys that are present; missing and
+	// expired keys are left out.
+	GetMany(ctx context.Context, keys []string) (map[string][]byte, error)
+
+	SetMany(ctx context.Context, items []Item) error
+}
+
 type ErrCacheMiss struct {
 	Key string
 }
@@ -2,6 +2,8 @@ package cache
 
 import (
 	"context"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 )
@@ -62,6 +64,38 @@ func (c *MemoryCache) Set(ctx context.Context, key string, value []byte, ttl tim
 	return nil
 }
 
+func (c *MemoryCache) GetMany(ctx context.Context, keys []string) (map[string][]byte, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	now := time.Now()
+	values := make(map[string][]byte, len(keys))
+	for _, key := range keys {
+		entry, exists := c.data[key]
+		if !exists || (!entry.expiration.IsZero() && now.After(entry.expiration)) {
+			continue
+		}
+		values[key] = entry.value
+	}
+
+	return values, nil
+}
+
+func (c *MemoryCache) SetMany(ctx context.Context, items []Item) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for _, item := range items {
+		entry := &cacheEntry{value: item.Value}
+		if item.TTL > 0 {
+			entry.expiration = now.Add(item.TTL)
+		}
+		c.data[item.Key] = entry
+	}
+	return nil
+}
+
 func (c *MemoryCache) Delete(ctx context.Context, key string) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -100,14 +134,77 @@ func (c *MemoryCache) cleanupExpired() {
 	}
 }
 
-func (c *MemoryCache) removeExpired() {
+func (c *MemoryCache) removeExpired() int {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	removed := 0
 	now := time.Now()
 	for key, entry := range c.data {
 		if !entry.expiration.IsZero() && now.After(entry.expiration) {
 			delete(c.data, key)
+			removed++
+		}
+	}
+	return removed
+}
+
+func (c *MemoryCache) Iterate(ctx context.Context, prefix string, fn func(Entry) error) error {
+	c.mu.RLock()
+	now := time.Now()
+	keys := make([]string, 0, len(c.data))
+	entries := make(map[string]Entry, len(c.data))
+	for key, entry := range c.data {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		if !entry.expiration.IsZero() && now.After(entry.expiration) {
+			continue
+		}
+		keys = append(keys, key)
+		entries[key] = Entry{
+			Key:       key,
+			Value:     entry.value,
+			Size:      int64(len(key) + len(entry.value)),
+			ExpiresAt: entry.expiration,
+		}
+	}
+	c.mu.RUnlock()
+
+	sort.Strings(keys)
+	for _, key := range keys {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(entries[key]); err != nil {
+			return err
 		}
 	}
+
+	return nil
+}
+
+func (c *MemoryCache) Stats(ctx context.Context) (Stats, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var stats Stats
+	now := time.Now()
+	for key, entry := range c.data {
+		if !entry.expiration.IsZero() && now.After(entry.expiration) {
+			continue
+		}
+		stats.Entries++
+		stats.Size += int64(len(key) + len(entry.value))
+	}
+
+	return stats, nil
+}
+
+func (c *MemoryCache) PurgeExpired(ctx context.Context) (int, error) {
+	return c.removeExpired(), nil
+}
+
+func (c *MemoryCache) Verify(ctx context.Context) error {
+	return nil
 }
@@ -168,3 +168,83 @@ func TestMemoryCache_ConcurrentAccess(t *testing.T) {
 		t.Errorf("Get() error after concurrent writes = %v", err)
 	}
 }
+
+func TestMemoryCache_IterateAndStats(t *testing.T) {
+	cache := NewMemoryCache()
+	defer cache.Close()
+
+	ctx := context.Background()
+	cache.Set(ctx, "a:2", []byte("two"), 0)
+	cache.Set(ctx, "a:1", []byte("one"), 0)
+	cache.Set(ctx, "b:1", []byte("three"), 0)
+	cache.Set(ctx, "a:0", []byte("expired"), time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+
+	var keys []string
+	err := cache.Iterate(ctx, "a:", func(entry Entry) error {
+		keys = append(keys, entry.Key)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Iterate() error = %v", err)
+	}
+
+	if len(keys) != 2 || keys[0] != "a:1" || keys[1] != "a:2" {
+		t.Errorf("Iterate() keys = %v, want [a:1 a:2]", keys)
+	}
+
+	stats, err := cache.Stats(ctx)
+	if err != nil {
+		t.Fatalf("Stats() error = %v", err)
+	}
+	if stats.Entries != 3 {
+		t.Errorf("Entries = %d, want 3", stats.Entries)
+	}
+	if stats.Size != int64(len("a:2two")+len("a:1one")+len("b:1three")) {
+		t.Errorf("Size = %d, want %d", stats.Size, len("a:2two")+len("a:1one")+len("b:1three"))
+	}
+}
+
+func TestMemoryCache_GetManySetMany(t *testing.T) {
+	cache := NewMemoryCache()
+	defer cache.Close()
+
+	ctx := context.Background()
+	cache.SetMany(ctx, []Item{
+		{Key: "a:1", Value: []byte("one")},
+		{Key: "a:2", Value: []byte("two"), TTL: time.Millisecond},
+	})
+	time.Sleep(10 * time.Millisecond)
+
+	values, err := cache.GetMany(ctx, []string{"a:1", "a:2", "a:3"})
+	if err != nil {
+		t.Fatalf("GetMany() error = %v", err)
+	}
+	if len(values) != 1 || string(values["a:1"]) != "one" {
+		t.Errorf("GetMany() = %q, want only a:1", values)
+	}
+}
+
+func TestMemoryCache_PurgeExpired(t *testing.T) {
+	cache := NewMemoryCache()
+	defer cache.Close()
+
+	ctx := context.Background()
+	cache.Set(ctx, "short", []byte("value"), 50*time.Millisecond)
+	cache.Set(ctx, "long", []byte("value"), 0)
+
+	time.Sleep(100 * time.Millisecond)
+
+	purged, err := cache.PurgeExpired(ctx)
+	if err != nil {
+		t.Fatalf("PurgeExpired() error = %v", err)
+	}
+	if purged != 1 {
+		t.Errorf("PurgeExpired() = %d, want 1", purged)
+	}
+
+	stats, _ := cache.Stats(ctx)
+	if stats.Entries != 1 {
+		t.Errorf("Entries = %d, want 1", stats.Entries)
+	}
+}
@@ -0,0 +1,149 @@
+package calendar
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Calendar decides which days a fixing is expected on.
+type Calendar interface {
+	Name() string
+	IsBusinessDay(date time.Time) bool
+}
+
+type weekends struct{}
+
+// Weekends treats Monday to Friday as business days.
+func Weekends() Calendar {
+	return weekends{}
+}
+
+func (weekends) Name() string {
+	return "weekends"
+}
+
+func (weekends) IsBusinessDay(date time.Time) bool {
+	day := date.Weekday()
+	return day != time.Saturday && day != time.Sunday
+}
+
+type everyDay struct{}
+
+// EveryDay expects a fixing on every calendar day.
+func EveryDay() Calendar {
+	return everyDay{}
+}
+
+func (everyDay) Name() string {
+	return "none"
+}
+
+func (everyDay) IsBusinessDay(time.Time) bool {
+	return true
+}
+
+type target2 struct{}
+
+// TARGET2 is the closing calendar of the euro area payment system, which the
+// ECB reference rates follow: weekends, New Year's Day, Good Friday, Easter
+// Monday, 1 May, and 25 and 26 December.
+func TARGET2() Calendar {
+	return target2{}
+}
+
+func (target2) Name() string {
+	return "target2"
+}
+
+func (target2) IsBusinessDay(date time.Time) bool {
+	if !Weekends().IsBusinessDay(date) {
+		return false
+	}
+
+	month, day := date.Month(), date.Day()
+	switch {
+	case month == time.January && day == 1,
+		month == time.May && day == 1,
+		month == time.December && (day == 25 || day == 26):
+		return false
+	}
+
+	easter := Easter(date.Year())
+	date = truncateDay(date)
+	return !date.Equal(easter.AddDate(0, 0, -2)) && !date.Equal(easter.AddDate(0, 0, 1))
+}
+
+type custom struct {
+	base     Calendar
+	holidays map[time.Time]bool
+}
+
+// WithHolidays closes base on the given extra days.
+func WithHolidays(base Calendar, holidays []time.Time) Calendar {
+	if len(holidays) == 0 {
+		return base
+	}
+
+	closed := make(map[time.Time]bool, len(holidays))
+	for _, day := range holidays {
+		closed[truncateDay(day)] = true
+	}
+	return custom{base: base, holidays: closed}
+}
+
+func (c custom) Name() string {
+	return c.base.Name() + "+custom"
+}
+
+func (c custom) IsBusinessDay(date time.Time) bool {
+	return !c.holidays[truncateDay(date)] && c.base.IsBusinessDay(date)
+}
+
+// Parse returns the named calendar: target2, weekends or none.
+func Parse(name string) (Calendar, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "target2":
+		return TARGET2(), nil
+	case "weekends":
+		return Weekends(), nil
+	case "none":
+		return EveryDay(), nil
+	default:
+		return nil, fmt.Errorf("unknown calendar: %s (use 'target2', 'weekends' or 'none')", name)
+	}
+}
+
+// BusinessDays lists the business days between start and end, inclusive.
+func BusinessDays(cal Calendar, start, end time.Time) []time.Time {
+	var days []time.Time
+	for date := truncateDay(start); !date.After(truncateDay(end)); date = date.AddDate(0, 0, 1) {
+		if cal.IsBusinessDay(date) {
+			days = append(days, date)
+		}
+	}
+	return days
+}
+
+// Easter returns Easter Sunday of the given year in the Gregorian calendar.
+func Easter(year int) time.Time {
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := b / 4
+	e := b % 4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,78 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+)
+
+func day(s string) time.Time {
+	d, _ := time.Parse("2006-01-02", s)
+	return d
+}
+
+func TestEaster(t *testing.T) {
+	tests := map[int]string{
+		2000: "2000-04-23",
+		2019: "2019-04-21",
+		2024: "2024-03-31",
+		2025: "2025-04-20",
+	}
+
+	for year, want := range tests {
+		if got := Easter(year); !got.Equal(day(want)) {
+			t.Errorf("Easter(%d) = %s, want %s", year, got.Format("2006-01-02"), want)
+		}
+	}
+}
+
+func TestTARGET2(t *testing.T) {
+	tests := []struct {
+		date string
+		want bool
+	}{
+		{"2024-01-01", false},
+		{"2024-01-02", true},
+		{"2024-03-29", false},
+		{"2024-04-01", false},
+		{"2024-04-02", true},
+		{"2024-05-01", false},
+		{"2024-12-24", true},
+		{"2024-12-25", false},
+		{"2024-12-26", false},
+		{"2024-01-06", false},
+	}
+
+	cal := TARGET2()
+	for _, tt := range tests {
+		if got := cal.IsBusinessDay(day(tt.date)); got != tt.want {
+			t.Errorf("IsBusinessDay(%s) = %v, want %v", tt.date, got, tt.want)
+		}
+	}
+}
+
+func TestWithHolidays(t *testing.T) {
+	cal := WithHolidays(Weekends(), []time.Time{day("2024-03-15")})
+
+	if cal.IsBusinessDay(day("2024-03-15")) {
+		t.Error("custom holiday should not be a business day")
+	}
+	if !cal.IsBusinessDay(day("2024-03-14")) {
+		t.Error("weekday without a holiday should be a business day")
+	}
+
+	days := BusinessDays(cal, day("2024-03-11"), day("2024-03-17"))
+	if len(days) != 4 {
+		t.Errorf("BusinessDays() = %d days, want 4", len(days))
+	}
+}
+
+func TestParse(t *testing.T) {
+	for _, name := range []string{"", "target2", "Weekends", "none"} {
+		if _, err := Parse(name); err != nil {
+			t.Errorf("Parse(%q) error = %v", name, err)
+		}
+	}
+	if _, err := Parse("nyse"); err == nil {
+		t.Error("Parse(nyse) should fail")
+	}
+}
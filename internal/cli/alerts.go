@@ -0,0 +1,164 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kaze/xrv/internal/alerts"
+	"github.com/kaze/xrv/internal/config"
+)
+
+var (
+	alertsRules   string
+	alertsNotify  []string
+	alertsDate    string
+	alertsNoCache bool
+)
+
+// exitAlertsTriggered is the exit code of `xrv alerts check` when a rule
+// fired, so that scripts can tell it from a failed check.
+const exitAlertsTriggered = 2
+
+func NewAlertsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "alerts",
+		Short: "Check exchange rates against alert rules",
+		Long:  "Evaluate the alert rules of alerts.rules_file against the latest fixings and notify on the ones that fire",
+	}
+
+	checkCmd := &cobra.Command{
+		Use:   "check",
+		Short: "Evaluate the alert rules once and notify on the ones that fire",
+		Long: `Fetch the latest fixings for every rule in the rules file and evaluate them.
+Rules that fire are sent to each notifier in alerts.notifiers: stdout, file
+(appends to alerts.log_file) or webhook (POSTs JSON to alerts.webhook_url).
+
+A rules file looks like
+
+  rules:
+    - name: Forint above 400
+      pair: EUR/HUF
+      type: threshold
+      above: 400
+    - pair: EUR/HUF
+      type: change
+      percent: 1
+      days: 1
+
+with the types threshold (above, below), change (percent, days), sma_cross
+(period) and high_low (days). Days and periods count fixings.
+
+The command exits with status 2 when a rule fired and 1 when the check
+failed, so it can be run from cron.`,
+		Example: `  xrv alerts check
+  xrv alerts check --rules configs/alerts.yaml --notify stdout,webhook
+  xrv alerts check --date 2024-03-01`,
+		Args: cobra.NoArgs,
+		RunE: runAlertsCheck,
+	}
+	checkCmd.Flags().StringVarP(&alertsRules, "rules", "r", "", "Rules file, defaults to alerts.rules_file")
+	checkCmd.Flags().StringSliceVarP(&alertsNotify, "notify", "n", nil, "Notifiers: stdout, file, webhook, defaults to alerts.notifiers")
+	checkCmd.Flags().StringVarP(&alertsDate, "date", "d", "", "Check as of this date (YYYY-MM-DD), defaults to today")
+	checkCmd.Flags().BoolVar(&alertsNoCache, "no-cache", false, "Disable caching")
+
+	cmd.AddCommand(checkCmd)
+
+	return cmd
+}
+
+func runAlertsCheck(cmd *cobra.Command, args []string) error {
+	cfg, err := currentConfig()
+	if err != nil {
+		return err
+	}
+
+	path := cfg.Alerts.RulesFile
+	if alertsRules != "" {
+		path = alertsRules
+	}
+	rules, err := alerts.LoadRules(path)
+	if err != nil {
+		return err
+	}
+
+	names := cfg.Alerts.Notifiers
+	if cmd.Flags().Changed("notify") {
+		names = alertsNotify
+	}
+	notifier, err := newNotifier(names, cfg.Alerts, cmd.OutOrStdout())
+	if err != nil {
+		return err
+	}
+
+	endDate := time.Now()
+	if alertsDate != "" {
+		endDate, err = time.Parse("2006-01-02", alertsDate)
+		if err != nil {
+			return fmt.Errorf("invalid date format: %w", err)
+		}
+	}
+
+	apiClient, err := newAPIClient(cfg)
+	if err != nil {
+		return err
+	}
+
+	dataCache, err := openCache(cfg)
+	if err != nil {
+		return err
+	}
+	defer dataCache.Close()
+
+	svc := newService(cfg, apiClient, dataCache)
+
+	ctx := context.Background()
+	triggered, err := alerts.Check(ctx, svc, rules, alerts.CheckOptions{
+		EndDate:  endDate,
+		UseCache: cfg.Cache.Enabled && !alertsNoCache,
+		Rebase:   cfg.Cache.Rebase,
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(triggered) == 0 {
+		fmt.Fprintf(cmd.ErrOrStderr(), "No alerts (%d rules checked)\n", len(rules))
+		return nil
+	}
+	if err := notifier.Notify(ctx, triggered); err != nil {
+		return fmt.Errorf("failed to notify: %w", err)
+	}
+
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	return &exitError{code: exitAlertsTriggered, err: fmt.Errorf("%d of %d rules fired", len(triggered), len(rules))}
+}
+
+// newNotifier builds the named notifiers; stdout writes to out.
+func newNotifier(names []string, cfg config.AlertsConfig, out io.Writer) (alerts.Notifier, error) {
+	var notifiers alerts.Notifiers
+	for _, name := range names {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "stdout":
+			notifiers = append(notifiers, alerts.NewWriterNotifier(out))
+		case "file":
+			notifiers = append(notifiers, alerts.NewFileNotifier(cfg.LogFile))
+		case "webhook":
+			if cfg.WebhookURL == "" {
+				return nil, fmt.Errorf("alerts.webhook_url is required by the webhook notifier")
+			}
+			notifiers = append(notifiers, alerts.NewWebhookNotifier(cfg.WebhookURL, cfg.WebhookTimeout))
+		default:
+			return nil, fmt.Errorf("unknown alert notifier %s (use 'stdout', 'file' or 'webhook')", name)
+		}
+	}
+	if len(notifiers) == 0 {
+		return nil, fmt.Errorf("at least one notifier is required")
+	}
+	return notifiers, nil
+}
@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kaze/xrv/internal/alerts"
+	"github.com/kaze/xrv/internal/config"
+)
+
+func TestNewNotifier(t *testing.T) {
+	cfg := config.AlertsConfig{LogFile: t.TempDir() + "/alerts.log", WebhookTimeout: time.Second}
+
+	var out bytes.Buffer
+	notifier, err := newNotifier([]string{"Stdout", "file"}, cfg, &out)
+	if err != nil {
+		t.Fatalf("newNotifier() error = %v", err)
+	}
+	if n, ok := notifier.(alerts.Notifiers); !ok || len(n) != 2 {
+		t.Fatalf("newNotifier() = %#v, want two notifiers", notifier)
+	}
+
+	alert := alerts.Alert{
+		Rule:    alerts.Rule{Name: "Forint above 400", Base: "EUR", Target: "HUF"},
+		Date:    time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		Message: "EUR/HUF at 401.2000 rose above 400",
+	}
+	if err := notifier.Notify(context.Background(), []alerts.Alert{alert}); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "Forint above 400: EUR/HUF") {
+		t.Errorf("stdout = %q", out.String())
+	}
+
+	for _, names := range [][]string{{"webhook"}, {"desktop"}, nil} {
+		if _, err := newNotifier(names, cfg, &out); err == nil {
+			t.Errorf("newNotifier(%v) should fail", names)
+		}
+	}
+}
+
+func TestAlertsCommand(t *testing.T) {
+	check, _, err := NewAlertsCommand().Find([]string{"check"})
+	if err != nil || check.Name() != "check" {
+		t.Fatalf("Find(check) = %v, %v", check, err)
+	}
+	for _, flag := range []string{"rules", "notify", "date", "no-cache"} {
+		if check.Flags().Lookup(flag) == nil {
+			t.Errorf("Flag %s not defined", flag)
+		}
+	}
+}
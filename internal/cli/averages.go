@@ -0,0 +1,216 @@
+package cli
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kaze/xrv/internal/service"
+)
+
+var (
+	averagesBase       string
+	averagesCurrencies string
+	averagesPeriod     string
+	averagesFrom       string
+	averagesTo         string
+	averagesOutput     string
+	averagesNoCache    bool
+)
+
+func NewAveragesCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "averages",
+		Short: "Period average, closing, high and low rates for accounting closes",
+		Long: `Summarize the rates of each target currency per week, month, quarter or
+year: the average over the period's business days, the closing rate on its
+last business day and the highest and lowest rate.
+
+Business days follow calendar.name. A business day without a fixing takes
+the previous one, and fixings on other days are left out. Periods with
+business days before the first or after the last fixing, such as the
+current month, are marked partial.`,
+		Example: `  xrv averages --base EUR --currencies USD,GBP --period month --from 2024-01-01
+  xrv averages -b EUR -c USD --period quarter --from "2 years ago" -o csv`,
+		RunE: runAverages,
+	}
+
+	cmd.Flags().StringVarP(&averagesBase, "base", "b", "", "Base currency, defaults to cli.default_base")
+	cmd.Flags().StringVarP(&averagesCurrencies, "currencies", "c", "", "Target currencies, defaults to cli.default_targets")
+	cmd.Flags().StringVarP(&averagesPeriod, "period", "p", "month", "Period: week, month, quarter, year")
+	cmd.Flags().StringVarP(&averagesFrom, "from", "f", "", "Start date (YYYY-MM-DD) or relative (e.g., '1 year ago')")
+	cmd.Flags().StringVarP(&averagesTo, "to", "t", "", "End date (YYYY-MM-DD), defaults to today")
+	cmd.Flags().StringVarP(&averagesOutput, "output", "o", "table", "Output format: table, json, csv")
+	cmd.Flags().BoolVar(&averagesNoCache, "no-cache", false, "Disable caching")
+
+	return cmd
+}
+
+func runAverages(cmd *cobra.Command, args []string) error {
+	format := strings.ToLower(averagesOutput)
+	if format != "table" && format != "json" && format != "csv" {
+		return fmt.Errorf("unsupported output format: %s (use 'table', 'json' or 'csv')", averagesOutput)
+	}
+
+	period, err := service.ParseInterval(averagesPeriod)
+	if err != nil {
+		return err
+	}
+	if period == service.IntervalDay {
+		return fmt.Errorf("period must be week, month, quarter or year")
+	}
+
+	cfg, err := currentConfig()
+	if err != nil {
+		return err
+	}
+
+	series, err := resolveSeriesArgs(cfg, averagesBase, averagesCurrencies, averagesFrom, averagesTo)
+	if err != nil {
+		return err
+	}
+
+	apiClient, err := newAPIClient(cfg)
+	if err != nil {
+		return err
+	}
+
+	dataCache, err := openCache(cfg)
+	if err != nil {
+		return err
+	}
+	defer dataCache.Close()
+
+	svc := newService(cfg, apiClient, dataCache)
+
+	averages, err := svc.PeriodAverages(context.Background(), service.FetchOptions{
+		Base:      series.Base,
+		Targets:   series.Targets,
+		StartDate: series.StartDate,
+		EndDate:   series.EndDate,
+		UseCache:  cfg.Cache.Enabled && !averagesNoCache,
+		Rebase:    cfg.Cache.Rebase,
+		Interval:  period,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to fetch data: %w", err)
+	}
+
+	out := cmd.OutOrStdout()
+	switch format {
+	case "json":
+		return writeAveragesJSON(out, averages)
+	case "csv":
+		return writeAveragesCSV(out, averages)
+	default:
+		return writeAveragesTable(out, averages)
+	}
+}
+
+func writeAveragesTable(out io.Writer, averages *service.PeriodAverages) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CURRENCY\tPERIOD\tAVERAGE\tCLOSE\tHIGH\tLOW\tDAYS")
+
+	for _, target := range averages.Targets {
+		for _, p := range averages.Rates[target] {
+			days := strconv.Itoa(p.Days)
+			if p.Partial {
+				days += " (partial)"
+			}
+			fmt.Fprintf(w, "%s/%s\t%s\t%.6f\t%.6f\t%.6f\t%.6f\t%s\n",
+				averages.Base, target, averages.Period.Label(p.Start), p.Average, p.Close, p.High, p.Low, days)
+		}
+	}
+
+	return w.Flush()
+}
+
+func writeAveragesJSON(out io.Writer, averages *service.PeriodAverages) error {
+	type periodJSON struct {
+		Period    string  `json:"period"`
+		Start     string  `json:"start"`
+		End       string  `json:"end"`
+		Average   float64 `json:"average"`
+		Close     float64 `json:"close"`
+		CloseDate string  `json:"close_date"`
+		High      float64 `json:"high"`
+		Low       float64 `json:"low"`
+		Days      int     `json:"business_days"`
+		Filled    int     `json:"filled_days"`
+		Partial   bool    `json:"partial"`
+	}
+
+	type targetJSON struct {
+		Currency string       `json:"currency"`
+		Periods  []periodJSON `json:"periods"`
+	}
+
+	type averagesJSON struct {
+		Base    string       `json:"base"`
+		Period  string       `json:"period"`
+		Targets []targetJSON `json:"targets"`
+	}
+
+	payload := averagesJSON{
+		Base:    string(averages.Base),
+		Period:  string(averages.Period),
+		Targets: make([]targetJSON, 0, len(averages.Targets)),
+	}
+	for _, target := range averages.Targets {
+		periods := make([]periodJSON, len(averages.Rates[target]))
+		for i, p := range averages.Rates[target] {
+			periods[i] = periodJSON{
+				Period:    averages.Period.Label(p.Start),
+				Start:     p.Start.Format("2006-01-02"),
+				End:       p.End.Format("2006-01-02"),
+				Average:   p.Average,
+				Close:     p.Close,
+				CloseDate: p.CloseDate.Format("2006-01-02"),
+				High:      p.High,
+				Low:       p.Low,
+				Days:      p.Days,
+				Filled:    p.Filled,
+				Partial:   p.Partial,
+			}
+		}
+		payload.Targets = append(payload.Targets, targetJSON{Currency: string(target), Periods: periods})
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(payload)
+}
+
+func writeAveragesCSV(out io.Writer, averages *service.PeriodAverages) error {
+	w := csv.NewWriter(out)
+	w.Write([]string{"base", "currency", "period", "start", "end", "average", "close", "close_date", "high", "low", "business_days", "partial"})
+
+	for _, target := range averages.Targets {
+		for _, p := range averages.Rates[target] {
+			w.Write([]string{
+				string(averages.Base),
+				string(target),
+				averages.Period.Label(p.Start),
+				p.Start.Format("2006-01-02"),
+				p.End.Format("2006-01-02"),
+				strconv.FormatFloat(p.Average, 'f', 6, 64),
+				strconv.FormatFloat(p.Close, 'f', 6, 64),
+				p.CloseDate.Format("2006-01-02"),
+				strconv.FormatFloat(p.High, 'f', 6, 64),
+				strconv.FormatFloat(p.Low, 'f', 6, 64),
+				strconv.Itoa(p.Days),
+				strconv.FormatBool(p.Partial),
+			})
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
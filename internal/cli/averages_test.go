@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kaze/xrv/internal/domain"
+	"github.com/kaze/xrv/internal/service"
+)
+
+func TestWriteAverages(t *testing.T) {
+	averages := &service.PeriodAverages{
+		Base:    "EUR",
+		Period:  service.IntervalQuarter,
+		Targets: []domain.Currency{"USD"},
+		Rates: map[domain.Currency][]service.PeriodRate{
+			"USD": {{
+				Start:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+				End:       time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC),
+				Average:   1.0866,
+				Close:     1.0811,
+				CloseDate: time.Date(2024, 3, 28, 0, 0, 0, 0, time.UTC),
+				High:      1.0953,
+				Low:       1.0743,
+				Days:      62,
+				Partial:   true,
+			}},
+		},
+	}
+
+	var table bytes.Buffer
+	if err := writeAveragesTable(&table, averages); err != nil {
+		t.Fatalf("writeAveragesTable() error = %v", err)
+	}
+	for _, want := range []string{"EUR/USD", "2024-Q1", "1.086600", "62 (partial)"} {
+		if !strings.Contains(table.String(), want) {
+			t.Errorf("table does not contain %q:\n%s", want, table.String())
+		}
+	}
+
+	var csvOut bytes.Buffer
+	if err := writeAveragesCSV(&csvOut, averages); err != nil {
+		t.Fatalf("writeAveragesCSV() error = %v", err)
+	}
+	want := "EUR,USD,2024-Q1,2024-01-01,2024-03-31,1.086600,1.081100,2024-03-28,1.095300,1.074300,62,true"
+	if lines := strings.Split(strings.TrimSpace(csvOut.String()), "\n"); len(lines) != 2 || lines[1] != want {
+		t.Errorf("writeAveragesCSV() = %q, want a header and %q", csvOut.String(), want)
+	}
+
+	var jsonOut bytes.Buffer
+	if err := writeAveragesJSON(&jsonOut, averages); err != nil {
+		t.Fatalf("writeAveragesJSON() error = %v", err)
+	}
+	var decoded struct {
+		Period  string `json:"period"`
+		Targets []struct {
+			Periods []struct {
+				Period    string `json:"period"`
+				CloseDate string `json:"close_date"`
+			} `json:"periods"`
+		} `json:"targets"`
+	}
+	if err := json.Unmarshal(jsonOut.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if decoded.Period != "quarter" || decoded.Targets[0].Periods[0].Period != "2024-Q1" || decoded.Targets[0].Periods[0].CloseDate != "2024-03-28" {
+		t.Errorf("decoded = %+v", decoded)
+	}
+}
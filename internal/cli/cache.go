@@ -0,0 +1,311 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kaze/xrv/internal/domain"
+	"github.com/kaze/xrv/internal/service"
+)
+
+var (
+	cacheBase   string
+	cacheTarget string
+	cacheFrom   string
+	cacheTo     string
+	cacheRepair bool
+	cacheKeys   bool
+	cacheAll    bool
+)
+
+func NewCacheCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Inspect and manage the rate cache",
+		Long:  "Inspect, evict and verify the exchange rate data stored in the local cache",
+	}
+
+	statsCmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show entry count and size of the cache",
+		Args:  cobra.NoArgs,
+		RunE:  runCacheStats,
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List cached series with their date ranges and TTL",
+		Args:  cobra.NoArgs,
+		RunE:  runCacheList,
+	}
+	addCacheFilterFlags(listCmd)
+	listCmd.Flags().BoolVar(&cacheKeys, "keys", false, "List individual cache keys instead of per-series summaries")
+
+	clearCmd := &cobra.Command{
+		Use:   "clear",
+		Short: "Remove cached entries (those matching the filters, or all with --all)",
+		Args:  cobra.NoArgs,
+		RunE:  runCacheClear,
+	}
+	addCacheFilterFlags(clearCmd)
+	clearCmd.Flags().BoolVar(&cacheAll, "all", false, "Remove every entry; required when no filter is given")
+
+	purgeCmd := &cobra.Command{
+		Use:   "purge-expired",
+		Short: "Remove expired entries and reclaim disk space",
+		Args:  cobra.NoArgs,
+		RunE:  runCachePurgeExpired,
+	}
+
+	verifyCmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Check cache integrity and report undecodable entries",
+		Args:  cobra.NoArgs,
+		RunE:  runCacheVerify,
+	}
+	verifyCmd.Flags().BoolVar(&cacheRepair, "repair", false, "Delete entries that cannot be decoded")
+
+	cmd.AddCommand(statsCmd, listCmd, clearCmd, purgeCmd, verifyCmd)
+
+	return cmd
+}
+
+func addCacheFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&cacheBase, "base", "b", "", "Only entries for this base currency")
+	cmd.Flags().StringVar(&cacheTarget, "target", "", "Only entries for this target currency")
+	cmd.Flags().StringVarP(&cacheFrom, "from", "f", "", "Only entries dated on or after this date")
+	cmd.Flags().StringVarP(&cacheTo, "to", "t", "", "Only entries dated on or before this date")
+}
+
+func openCacheService() (*service.Service, func(), error) {
+	cfg, err := currentConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+	if !cfg.Cache.Enabled {
+		return nil, nil, fmt.Errorf("the cache is disabled (cache.enabled is false)")
+	}
+
+	dataCache, err := openCache(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return newService(cfg, nil, dataCache), func() { dataCache.Close() }, nil
+}
+
+func cacheFilterFromFlags() (service.CacheFilter, error) {
+	filter := service.CacheFilter{
+		Base:   domain.Currency(strings.ToUpper(strings.TrimSpace(cacheBase))),
+		Target: domain.Currency(strings.ToUpper(strings.TrimSpace(cacheTarget))),
+	}
+
+	now := time.Now()
+	if cacheFrom != "" {
+		from, err := parseDate(cacheFrom, now)
+		if err != nil {
+			return filter, fmt.Errorf("invalid --from date: %w", err)
+		}
+		filter.From = from
+	}
+	if cacheTo != "" {
+		to, err := parseDate(cacheTo, now)
+		if err != nil {
+			return filter, fmt.Errorf("invalid --to date: %w", err)
+		}
+		filter.To = to
+	}
+
+	return filter, nil
+}
+
+func runCacheStats(cmd *cobra.Command, args []string) error {
+	svc, closeCache, err := openCacheService()
+	if err != nil {
+		return err
+	}
+	defer closeCache()
+
+	stats, err := svc.CacheStats(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to read cache stats: %w", err)
+	}
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Location: %s\n", cfg.Cache.Directory)
+	fmt.Fprintf(out, "Entries:  %d\n", stats.Entries)
+	fmt.Fprintf(out, "Size:     %s\n", formatBytes(stats.Size))
+
+	return nil
+}
+
+func runCacheList(cmd *cobra.Command, args []string) error {
+	filter, err := cacheFilterFromFlags()
+	if err != nil {
+		return err
+	}
+
+	svc, closeCache, err := openCacheService()
+	if err != nil {
+		return err
+	}
+	defer closeCache()
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+
+	if cacheKeys {
+		entries, err := svc.CacheEntries(context.Background(), filter)
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), "No cached entries")
+			return nil
+		}
+
+		fmt.Fprintln(w, "KEY\tRATE\tSOURCE\tSIZE\tTTL")
+		for _, e := range entries {
+			rate := fmt.Sprintf("%.6f", e.Rate)
+			if e.Missing {
+				rate = "-"
+			}
+			source := e.Source
+			if source == "" {
+				source = "-"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", e.Key, rate, source, formatBytes(e.Size), formatTTL(e.ExpiresAt))
+		}
+		return w.Flush()
+	}
+
+	series, err := svc.CacheSeries(context.Background(), filter)
+	if err != nil {
+		return err
+	}
+	if len(series) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "No cached entries")
+		return nil
+	}
+
+	fmt.Fprintln(w, "BASE\tTARGET\tFROM\tTO\tRATES\tNO FIXING\tSIZE\tTTL")
+	for _, e := range series {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%d\t%s\t%s\n",
+			e.Base,
+			e.Target,
+			e.StartDate.Format("2006-01-02"),
+			e.EndDate.Format("2006-01-02"),
+			e.Observations,
+			e.Missing,
+			formatBytes(e.Size),
+			formatTTL(e.ExpiresAt),
+		)
+	}
+
+	return w.Flush()
+}
+
+func runCacheClear(cmd *cobra.Command, args []string) error {
+	filter, err := cacheFilterFromFlags()
+	if err != nil {
+		return err
+	}
+	switch {
+	case filter.IsEmpty() && !cacheAll:
+		return fmt.Errorf("no filter given: pass --all to remove every entry, or narrow it with --base, --target, --from or --to")
+	case !filter.IsEmpty() && cacheAll:
+		return fmt.Errorf("--all cannot be combined with --base, --target, --from or --to")
+	}
+
+	svc, closeCache, err := openCacheService()
+	if err != nil {
+		return err
+	}
+	defer closeCache()
+
+	removed, err := svc.EvictCache(context.Background(), filter)
+	if err != nil {
+		return fmt.Errorf("failed to clear cache: %w", err)
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Removed %d entries\n", removed)
+	return nil
+}
+
+func runCachePurgeExpired(cmd *cobra.Command, args []string) error {
+	svc, closeCache, err := openCacheService()
+	if err != nil {
+		return err
+	}
+	defer closeCache()
+
+	purged, err := svc.PurgeExpiredCache(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to purge expired entries: %w", err)
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Purged %d expired entries\n", purged)
+	return nil
+}
+
+func runCacheVerify(cmd *cobra.Command, args []string) error {
+	svc, closeCache, err := openCacheService()
+	if err != nil {
+		return err
+	}
+	defer closeCache()
+
+	report, err := svc.VerifyCache(context.Background(), cacheRepair)
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Checked: %d\n", report.Checked)
+	fmt.Fprintf(out, "Valid:   %d\n", report.Valid)
+	fmt.Fprintf(out, "Corrupt: %d\n", len(report.Corrupt))
+	for _, key := range report.Corrupt {
+		fmt.Fprintf(out, "  %s\n", key)
+	}
+	if cacheRepair {
+		fmt.Fprintf(out, "Removed: %d\n", report.Removed)
+	}
+
+	if len(report.Corrupt) > 0 && !cacheRepair {
+		return fmt.Errorf("found %d corrupt entries (run with --repair to remove them)", len(report.Corrupt))
+	}
+
+	return nil
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+func formatTTL(expiresAt time.Time) string {
+	if expiresAt.IsZero() {
+		return "never"
+	}
+
+	remaining := time.Until(expiresAt)
+	if remaining <= 0 {
+		return "expired"
+	}
+
+	return remaining.Round(time.Second).String()
+}
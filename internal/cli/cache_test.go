@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestOpenCacheService_Disabled(t *testing.T) {
+	previous := cfg
+	t.Cleanup(func() { cfg = previous })
+
+	cfg = testConfig(t)
+	cfg.Cache.Enabled = false
+
+	if _, _, err := openCacheService(); err == nil || !strings.Contains(err.Error(), "disabled") {
+		t.Errorf("openCacheService() error = %v, want the cache reported as disabled", err)
+	}
+}
+
+func TestRunCacheClear_RequiresAllWithoutFilter(t *testing.T) {
+	previous := cfg
+	t.Cleanup(func() {
+		cfg = previous
+		cacheBase, cacheAll = "", false
+	})
+
+	cfg = testConfig(t)
+	cfg.Cache.Type = "memory"
+	cmd := NewCacheCommand()
+	cmd.SetOut(io.Discard)
+
+	if err := runCacheClear(cmd, nil); err == nil || !strings.Contains(err.Error(), "--all") {
+		t.Errorf("runCacheClear() error = %v, want --all required", err)
+	}
+
+	cacheBase, cacheAll = "USD", true
+	if err := runCacheClear(cmd, nil); err == nil {
+		t.Error("runCacheClear() should reject --all with a filter")
+	}
+
+	cacheBase = ""
+	if err := runCacheClear(cmd, nil); err != nil {
+		t.Errorf("runCacheClear() with --all error = %v", err)
+	}
+}
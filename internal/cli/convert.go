@@ -0,0 +1,267 @@
+package cli
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kaze/xrv/internal/domain"
+	"github.com/kaze/xrv/internal/service"
+)
+
+var (
+	convertDate     string
+	convertOutput   string
+	convertLookback int
+	convertNoCache  bool
+)
+
+func NewConvertCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "convert AMOUNT FROM TO...",
+		Short: "Convert an amount between currencies on a given date",
+		Long: `Convert an amount at the reference rate of a given date.
+
+When there is no fixing on that date (weekends, holidays, or today before
+publication) the previous available fixing is used and reported.`,
+		Example: `  xrv convert 12430.50 USD HUF --date 2023-03-15
+  xrv convert 100 EUR USD,GBP,JPY -o json`,
+		Args: cobra.MinimumNArgs(3),
+		RunE: runConvert,
+	}
+
+	cmd.Flags().StringVarP(&convertDate, "date", "d", "", "Conversion date (YYYY-MM-DD) or relative (e.g., '3 days ago'), defaults to today")
+	cmd.Flags().StringVarP(&convertOutput, "output", "o", "table", "Output format: table, json, csv")
+	cmd.Flags().IntVar(&convertLookback, "lookback", 7, "Business days to search back for the previous available fixing")
+	cmd.Flags().BoolVar(&convertNoCache, "no-cache", false, "Disable caching")
+
+	return cmd
+}
+
+func runConvert(cmd *cobra.Command, args []string) error {
+	amount, err := parseAmount(args[0])
+	if err != nil {
+		return err
+	}
+
+	from := domain.Currency(strings.ToUpper(strings.TrimSpace(args[1])))
+	targets := parseCurrencyList(args[2:])
+	if len(targets) == 0 {
+		return fmt.Errorf("at least one target currency is required")
+	}
+
+	date := time.Now()
+	if convertDate != "" {
+		date, err = parseDate(convertDate, date)
+		if err != nil {
+			return fmt.Errorf("invalid date: %w", err)
+		}
+	}
+
+	format := strings.ToLower(convertOutput)
+	if format != "table" && format != "json" && format != "csv" {
+		return fmt.Errorf("unsupported output format: %s (use 'table', 'json' or 'csv')", convertOutput)
+	}
+
+	cfg, err := currentConfig()
+	if err != nil {
+		return err
+	}
+
+	lookback := cfg.Convert.LookbackDays
+	if cmd.Flags().Changed("lookback") {
+		lookback = convertLookback
+	}
+
+	apiClient, err := newAPIClient(cfg)
+	if err != nil {
+		return err
+	}
+
+	dataCache, err := openCache(cfg)
+	if err != nil {
+		return err
+	}
+	defer dataCache.Close()
+
+	svc := newService(cfg, apiClient, dataCache)
+
+	result, err := svc.Convert(context.Background(), service.ConvertOptions{
+		Amount:       amount,
+		From:         from,
+		Targets:      targets,
+		Date:         date,
+		LookbackDays: lookback,
+		UseCache:     cfg.Cache.Enabled && !convertNoCache,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to convert: %w", err)
+	}
+
+	out := cmd.OutOrStdout()
+	switch format {
+	case "json":
+		err = writeConversionJSON(out, result)
+	case "csv":
+		err = writeConversionCSV(out, result)
+	default:
+		err = writeConversionTable(out, result)
+	}
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, c := range result.Conversions {
+		if c.Err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "%s: %v\n", c.Target, c.Err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d targets could not be converted", failed, len(result.Conversions))
+	}
+
+	return nil
+}
+
+func parseAmount(value string) (float64, error) {
+	cleaned := strings.ReplaceAll(strings.ReplaceAll(strings.TrimSpace(value), ",", ""), "_", "")
+	amount, err := strconv.ParseFloat(cleaned, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", value)
+	}
+	return amount, nil
+}
+
+func parseCurrencyList(values []string) []domain.Currency {
+	var currencies []domain.Currency
+	seen := make(map[domain.Currency]bool)
+
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			currency := domain.Currency(strings.ToUpper(strings.TrimSpace(part)))
+			if currency == "" || seen[currency] {
+				continue
+			}
+			seen[currency] = true
+			currencies = append(currencies, currency)
+		}
+	}
+
+	return currencies
+}
+
+func writeConversionTable(out io.Writer, result *service.ConversionResult) error {
+	fmt.Fprintf(out, "%s %s on %s\n\n",
+		strconv.FormatFloat(result.Amount, 'f', -1, 64), result.From, result.Date.Format("2006-01-02"))
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TARGET\tAMOUNT\tRATE\tRATE DATE")
+	for _, c := range result.Conversions {
+		if c.Err != nil {
+			fmt.Fprintf(w, "%s\t-\t-\t-\n", c.Target)
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%.2f\t%.6f\t%s\n", c.Target, c.Amount, c.Rate, c.RateDate.Format("2006-01-02"))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	noted := false
+	for _, c := range result.Conversions {
+		if !c.Fallback {
+			continue
+		}
+		if !noted {
+			fmt.Fprintln(out)
+			noted = true
+		}
+		fmt.Fprintf(out, "Note: no %s/%s fixing on %s, used the previous available fixing from %s\n",
+			result.From, c.Target, result.Date.Format("2006-01-02"), c.RateDate.Format("2006-01-02"))
+	}
+
+	return nil
+}
+
+func writeConversionJSON(out io.Writer, result *service.ConversionResult) error {
+	type conversionJSON struct {
+		Target   string  `json:"target"`
+		Amount   float64 `json:"amount"`
+		Rate     float64 `json:"rate"`
+		RateDate string  `json:"rate_date,omitempty"`
+		Fallback bool    `json:"fallback"`
+		Error    string  `json:"error,omitempty"`
+	}
+
+	type resultJSON struct {
+		Amount      float64          `json:"amount"`
+		From        string           `json:"from"`
+		Date        string           `json:"date"`
+		Conversions []conversionJSON `json:"conversions"`
+	}
+
+	payload := resultJSON{
+		Amount:      result.Amount,
+		From:        string(result.From),
+		Date:        result.Date.Format("2006-01-02"),
+		Conversions: make([]conversionJSON, len(result.Conversions)),
+	}
+	for i, c := range result.Conversions {
+		if c.Err != nil {
+			payload.Conversions[i] = conversionJSON{Target: string(c.Target), Error: c.Err.Error()}
+			continue
+		}
+		payload.Conversions[i] = conversionJSON{
+			Target:   string(c.Target),
+			Amount:   c.Amount,
+			Rate:     c.Rate,
+			RateDate: c.RateDate.Format("2006-01-02"),
+			Fallback: c.Fallback,
+		}
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(payload)
+}
+
+func writeConversionCSV(out io.Writer, result *service.ConversionResult) error {
+	w := csv.NewWriter(out)
+	w.Write([]string{"date", "amount", "from", "target", "converted", "rate", "rate_date", "fallback"})
+
+	for _, c := range result.Conversions {
+		if c.Err != nil {
+			w.Write([]string{
+				result.Date.Format("2006-01-02"),
+				strconv.FormatFloat(result.Amount, 'f', -1, 64),
+				string(result.From),
+				string(c.Target),
+				"", "", "", "",
+			})
+			continue
+		}
+		w.Write([]string{
+			result.Date.Format("2006-01-02"),
+			strconv.FormatFloat(result.Amount, 'f', -1, 64),
+			string(result.From),
+			string(c.Target),
+			strconv.FormatFloat(c.Amount, 'f', 2, 64),
+			strconv.FormatFloat(c.Rate, 'f', -1, 64),
+			c.RateDate.Format("2006-01-02"),
+			strconv.FormatBool(c.Fallback),
+		})
+	}
+
+	w.Flush()
+	return w.Error()
+}
@@ -0,0 +1,251 @@
+package cli
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kaze/xrv/internal/domain"
+	"github.com/kaze/xrv/internal/service"
+)
+
+var (
+	convertFileTo          string
+	convertFileOut         string
+	convertFileRule        string
+	convertFileLookback    int
+	convertFileDateCol     string
+	convertFileAmountCol   string
+	convertFileCurrencyCol string
+	convertFileNoCache     bool
+)
+
+func NewConvertFileCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "convert-file LEDGER.csv",
+		Short: "Convert a CSV of transactions to a reporting currency",
+		Long: `Convert every row of a ledger CSV to a reporting currency at the rate of the
+row's date.
+
+The input needs date (YYYY-MM-DD), amount and currency columns; all other
+columns are kept. The output repeats each row with rate, rate_date and
+converted_amount columns appended. Rates are fetched once per currency for the
+whole date range of the ledger.
+
+When a row's date has no fixing, --rule decides which rate applies:
+  previous  the last fixing on or before the date (default)
+  next      the first fixing on or after the date
+  exact     only a fixing on the date itself`,
+		Example: `  xrv convert-file ledger.csv --to EUR
+  xrv convert-file ledger.csv --to EUR --rule next --out ledger-eur.csv`,
+		Args: cobra.ExactArgs(1),
+		RunE: runConvertFile,
+	}
+
+	cmd.Flags().StringVar(&convertFileTo, "to", "", "Reporting currency (required)")
+	cmd.Flags().StringVar(&convertFileOut, "out", "", "Write the augmented CSV to this file instead of stdout")
+	cmd.Flags().StringVar(&convertFileRule, "rule", "", "Rate to use when a date has no fixing: previous, next, exact")
+	cmd.Flags().IntVar(&convertFileLookback, "lookback", 7, "Business days to search for a fixing under the previous/next rules")
+	cmd.Flags().StringVar(&convertFileDateCol, "date-column", "date", "Name of the date column")
+	cmd.Flags().StringVar(&convertFileAmountCol, "amount-column", "amount", "Name of the amount column")
+	cmd.Flags().StringVar(&convertFileCurrencyCol, "currency-column", "currency", "Name of the currency column")
+	cmd.Flags().BoolVar(&convertFileNoCache, "no-cache", false, "Disable caching")
+	cmd.MarkFlagRequired("to")
+
+	return cmd
+}
+
+type ledger struct {
+	header       []string
+	rows         [][]string
+	transactions []service.Transaction
+}
+
+func runConvertFile(cmd *cobra.Command, args []string) error {
+	cfg, err := currentConfig()
+	if err != nil {
+		return err
+	}
+
+	rule, err := service.ParseRateRule(cfg.Convert.RateRule)
+	if err != nil {
+		return err
+	}
+	if cmd.Flags().Changed("rule") {
+		if rule, err = service.ParseRateRule(convertFileRule); err != nil {
+			return err
+		}
+	}
+	lookback := cfg.Convert.LookbackDays
+	if cmd.Flags().Changed("lookback") {
+		lookback = convertFileLookback
+	}
+
+	f, err := os.Open(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to open ledger: %w", err)
+	}
+	defer f.Close()
+
+	book, err := readLedger(f, convertFileDateCol, convertFileAmountCol, convertFileCurrencyCol)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", args[0], err)
+	}
+
+	apiClient, err := newAPIClient(cfg)
+	if err != nil {
+		return err
+	}
+
+	dataCache, err := openCache(cfg)
+	if err != nil {
+		return err
+	}
+	defer dataCache.Close()
+
+	svc := newService(cfg, apiClient, dataCache)
+
+	converted, err := svc.ConvertTransactions(context.Background(), book.transactions, service.BatchConvertOptions{
+		To:           domain.Currency(strings.ToUpper(strings.TrimSpace(convertFileTo))),
+		Rule:         rule,
+		LookbackDays: lookback,
+		UseCache:     cfg.Cache.Enabled && !convertFileNoCache,
+	})
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	if convertFileOut != "" {
+		file, err := os.Create(convertFileOut)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	if err := writeLedger(out, book, converted); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+
+	failed := 0
+	for i, c := range converted {
+		if c.Err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "row %d: %v\n", i+2, c.Err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d rows could not be converted", failed, len(converted))
+	}
+
+	return nil
+}
+
+func readLedger(r io.Reader, dateCol, amountCol, currencyCol string) (*ledger, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+
+	indexOf := func(name string) (int, error) {
+		i, ok := columns[strings.ToLower(name)]
+		if !ok {
+			return 0, fmt.Errorf("missing %q column", name)
+		}
+		return i, nil
+	}
+
+	dateIdx, err := indexOf(dateCol)
+	if err != nil {
+		return nil, err
+	}
+	amountIdx, err := indexOf(amountCol)
+	if err != nil {
+		return nil, err
+	}
+	currencyIdx, err := indexOf(currencyCol)
+	if err != nil {
+		return nil, err
+	}
+
+	book := &ledger{header: header}
+	line := 1
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		date, err := time.Parse("2006-01-02", strings.TrimSpace(record[dateIdx]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid date %q", line, record[dateIdx])
+		}
+		amount, err := parseAmount(record[amountIdx])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		currency := strings.ToUpper(strings.TrimSpace(record[currencyIdx]))
+		if currency == "" {
+			return nil, fmt.Errorf("line %d: missing currency", line)
+		}
+
+		book.rows = append(book.rows, record)
+		book.transactions = append(book.transactions, service.Transaction{
+			Date:     date,
+			Amount:   amount,
+			Currency: domain.Currency(currency),
+		})
+	}
+
+	return book, nil
+}
+
+func writeLedger(out io.Writer, book *ledger, converted []service.ConvertedTransaction) error {
+	w := csv.NewWriter(out)
+
+	header := append(append([]string{}, book.header...), "rate", "rate_date", "converted_amount")
+	if err := w.Write(header); err != nil {
+		return err
+	}
+
+	for i, record := range book.rows {
+		row := append([]string{}, record...)
+		c := converted[i]
+		if c.Err != nil {
+			row = append(row, "", "", "")
+		} else {
+			row = append(row,
+				strconv.FormatFloat(c.Rate, 'f', -1, 64),
+				c.RateDate.Format("2006-01-02"),
+				strconv.FormatFloat(c.Converted, 'f', 2, 64),
+			)
+		}
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
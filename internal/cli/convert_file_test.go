@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kaze/xrv/internal/service"
+)
+
+func TestReadLedger(t *testing.T) {
+	input := "id,Date,Amount,Currency,memo\n" +
+		"1,2023-03-15,\"12,430.50\",usd,invoice\n" +
+		"2,2023-03-18,99,HUF,\n"
+
+	book, err := readLedger(strings.NewReader(input), "date", "amount", "currency")
+	if err != nil {
+		t.Fatalf("readLedger() error = %v", err)
+	}
+
+	if len(book.transactions) != 2 {
+		t.Fatalf("transactions = %d, want 2", len(book.transactions))
+	}
+	first := book.transactions[0]
+	if first.Amount != 12430.50 || first.Currency != "USD" || first.Date.Format("2006-01-02") != "2023-03-15" {
+		t.Errorf("first transaction = %+v", first)
+	}
+
+	if _, err := readLedger(strings.NewReader("when,amount,currency\n"), "date", "amount", "currency"); err == nil {
+		t.Error("readLedger() should fail when the date column is missing")
+	}
+	if _, err := readLedger(strings.NewReader("date,amount,currency\n15/03/2023,1,USD\n"), "date", "amount", "currency"); err == nil {
+		t.Error("readLedger() should fail on invalid dates")
+	}
+}
+
+func TestWriteLedger(t *testing.T) {
+	book := &ledger{
+		header: []string{"date", "amount", "currency"},
+		rows:   [][]string{{"2023-03-18", "100", "USD"}, {"2023-03-19", "5", "XXX"}},
+	}
+	converted := []service.ConvertedTransaction{
+		{Rate: 0.93, RateDate: time.Date(2023, 3, 17, 0, 0, 0, 0, time.UTC), Converted: 93},
+		{Err: errors.New("no fixing")},
+	}
+
+	var out bytes.Buffer
+	if err := writeLedger(&out, book, converted); err != nil {
+		t.Fatalf("writeLedger() error = %v", err)
+	}
+
+	want := "date,amount,currency,rate,rate_date,converted_amount\n" +
+		"2023-03-18,100,USD,0.93,2023-03-17,93.00\n" +
+		"2023-03-19,5,XXX,,,\n"
+	if out.String() != want {
+		t.Errorf("writeLedger() =\n%s\nwant\n%s", out.String(), want)
+	}
+}
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kaze/xrv/internal/service"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    float64
+		wantErr bool
+	}{
+		{"12430.50", 12430.50, false},
+		{"12,430.50", 12430.50, false},
+		{"1_000", 1000, false},
+		{"abc", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseAmount(tt.input)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseAmount(%q) = %v, %v, want %v (error: %v)", tt.input, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestParseCurrencyList(t *testing.T) {
+	got := parseCurrencyList([]string{"huf", "EUR,gbp", "HUF"})
+	if len(got) != 3 || got[0] != "HUF" || got[1] != "EUR" || got[2] != "GBP" {
+		t.Errorf("parseCurrencyList() = %v, want [HUF EUR GBP]", got)
+	}
+}
+
+func TestWriteConversion(t *testing.T) {
+	date := time.Date(2023, 3, 19, 0, 0, 0, 0, time.UTC)
+	result := &service.ConversionResult{
+		Amount: 100,
+		From:   "USD",
+		Date:   date,
+		Conversions: []service.Conversion{
+			{Target: "EUR", Rate: 0.93, RateDate: date.AddDate(0, 0, -2), Amount: 93, Fallback: true},
+			{Target: "XXX", Err: errors.New("currency not found")},
+		},
+	}
+
+	var table bytes.Buffer
+	if err := writeConversionTable(&table, result); err != nil {
+		t.Fatalf("writeConversionTable() error = %v", err)
+	}
+	if !strings.Contains(table.String(), "93.00") || !strings.Contains(table.String(), "previous available fixing from 2023-03-17") {
+		t.Errorf("table output missing amount or fallback note:\n%s", table.String())
+	}
+
+	var csvOut bytes.Buffer
+	if err := writeConversionCSV(&csvOut, result); err != nil {
+		t.Fatalf("writeConversionCSV() error = %v", err)
+	}
+	if !strings.Contains(csvOut.String(), "2023-03-19,100,USD,EUR,93.00,0.93,2023-03-17,true") ||
+		!strings.Contains(csvOut.String(), "2023-03-19,100,USD,XXX,,,,") {
+		t.Errorf("unexpected CSV output:\n%s", csvOut.String())
+	}
+
+	var jsonOut bytes.Buffer
+	if err := writeConversionJSON(&jsonOut, result); err != nil {
+		t.Fatalf("writeConversionJSON() error = %v", err)
+	}
+	if !strings.Contains(jsonOut.String(), `"rate_date": "2023-03-17"`) || !strings.Contains(jsonOut.String(), `"fallback": true`) ||
+		!strings.Contains(jsonOut.String(), `"error": "currency not found"`) {
+		t.Errorf("unexpected JSON output:\n%s", jsonOut.String())
+	}
+}
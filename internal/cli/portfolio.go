@@ -0,0 +1,276 @@
+package cli
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kaze/xrv/internal/domain"
+	"github.com/kaze/xrv/internal/portfolio"
+	"github.com/kaze/xrv/internal/statistics"
+	"github.com/kaze/xrv/internal/visualization/browser"
+	"github.com/kaze/xrv/internal/visualization/terminal"
+)
+
+var (
+	portfolioBase    string
+	portfolioFrom    string
+	portfolioTo      string
+	portfolioOutput  string
+	portfolioPort    int
+	portfolioHeight  int
+	portfolioWidth   int
+	portfolioNoCache bool
+)
+
+func NewPortfolioCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "portfolio HOLDINGS",
+		Short: "Value currency holdings in a reporting currency",
+		Long: `Value the currency holdings listed in a YAML or CSV file in a reporting
+currency over time, with each currency's contribution and the unrealized FX
+gain or loss of every holding.
+
+A YAML file looks like
+
+  reporting: EUR
+  holdings:
+    - currency: USD
+      amount: 250000
+      acquired: 2024-03-01
+      rate: 0.92
+    - currency: GBP
+      amount: 40000
+
+and a CSV file has a header with currency and amount columns and optional
+acquired and rate columns. The rate is the price of one unit of the holding
+in the reporting currency when it was acquired. Without it the cost basis is
+the fixing on or before the acquisition date, and without either it is the
+value at the start of the period.`,
+		Example: `  xrv portfolio holdings.yaml
+  xrv portfolio holdings.csv --base USD --from "2 years ago" -o browser
+  xrv portfolio holdings.yaml -o csv > value.csv`,
+		Args: cobra.ExactArgs(1),
+		RunE: runPortfolio,
+	}
+
+	cmd.Flags().StringVarP(&portfolioBase, "base", "b", "", "Reporting currency, defaults to the file's, then cli.default_base")
+	cmd.Flags().StringVarP(&portfolioFrom, "from", "f", "", "Start date (YYYY-MM-DD) or relative (e.g., '1 year ago')")
+	cmd.Flags().StringVarP(&portfolioTo, "to", "t", "", "End date (YYYY-MM-DD), defaults to today")
+	cmd.Flags().StringVarP(&portfolioOutput, "output", "o", "terminal", "Output format: terminal, browser, json, csv")
+	cmd.Flags().IntVar(&portfolioPort, "port", 8080, "Port for browser mode (default: 8080)")
+	cmd.Flags().IntVar(&portfolioHeight, "height", 15, "Chart height (terminal mode)")
+	cmd.Flags().IntVar(&portfolioWidth, "width", 80, "Chart width (terminal mode)")
+	cmd.Flags().BoolVar(&portfolioNoCache, "no-cache", false, "Disable caching")
+
+	return cmd
+}
+
+func runPortfolio(cmd *cobra.Command, args []string) error {
+	format := strings.ToLower(portfolioOutput)
+	switch format {
+	case "terminal", "browser", "json", "csv":
+	default:
+		return fmt.Errorf("unsupported output format: %s (use 'terminal', 'browser', 'json' or 'csv')", portfolioOutput)
+	}
+
+	holdings, err := portfolio.Load(args[0])
+	if err != nil {
+		return err
+	}
+
+	cfg, err := currentConfig()
+	if err != nil {
+		return err
+	}
+
+	reporting := domain.Currency(strings.ToUpper(portfolioBase))
+	if reporting == "" {
+		reporting = holdings.Reporting
+	}
+	if reporting == "" {
+		reporting = domain.Currency(strings.ToUpper(cfg.CLI.DefaultBase))
+	}
+
+	startDate, endDate, err := resolvePeriod(cfg, portfolioFrom, portfolioTo)
+	if err != nil {
+		return err
+	}
+
+	apiClient, err := newAPIClient(cfg)
+	if err != nil {
+		return err
+	}
+
+	dataCache, err := openCache(cfg)
+	if err != nil {
+		return err
+	}
+	defer dataCache.Close()
+
+	svc := newService(cfg, apiClient, dataCache)
+
+	valuation, err := portfolio.Value(context.Background(), svc, holdings, portfolio.ValuationOptions{
+		Reporting: reporting,
+		StartDate: startDate,
+		EndDate:   endDate,
+		UseCache:  cfg.Cache.Enabled && !portfolioNoCache,
+		Rebase:    cfg.Cache.Rebase,
+	})
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	switch format {
+	case "json":
+		return writePortfolioJSON(out, valuation)
+	case "csv":
+		return writePortfolioCSV(out, valuation)
+	case "browser":
+		port := cfg.Visualization.Port
+		if cmd.Flags().Changed("port") {
+			port = portfolioPort
+		}
+		renderer := browser.NewRenderer(port, browser.WithTheme(cfg.Visualization.Theme))
+		return renderer.RenderPortfolio(valuation)
+	default:
+		height := cfg.Visualization.ChartHeight
+		if cmd.Flags().Changed("height") {
+			height = portfolioHeight
+		}
+		width := cfg.Visualization.ChartWidth
+		if cmd.Flags().Changed("width") {
+			width = portfolioWidth
+		}
+		terminal.NewRenderer(height, width).RenderPortfolio(valuation)
+		return nil
+	}
+}
+
+func writePortfolioJSON(out io.Writer, v *portfolio.Valuation) error {
+	type positionJSON struct {
+		Currency        string  `json:"currency"`
+		Amount          float64 `json:"amount"`
+		Acquired        string  `json:"acquired,omitempty"`
+		Rate            float64 `json:"rate"`
+		Value           float64 `json:"value"`
+		Share           float64 `json:"share_percent"`
+		Change          float64 `json:"change"`
+		CostBasis       float64 `json:"cost_basis"`
+		CostSource      string  `json:"cost_source"`
+		GainLoss        float64 `json:"gain_loss"`
+		GainLossPercent float64 `json:"gain_loss_percent"`
+		Unpriced        bool    `json:"unpriced,omitempty"`
+	}
+
+	type contributionJSON struct {
+		Currency string            `json:"currency"`
+		Amount   float64           `json:"amount"`
+		Value    float64           `json:"value"`
+		Share    float64           `json:"share_percent"`
+		Change   float64           `json:"change"`
+		GainLoss float64           `json:"gain_loss"`
+		Values   statistics.Series `json:"values"`
+	}
+
+	type valuationJSON struct {
+		Reporting  string             `json:"reporting"`
+		StartDate  string             `json:"start_date"`
+		EndDate    string             `json:"end_date"`
+		Value      float64            `json:"value"`
+		Change     float64            `json:"change"`
+		CostBasis  float64            `json:"cost_basis"`
+		GainLoss   float64            `json:"gain_loss"`
+		Positions  []positionJSON     `json:"positions"`
+		ByCurrency []contributionJSON `json:"by_currency"`
+		Dates      []string           `json:"dates"`
+		Total      statistics.Series  `json:"total"`
+	}
+
+	payload := valuationJSON{
+		Reporting:  string(v.Reporting),
+		StartDate:  v.StartDate.Format("2006-01-02"),
+		EndDate:    v.EndDate.Format("2006-01-02"),
+		Value:      v.TotalValue,
+		Change:     v.Change,
+		CostBasis:  v.TotalCost,
+		GainLoss:   v.TotalGainLoss,
+		Positions:  make([]positionJSON, len(v.Positions)),
+		ByCurrency: make([]contributionJSON, len(v.ByCurrency)),
+		Dates:      make([]string, len(v.Dates)),
+		Total:      v.Total,
+	}
+	for i, p := range v.Positions {
+		payload.Positions[i] = positionJSON{
+			Currency:        string(p.Holding.Currency),
+			Amount:          p.Holding.Amount,
+			Rate:            p.Rate,
+			Value:           p.Value,
+			Share:           p.Share,
+			Change:          p.Change,
+			CostBasis:       p.CostBasis,
+			CostSource:      string(p.CostSource),
+			GainLoss:        p.GainLoss,
+			GainLossPercent: p.GainLossPercent,
+			Unpriced:        p.Unpriced,
+		}
+		if !p.Holding.Acquired.IsZero() {
+			payload.Positions[i].Acquired = p.Holding.Acquired.Format("2006-01-02")
+		}
+	}
+	for i, c := range v.ByCurrency {
+		payload.ByCurrency[i] = contributionJSON{
+			Currency: string(c.Currency),
+			Amount:   c.Amount,
+			Value:    c.Value,
+			Share:    c.Share,
+			Change:   c.Change,
+			GainLoss: c.GainLoss,
+			Values:   c.Values,
+		}
+	}
+	for i, date := range v.Dates {
+		payload.Dates[i] = date.Format("2006-01-02")
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(payload)
+}
+
+// writePortfolioCSV writes the value of each currency and the total per
+// date; values before a currency's first fixing are empty.
+func writePortfolioCSV(out io.Writer, v *portfolio.Valuation) error {
+	w := csv.NewWriter(out)
+
+	header := []string{"date"}
+	for _, c := range v.ByCurrency {
+		header = append(header, string(c.Currency))
+	}
+	w.Write(append(header, "total"))
+
+	format := func(value float64) string {
+		if math.IsNaN(value) {
+			return ""
+		}
+		return strconv.FormatFloat(value, 'f', 2, 64)
+	}
+	for i, date := range v.Dates {
+		record := []string{date.Format("2006-01-02")}
+		for _, c := range v.ByCurrency {
+			record = append(record, format(c.Values[i]))
+		}
+		w.Write(append(record, format(v.Total[i])))
+	}
+
+	w.Flush()
+	return w.Error()
+}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kaze/xrv/internal/portfolio"
+	"github.com/kaze/xrv/internal/statistics"
+)
+
+func TestWritePortfolio(t *testing.T) {
+	v := &portfolio.Valuation{
+		Reporting: "EUR",
+		StartDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		Dates: []time.Time{
+			time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		},
+		Positions: []portfolio.Position{
+			{Holding: portfolio.Holding{Currency: "USD", Amount: 100, Acquired: time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)},
+				Rate: 0.9, Value: 90, CostBasis: 92, CostSource: portfolio.CostFromAcquired, GainLoss: -2},
+		},
+		ByCurrency: []portfolio.Contribution{
+			{Currency: "USD", Amount: 100, Values: statistics.Series{math.NaN(), 90}, Value: 90, Share: 100},
+		},
+		Total:      statistics.Series{math.NaN(), 90},
+		TotalValue: 90,
+	}
+
+	var csvOut bytes.Buffer
+	if err := writePortfolioCSV(&csvOut, v); err != nil {
+		t.Fatalf("writePortfolioCSV() error = %v", err)
+	}
+	want := "date,USD,total\n2024-01-01,,\n2024-01-02,90.00,90.00\n"
+	if csvOut.String() != want {
+		t.Errorf("writePortfolioCSV() = %q, want %q", csvOut.String(), want)
+	}
+
+	var jsonOut bytes.Buffer
+	if err := writePortfolioJSON(&jsonOut, v); err != nil {
+		t.Fatalf("writePortfolioJSON() error = %v", err)
+	}
+	var decoded struct {
+		Reporting string `json:"reporting"`
+		Positions []struct {
+			Acquired   string `json:"acquired"`
+			CostSource string `json:"cost_source"`
+		} `json:"positions"`
+		Total []*float64 `json:"total"`
+	}
+	if err := json.Unmarshal(jsonOut.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, jsonOut.String())
+	}
+	if decoded.Reporting != "EUR" || decoded.Positions[0].Acquired != "2023-06-01" || decoded.Positions[0].CostSource != "acquired" {
+		t.Errorf("decoded = %+v", decoded)
+	}
+	if decoded.Total[0] != nil || *decoded.Total[1] != 90 {
+		t.Errorf("total = %v, want null before the first fixing", decoded.Total)
+	}
+	if !strings.Contains(jsonOut.String(), `"by_currency"`) {
+		t.Error("JSON should list the contribution of each currency")
+	}
+}
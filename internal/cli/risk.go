@@ -0,0 +1,315 @@
+package cli
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kaze/xrv/internal/domain"
+	"github.com/kaze/xrv/internal/service"
+	"github.com/kaze/xrv/internal/statistics"
+)
+
+var (
+	riskBase       string
+	riskCurrencies string
+	riskFrom       string
+	riskTo         string
+	riskConfidence string
+	riskHorizon    string
+	riskExposure   float64
+	riskOutput     string
+	riskNoCache    bool
+)
+
+func NewRiskCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "risk",
+		Short: "Value-at-Risk and expected shortfall of currency exposures",
+		Long: `Estimate Value-at-Risk (VaR) and Expected Shortfall (ES) of holding each
+target currency, valued in the base currency, from the log returns of its
+rate over the chosen period.
+
+Figures are percentages of the position's value (or amounts when --exposure
+is given) for the historical, parametric (normal) and EWMA methods at each
+confidence level and horizon. Horizons count observations, i.e. business
+days for daily fixings, and scale with the square root of time.`,
+		Example: `  xrv risk --base EUR --currencies USD,GBP --from "2 years ago"
+  xrv risk -b EUR -c USD --confidence 0.975 --horizon 1,5 --exposure 1000000 -o json`,
+		RunE: runRisk,
+	}
+
+	cmd.Flags().StringVarP(&riskBase, "base", "b", "", "Base (reporting) currency, defaults to cli.default_base")
+	cmd.Flags().StringVarP(&riskCurrencies, "currencies", "c", "", "Currencies held, defaults to cli.default_targets")
+	cmd.Flags().StringVarP(&riskFrom, "from", "f", "", "Start date (YYYY-MM-DD) or relative (e.g., '1 year ago')")
+	cmd.Flags().StringVarP(&riskTo, "to", "t", "", "End date (YYYY-MM-DD), defaults to today")
+	cmd.Flags().StringVar(&riskConfidence, "confidence", "", "Confidence levels, e.g. 0.95,0.99 (default from statistics.var_confidence)")
+	cmd.Flags().StringVar(&riskHorizon, "horizon", "", "Horizons in observations, e.g. 1,10 (default from statistics.var_horizons)")
+	cmd.Flags().Float64Var(&riskExposure, "exposure", 0, "Value of each position in the base currency, to report losses as amounts")
+	cmd.Flags().StringVarP(&riskOutput, "output", "o", "table", "Output format: table, json, csv")
+	cmd.Flags().BoolVar(&riskNoCache, "no-cache", false, "Disable caching")
+
+	return cmd
+}
+
+func runRisk(cmd *cobra.Command, args []string) error {
+	format := strings.ToLower(riskOutput)
+	if format != "table" && format != "json" && format != "csv" {
+		return fmt.Errorf("unsupported output format: %s (use 'table', 'json' or 'csv')", riskOutput)
+	}
+	if riskExposure < 0 {
+		return fmt.Errorf("exposure must not be negative")
+	}
+
+	cfg, err := currentConfig()
+	if err != nil {
+		return err
+	}
+
+	opts := statistics.RiskOptions{
+		Confidences: cfg.Statistics.VaRConfidence,
+		Horizons:    cfg.Statistics.VaRHorizons,
+	}
+	if riskConfidence != "" {
+		confidences, err := parseFloatList(riskConfidence)
+		if err != nil {
+			return fmt.Errorf("invalid confidence: %w", err)
+		}
+		opts.Confidences = confidences
+	}
+	if riskHorizon != "" {
+		horizons, err := parseIntList(riskHorizon)
+		if err != nil {
+			return fmt.Errorf("invalid horizon: %w", err)
+		}
+		opts.Horizons = horizons
+	}
+	if err := statistics.ValidateRiskOptions(opts); err != nil {
+		return err
+	}
+
+	series, err := resolveSeriesArgs(cfg, riskBase, riskCurrencies, riskFrom, riskTo)
+	if err != nil {
+		return err
+	}
+
+	apiClient, err := newAPIClient(cfg)
+	if err != nil {
+		return err
+	}
+
+	dataCache, err := openCache(cfg)
+	if err != nil {
+		return err
+	}
+	defer dataCache.Close()
+
+	svc := newService(cfg, apiClient, dataCache, service.WithRisk(opts.Confidences, opts.Horizons))
+
+	data, err := svc.FetchTimeSeriesData(context.Background(), service.FetchOptions{
+		Base:      series.Base,
+		Targets:   series.Targets,
+		StartDate: series.StartDate,
+		EndDate:   series.EndDate,
+		UseCache:  cfg.Cache.Enabled && !riskNoCache,
+		Rebase:    cfg.Cache.Rebase,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to fetch data: %w", err)
+	}
+
+	report := riskReport{
+		Base:      data.Base,
+		StartDate: data.StartDate,
+		EndDate:   data.EndDate,
+		Exposure:  riskExposure,
+	}
+	stats := svc.CalculateStatistics(data)
+	for _, target := range data.Targets {
+		if stat, exists := stats[string(target)]; exists {
+			report.Targets = append(report.Targets, target)
+			report.Risk = append(report.Risk, stat.Risk)
+		}
+	}
+
+	out := cmd.OutOrStdout()
+	switch format {
+	case "json":
+		return writeRiskJSON(out, report)
+	case "csv":
+		return writeRiskCSV(out, report)
+	default:
+		return writeRiskTable(out, report)
+	}
+}
+
+// riskReport pairs each target with its estimates; Risk[i] belongs to
+// Targets[i].
+type riskReport struct {
+	Base      domain.Currency
+	StartDate time.Time
+	EndDate   time.Time
+	Exposure  float64
+	Targets   []domain.Currency
+	Risk      []statistics.RiskStats
+}
+
+// amount converts a percentage loss to the exposure's currency, or returns
+// zero without an exposure.
+func (r riskReport) amount(percent float64) float64 {
+	return r.Exposure * percent / 100
+}
+
+func writeRiskTable(out io.Writer, report riskReport) error {
+	fmt.Fprintf(out, "Risk of holding each currency, valued in %s, %s to %s\n\n",
+		report.Base, report.StartDate.Format("2006-01-02"), report.EndDate.Format("2006-01-02"))
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	header := "CURRENCY\tMETHOD\tCONFIDENCE\tHORIZON\tVAR\tES"
+	if report.Exposure > 0 {
+		header += fmt.Sprintf("\tVAR %s\tES %s", report.Base, report.Base)
+	}
+	fmt.Fprintln(w, header)
+
+	for i, target := range report.Targets {
+		if len(report.Risk[i].Estimates) == 0 {
+			fmt.Fprintf(w, "%s\t(not enough data: %d returns)\n", target, report.Risk[i].Observations)
+			continue
+		}
+		for _, e := range report.Risk[i].Estimates {
+			fmt.Fprintf(w, "%s\t%s\t%.4g%%\t%d\t%.2f%%\t%.2f%%", target, e.Method, e.Confidence*100, e.Horizon, e.VaR, e.ES)
+			if report.Exposure > 0 {
+				fmt.Fprintf(w, "\t%.2f\t%.2f", report.amount(e.VaR), report.amount(e.ES))
+			}
+			fmt.Fprintln(w)
+		}
+	}
+
+	return w.Flush()
+}
+
+func writeRiskJSON(out io.Writer, report riskReport) error {
+	type estimateJSON struct {
+		Method     string   `json:"method"`
+		Confidence float64  `json:"confidence"`
+		Horizon    int      `json:"horizon"`
+		VaR        float64  `json:"var_percent"`
+		ES         float64  `json:"es_percent"`
+		VaRAmount  *float64 `json:"var_amount,omitempty"`
+		ESAmount   *float64 `json:"es_amount,omitempty"`
+	}
+
+	type targetJSON struct {
+		Currency     string         `json:"currency"`
+		Observations int            `json:"observations"`
+		Estimates    []estimateJSON `json:"estimates"`
+	}
+
+	type reportJSON struct {
+		Base      string       `json:"base"`
+		StartDate string       `json:"start_date"`
+		EndDate   string       `json:"end_date"`
+		Exposure  float64      `json:"exposure,omitempty"`
+		Targets   []targetJSON `json:"targets"`
+	}
+
+	payload := reportJSON{
+		Base:      string(report.Base),
+		StartDate: report.StartDate.Format("2006-01-02"),
+		EndDate:   report.EndDate.Format("2006-01-02"),
+		Exposure:  report.Exposure,
+		Targets:   make([]targetJSON, len(report.Targets)),
+	}
+	for i, target := range report.Targets {
+		estimates := make([]estimateJSON, len(report.Risk[i].Estimates))
+		for j, e := range report.Risk[i].Estimates {
+			estimates[j] = estimateJSON{
+				Method:     string(e.Method),
+				Confidence: e.Confidence,
+				Horizon:    e.Horizon,
+				VaR:        e.VaR,
+				ES:         e.ES,
+			}
+			if report.Exposure > 0 {
+				varAmount, esAmount := report.amount(e.VaR), report.amount(e.ES)
+				estimates[j].VaRAmount, estimates[j].ESAmount = &varAmount, &esAmount
+			}
+		}
+		payload.Targets[i] = targetJSON{
+			Currency:     string(target),
+			Observations: report.Risk[i].Observations,
+			Estimates:    estimates,
+		}
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(payload)
+}
+
+func writeRiskCSV(out io.Writer, report riskReport) error {
+	w := csv.NewWriter(out)
+	w.Write([]string{"base", "currency", "method", "confidence", "horizon", "var_percent", "es_percent", "var_amount", "es_amount"})
+
+	for i, target := range report.Targets {
+		for _, e := range report.Risk[i].Estimates {
+			varAmount, esAmount := "", ""
+			if report.Exposure > 0 {
+				varAmount = strconv.FormatFloat(report.amount(e.VaR), 'f', 2, 64)
+				esAmount = strconv.FormatFloat(report.amount(e.ES), 'f', 2, 64)
+			}
+			w.Write([]string{
+				string(report.Base),
+				string(target),
+				string(e.Method),
+				strconv.FormatFloat(e.Confidence, 'f', -1, 64),
+				strconv.Itoa(e.Horizon),
+				strconv.FormatFloat(e.VaR, 'f', 4, 64),
+				strconv.FormatFloat(e.ES, 'f', 4, 64),
+				varAmount,
+				esAmount,
+			})
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
+
+func parseFloatList(value string) ([]float64, error) {
+	var values []float64
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part == "" {
+			continue
+		}
+		number, err := strconv.ParseFloat(part, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", part)
+		}
+		values = append(values, number)
+	}
+	return values, nil
+}
+
+func parseIntList(value string) ([]int, error) {
+	var values []int
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part == "" {
+			continue
+		}
+		number, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a whole number", part)
+		}
+		values = append(values, number)
+	}
+	return values, nil
+}
@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kaze/xrv/internal/domain"
+	"github.com/kaze/xrv/internal/statistics"
+)
+
+func TestWriteRisk(t *testing.T) {
+	report := riskReport{
+		Base:      "EUR",
+		StartDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC),
+		Exposure:  1000000,
+		Targets:   []domain.Currency{"USD", "GBP"},
+		Risk: []statistics.RiskStats{
+			{Observations: 250, Estimates: []statistics.RiskEstimate{
+				{Method: statistics.RiskHistorical, Confidence: 0.95, Horizon: 1, VaR: 0.72, ES: 0.95},
+			}},
+			{Observations: 1},
+		},
+	}
+
+	var table bytes.Buffer
+	if err := writeRiskTable(&table, report); err != nil {
+		t.Fatalf("writeRiskTable() error = %v", err)
+	}
+	for _, want := range []string{"historical", "95%", "0.72%", "7200.00", "not enough data"} {
+		if !strings.Contains(table.String(), want) {
+			t.Errorf("table output missing %q:\n%s", want, table.String())
+		}
+	}
+
+	var csvOut bytes.Buffer
+	if err := writeRiskCSV(&csvOut, report); err != nil {
+		t.Fatalf("writeRiskCSV() error = %v", err)
+	}
+	if !strings.Contains(csvOut.String(), "EUR,USD,historical,0.95,1,0.7200,0.9500,7200.00,9500.00") {
+		t.Errorf("unexpected CSV output:\n%s", csvOut.String())
+	}
+
+	var jsonOut bytes.Buffer
+	if err := writeRiskJSON(&jsonOut, report); err != nil {
+		t.Fatalf("writeRiskJSON() error = %v", err)
+	}
+	if !strings.Contains(jsonOut.String(), `"var_amount": 7200`) || !strings.Contains(jsonOut.String(), `"observations": 1`) {
+		t.Errorf("unexpected JSON output:\n%s", jsonOut.String())
+	}
+}
+
+func TestParseLists(t *testing.T) {
+	if got, err := parseFloatList("0.95, 0.99"); err != nil || len(got) != 2 || got[1] != 0.99 {
+		t.Errorf("parseFloatList() = %v, %v", got, err)
+	}
+	if _, err := parseIntList("1,ten"); err == nil {
+		t.Error("parseIntList(1,ten) should fail")
+	}
+}
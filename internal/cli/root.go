@@ -1,17 +1,25 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+
+	"github.com/kaze/xrv/internal/config"
 )
 
 var (
-	cfgFile  string
-	cacheDir string
-	debug    bool
+	cfgFile      string
+	cacheDir     string
+	providerName string
+	fallback     []string
+	dataDir      string
+	ecbSource    string
+	debug        bool
+	cfg          *config.Config
 )
 
 func NewRootCommand() *cobra.Command {
@@ -23,23 +31,66 @@ func NewRootCommand() *cobra.Command {
 It provides interactive terminal visualizations, comprehensive statistics,
 and support for long historical time ranges (back to 1999).`,
 		Version: "0.1.0",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			loaded, err := config.Load(viper.GetViper(), cfgFile)
+			if err != nil {
+				return err
+			}
+			cfg = loaded
+			return nil
+		},
 	}
 
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.xrv/config.yaml)")
 	rootCmd.PersistentFlags().StringVar(&cacheDir, "cache-dir", "", "cache directory (default is $HOME/.xrv/cache)")
+	rootCmd.PersistentFlags().StringVar(&providerName, "provider", "", "rate provider: frankfurter, ecb, file (default is frankfurter)")
+	rootCmd.PersistentFlags().StringSliceVar(&fallback, "fallback", nil, "providers to try in order when the primary one is unavailable")
+	rootCmd.PersistentFlags().StringVar(&dataDir, "data-dir", "", "snapshot directory for the file provider")
+	rootCmd.PersistentFlags().StringVar(&ecbSource, "ecb-source", "", "ECB eurofxref-hist file or URL (.zip, .xml or .csv) for the ecb provider")
 	rootCmd.PersistentFlags().BoolVar(&debug, "debug", false, "enable debug logging")
 
 	viper.BindPFlag("config", rootCmd.PersistentFlags().Lookup("config"))
-	viper.BindPFlag("cache-dir", rootCmd.PersistentFlags().Lookup("cache-dir"))
+	viper.BindPFlag("cache.directory", rootCmd.PersistentFlags().Lookup("cache-dir"))
+	viper.BindPFlag("provider.name", rootCmd.PersistentFlags().Lookup("provider"))
+	viper.BindPFlag("provider.fallback", rootCmd.PersistentFlags().Lookup("fallback"))
+	viper.BindPFlag("provider.data_dir", rootCmd.PersistentFlags().Lookup("data-dir"))
+	viper.BindPFlag("provider.ecb_source", rootCmd.PersistentFlags().Lookup("ecb-source"))
 	viper.BindPFlag("debug", rootCmd.PersistentFlags().Lookup("debug"))
 
 	rootCmd.AddCommand(NewVisualizeCommand())
+	rootCmd.AddCommand(NewCacheCommand())
+	rootCmd.AddCommand(NewConvertCommand())
+	rootCmd.AddCommand(NewConvertFileCommand())
+	rootCmd.AddCommand(NewRiskCommand())
+	rootCmd.AddCommand(NewPortfolioCommand())
+	rootCmd.AddCommand(NewAveragesCommand())
+	rootCmd.AddCommand(NewAlertsCommand())
+	rootCmd.AddCommand(NewWatchCommand())
 
 	return rootCmd
 }
 
+// exitError ends the process with code instead of 1 and without printing
+// err, for commands whose outcome is reported through the exit status.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
 func Execute() {
 	if err := NewRootCommand().Execute(); err != nil {
+		var exit *exitError
+		if errors.As(err, &exit) {
+			os.Exit(exit.code)
+		}
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
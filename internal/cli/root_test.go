@@ -27,7 +27,7 @@ func TestRootCommand(t *testing.T) {
 func TestRootCommandFlags(t *testing.T) {
 	cmd := NewRootCommand()
 
-	flags := []string{"config", "cache-dir", "debug"}
+	flags := []string{"config", "cache-dir", "provider", "fallback", "data-dir", "ecb-source", "debug"}
 
 	for _, flag := range flags {
 		if cmd.PersistentFlags().Lookup(flag) == nil {
@@ -0,0 +1,227 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kaze/xrv/internal/cache"
+	"github.com/kaze/xrv/internal/calendar"
+	"github.com/kaze/xrv/internal/config"
+	"github.com/kaze/xrv/internal/domain"
+	"github.com/kaze/xrv/internal/providers"
+	"github.com/kaze/xrv/internal/service"
+	"github.com/kaze/xrv/internal/statistics"
+)
+
+func currentConfig() (*config.Config, error) {
+	if cfg == nil {
+		defaults, err := config.Default()
+		if err != nil {
+			return nil, err
+		}
+		cfg = defaults
+	}
+	return cfg, nil
+}
+
+func newAPIClient(cfg *config.Config) (providers.APIClient, error) {
+	names := []string{cfg.Provider.Name}
+	if names[0] == "" {
+		names[0] = "frankfurter"
+	}
+	names = append(names, cfg.Provider.Fallback...)
+
+	for _, name := range names {
+		if name == "file" && cfg.Provider.DataDir == "" {
+			return nil, fmt.Errorf("the file provider requires a data directory (--data-dir or provider.data_dir)")
+		}
+	}
+
+	registry, err := newProviderRegistry(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return registry.Chain(names...)
+}
+
+// newProviderRegistry registers the available providers. Frankfurter serves
+// the ECB reference rates, so both quote the ECB currencies from its first
+// fixing on; both split or load long ranges themselves and have no maximum
+// range. The currencies of the file provider depend on its snapshots.
+func newProviderRegistry(cfg *config.Config) (*providers.Registry, error) {
+	registry := providers.NewRegistry()
+	reference := providers.Capabilities{
+		Currencies:   providers.ECBCurrencies(),
+		EarliestDate: time.Date(1999, 1, 4, 0, 0, 0, 0, time.UTC),
+	}
+
+	err := registry.Register("frankfurter",
+		providers.NewFrankfurterClient(cfg.API.BaseURL, cfg.API.Timeout, cfg.API.RetryAttempts,
+			providers.WithChunkDays(cfg.API.ChunkDays),
+			providers.WithConcurrency(cfg.API.Concurrency),
+			providers.WithRateLimit(cfg.API.RateLimit, cfg.API.RateBurst),
+		),
+		reference,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := registry.Register("ecb", providers.NewECBClient(cfg.Provider.ECBSource, cfg.API.Timeout), reference); err != nil {
+		return nil, err
+	}
+
+	if cfg.Provider.DataDir != "" {
+		if err := registry.Register("file", providers.NewFileClient(cfg.Provider.DataDir), providers.Capabilities{}); err != nil {
+			return nil, err
+		}
+	}
+
+	return registry, nil
+}
+
+func openCache(cfg *config.Config) (cache.Cache, error) {
+	if !cfg.Cache.Enabled {
+		return cache.NewMemoryCache(), nil
+	}
+
+	switch strings.ToLower(cfg.Cache.Type) {
+	case "", "badger":
+		badgerCache, err := cache.NewBadgerCache(cfg.Cache.Directory)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize cache: %w", err)
+		}
+		return badgerCache, nil
+	case "memory":
+		return cache.NewMemoryCache(), nil
+	default:
+		return nil, fmt.Errorf("unsupported cache type: %s (use 'badger' or 'memory')", cfg.Cache.Type)
+	}
+}
+
+// newService builds the service from the config; extra options are applied
+// last, so command-line flags can override configured values.
+func newService(cfg *config.Config, apiClient providers.APIClient, c cache.Cache, extra ...service.Option) *service.Service {
+	opts := []service.Option{
+		service.WithMovingAverages(movingAverages(cfg)),
+		service.WithIndicators(indicatorOptions(cfg)),
+		service.WithCorrelationWindow(cfg.Statistics.CorrelationWindow),
+		service.WithVolatility(cfg.Statistics.VolatilityWindows, cfg.Statistics.EWMALambda),
+		service.WithRisk(cfg.Statistics.VaRConfidence, cfg.Statistics.VaRHorizons),
+		service.WithCurrentDayTTL(cfg.Cache.TTLCurrentDay),
+		service.WithPivot(domain.Currency(cfg.Provider.Pivot)),
+		service.WithCalendar(newCalendar(cfg)),
+	}
+	return service.NewService(apiClient, c, append(opts, extra...)...)
+}
+
+func movingAverages(cfg *config.Config) []statistics.MovingAverageSpec {
+	var specs []statistics.MovingAverageSpec
+	add := func(kind statistics.MovingAverageKind, periods []int) {
+		for _, period := range periods {
+			if period > 0 {
+				specs = append(specs, statistics.MovingAverageSpec{Kind: kind, Period: period})
+			}
+		}
+	}
+	add(statistics.SMA, cfg.Statistics.SMAPeriods)
+	add(statistics.EMA, cfg.Statistics.EMAPeriods)
+	add(statistics.WMA, cfg.Statistics.WMAPeriods)
+	return specs
+}
+
+func indicatorOptions(cfg *config.Config) statistics.IndicatorOptions {
+	opts := statistics.IndicatorOptions{
+		RSIPeriod:       cfg.Statistics.RSIPeriod,
+		BollingerPeriod: cfg.Statistics.BollingerPeriod,
+		BollingerWidth:  cfg.Statistics.BollingerWidth,
+	}
+	if len(cfg.Statistics.MACD) == 3 {
+		opts.MACDFast, opts.MACDSlow, opts.MACDSignal = cfg.Statistics.MACD[0], cfg.Statistics.MACD[1], cfg.Statistics.MACD[2]
+	}
+	return opts
+}
+
+// newCalendar builds the configured calendar. The name and holidays are
+// validated when the config is loaded, so parse errors cannot occur here.
+func newCalendar(cfg *config.Config) calendar.Calendar {
+	cal, err := calendar.Parse(cfg.Calendar.Name)
+	if err != nil {
+		cal = calendar.TARGET2()
+	}
+
+	holidays := make([]time.Time, 0, len(cfg.Calendar.Holidays))
+	for _, day := range cfg.Calendar.Holidays {
+		if date, err := time.Parse("2006-01-02", day); err == nil {
+			holidays = append(holidays, date)
+		}
+	}
+
+	return calendar.WithHolidays(cal, holidays)
+}
+
+// seriesArgs are the currencies and period of a command that fetches a time
+// series.
+type seriesArgs struct {
+	Base      domain.Currency
+	Targets   []domain.Currency
+	StartDate time.Time
+	EndDate   time.Time
+}
+
+// resolveSeriesArgs fills the base, comma-separated currencies and period
+// flags of a command from cli.default_base, cli.default_targets and
+// cli.default_from when they are empty.
+func resolveSeriesArgs(cfg *config.Config, base, currencies, from, to string) (seriesArgs, error) {
+	if base == "" {
+		base = cfg.CLI.DefaultBase
+	}
+	targets := parseCurrencyList([]string{currencies})
+	if len(targets) == 0 {
+		targets = parseCurrencyList(cfg.CLI.DefaultTargets)
+	}
+	if len(targets) == 0 {
+		return seriesArgs{}, fmt.Errorf("at least one currency is required")
+	}
+
+	startDate, endDate, err := resolvePeriod(cfg, from, to)
+	if err != nil {
+		return seriesArgs{}, err
+	}
+
+	return seriesArgs{
+		Base:      domain.Currency(strings.ToUpper(base)),
+		Targets:   targets,
+		StartDate: startDate,
+		EndDate:   endDate,
+	}, nil
+}
+
+// resolvePeriod parses the --from and --to flags. The period ends today
+// without --to and starts at cli.default_from, or a year before the end,
+// without --from.
+func resolvePeriod(cfg *config.Config, from, to string) (time.Time, time.Time, error) {
+	endDate := time.Now()
+	if to != "" {
+		var err error
+		endDate, err = time.Parse("2006-01-02", to)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid end date format: %w", err)
+		}
+	}
+
+	if from == "" {
+		from = cfg.CLI.DefaultFrom
+	}
+	startDate := endDate.AddDate(-1, 0, 0)
+	if from != "" {
+		var err error
+		startDate, err = parseDate(from, endDate)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid start date format: %w", err)
+		}
+	}
+
+	return startDate, endDate, nil
+}
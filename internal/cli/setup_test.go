@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kaze/xrv/internal/config"
+)
+
+func testConfig(t *testing.T) *config.Config {
+	t.Helper()
+	c, err := config.Default()
+	if err != nil {
+		t.Fatalf("config.Default() error = %v", err)
+	}
+	return c
+}
+
+func TestResolveSeriesArgs(t *testing.T) {
+	c := testConfig(t)
+	c.CLI.DefaultBase = "EUR"
+	c.CLI.DefaultTargets = []string{"USD", "GBP"}
+	c.CLI.DefaultFrom = ""
+
+	args, err := resolveSeriesArgs(c, "", "", "", "2024-03-15")
+	if err != nil {
+		t.Fatalf("resolveSeriesArgs() error = %v", err)
+	}
+	if args.Base != "EUR" || len(args.Targets) != 2 || args.Targets[1] != "GBP" {
+		t.Errorf("defaults = %s %v, want EUR [USD GBP]", args.Base, args.Targets)
+	}
+	if args.EndDate.Format("2006-01-02") != "2024-03-15" || args.StartDate.Format("2006-01-02") != "2023-03-15" {
+		t.Errorf("period = %s..%s, want a year up to 2024-03-15", args.StartDate.Format("2006-01-02"), args.EndDate.Format("2006-01-02"))
+	}
+
+	args, err = resolveSeriesArgs(c, "usd", "huf, jpy", "2024-01-02", "2024-03-15")
+	if err != nil {
+		t.Fatalf("resolveSeriesArgs() error = %v", err)
+	}
+	if args.Base != "USD" || len(args.Targets) != 2 || args.Targets[0] != "HUF" || !args.StartDate.Equal(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("flags = %+v, want USD [HUF JPY] from 2024-01-02", args)
+	}
+
+	if _, err := resolveSeriesArgs(c, "", "", "", "15/03/2024"); err == nil {
+		t.Error("expected an error for an invalid end date")
+	}
+	c.CLI.DefaultTargets = nil
+	if _, err := resolveSeriesArgs(c, "", "", "", ""); err == nil {
+		t.Error("expected an error without currencies")
+	}
+}
+
+func TestNewProviderRegistry(t *testing.T) {
+	c := testConfig(t)
+	c.Provider.DataDir = t.TempDir()
+
+	registry, err := newProviderRegistry(c)
+	if err != nil {
+		t.Fatalf("newProviderRegistry() error = %v", err)
+	}
+	if names := registry.Names(); len(names) != 3 {
+		t.Errorf("Names() = %v, want ecb, file and frankfurter", names)
+	}
+
+	_, caps, _ := registry.Get("frankfurter")
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	if !caps.Supports(day, day, "EUR", []string{"HUF", "USD"}) {
+		t.Error("frankfurter should support EUR/HUF and EUR/USD")
+	}
+	if caps.Supports(day, day, "EUR", []string{"XAU"}) {
+		t.Error("frankfurter should not claim currencies outside the ECB reference rates")
+	}
+}
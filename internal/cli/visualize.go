@@ -3,17 +3,15 @@ package cli
 import (
 	"context"
 	"fmt"
-	"os"
-	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 
-	"github.com/kaze/xrv/internal/cache"
-	"github.com/kaze/xrv/internal/providers"
 	"github.com/kaze/xrv/internal/domain"
+	"github.com/kaze/xrv/internal/providers"
 	"github.com/kaze/xrv/internal/service"
+	"github.com/kaze/xrv/internal/statistics"
 	"github.com/kaze/xrv/internal/visualization/browser"
 	"github.com/kaze/xrv/internal/visualization/terminal"
 )
@@ -29,6 +27,12 @@ var (
 	vizOutput      string
 	vizPort        int
 	vizInvert      bool
+	vizRebase      bool
+	vizInterval    string
+	vizAggregation string
+	vizChart       string
+	vizGaps        string
+	vizMA          string
 	vizInteractive bool
 )
 
@@ -49,6 +53,12 @@ func NewVisualizeCommand() *cobra.Command {
 	cmd.Flags().IntVar(&vizPort, "port", 8080, "Port for browser mode (default: 8080)")
 	cmd.Flags().BoolVarP(&vizInteractive, "interactive", "i", false, "Interactive mode (browser with form)")
 	cmd.Flags().BoolVar(&vizInvert, "invert", false, "Invert rates (show base in target currency)")
+	cmd.Flags().BoolVar(&vizRebase, "rebase", false, "Derive the base locally from cached rates against the pivot currency")
+	cmd.Flags().StringVar(&vizInterval, "interval", "day", "Resample to day, week, month, quarter or year")
+	cmd.Flags().StringVar(&vizAggregation, "agg", "last", "Aggregation when resampling: last, mean, ohlc, min, max")
+	cmd.Flags().StringVar(&vizChart, "chart", "line", "Chart type: line, candle (weekly OHLC bars unless --interval is set)")
+	cmd.Flags().StringVar(&vizGaps, "gaps", "", "Missing fixings: omit, ffill, interpolate, null (default from calendar.gaps)")
+	cmd.Flags().StringVar(&vizMA, "ma", "", "Moving averages to overlay, e.g. sma20,ema50,wma10 (default from statistics.*_periods)")
 	cmd.Flags().BoolVar(&vizNoCache, "no-cache", false, "Disable caching")
 	cmd.Flags().IntVar(&vizHeight, "height", 15, "Chart height (terminal mode)")
 	cmd.Flags().IntVar(&vizWidth, "width", 80, "Chart width (terminal mode)")
@@ -57,70 +67,110 @@ func NewVisualizeCommand() *cobra.Command {
 }
 
 func runVisualize(cmd *cobra.Command, args []string) error {
-	apiClient := providers.NewFrankfurterClient("https://api.frankfurter.dev/v1", 30*time.Second, 3)
+	cfg, err := currentConfig()
+	if err != nil {
+		return err
+	}
 
-	homeDir, err := os.UserHomeDir()
+	apiClient, err := newAPIClient(cfg)
 	if err != nil {
-		return fmt.Errorf("failed to get home directory: %w", err)
+		return err
 	}
-	cacheDir := filepath.Join(homeDir, ".xrv", "cache")
-	badgerCache, err := cache.NewBadgerCache(cacheDir)
+
+	dataCache, err := openCache(cfg)
 	if err != nil {
-		return fmt.Errorf("failed to initialize cache: %w", err)
+		return err
 	}
-	defer badgerCache.Close()
+	defer dataCache.Close()
 
-	svc := service.NewService(apiClient, badgerCache)
+	var extra []service.Option
+	if cmd.Flags().Changed("ma") {
+		averages, err := statistics.ParseMovingAverages(strings.Split(vizMA, ","))
+		if err != nil {
+			return err
+		}
+		extra = append(extra, service.WithMovingAverages(averages))
+	}
 
-	if vizInteractive || (vizOutput == "browser" && vizBase == "" && vizCurrencies == "") {
-		server := browser.NewServer(vizPort, svc, apiClient)
-		return server.Start()
+	svc := newService(cfg, apiClient, dataCache, extra...)
+
+	output := cfg.Visualization.DefaultOutput
+	if cmd.Flags().Changed("output") {
+		output = vizOutput
+	}
+	port := cfg.Visualization.Port
+	if cmd.Flags().Changed("port") {
+		port = vizPort
+	}
+	height := cfg.Visualization.ChartHeight
+	if cmd.Flags().Changed("height") {
+		height = vizHeight
+	}
+	width := cfg.Visualization.ChartWidth
+	if cmd.Flags().Changed("width") {
+		width = vizWidth
+	}
+	rebase := cfg.Cache.Rebase
+	if cmd.Flags().Changed("rebase") {
+		rebase = vizRebase
+	}
+	gapsValue := cfg.Calendar.Gaps
+	if cmd.Flags().Changed("gaps") {
+		gapsValue = vizGaps
+	}
+	gaps, err := service.ParseGapPolicy(gapsValue)
+	if err != nil {
+		return err
 	}
 
-	if vizBase == "" {
-		vizBase = "USD"
+	if vizInteractive || (output == "browser" && vizBase == "" && vizCurrencies == "") {
+		server := browser.NewServer(port, svc, apiClient,
+			browser.WithTheme(cfg.Visualization.Theme),
+			browser.WithRebase(rebase),
+			browser.WithGapPolicy(gaps),
+		)
+		return server.Start()
 	}
-	if vizCurrencies == "" {
-		vizCurrencies = "EUR,GBP,JPY"
+
+	series, err := resolveSeriesArgs(cfg, vizBase, vizCurrencies, vizFrom, vizTo)
+	if err != nil {
+		return err
 	}
 
 	ctx := context.Background()
 
-	endDate := time.Now()
-	if vizTo != "" {
-		var err error
-		endDate, err = time.Parse("2006-01-02", vizTo)
-		if err != nil {
-			return fmt.Errorf("invalid end date format: %w", err)
-		}
+	interval, err := service.ParseInterval(vizInterval)
+	if err != nil {
+		return err
 	}
-
-	startDate := endDate.AddDate(-1, 0, 0) // Default to 1 year ago
-	if vizFrom != "" {
-		var err error
-		startDate, err = time.Parse("2006-01-02", vizFrom)
-		if err != nil {
-			startDate, err = parseRelativeDate(vizFrom, endDate)
-			if err != nil {
-				return fmt.Errorf("invalid start date format: %w", err)
-			}
-		}
+	aggregation, err := service.ParseAggregation(vizAggregation)
+	if err != nil {
+		return err
 	}
-
-	targets := strings.Split(vizCurrencies, ",")
-	targetCurrencies := make([]domain.Currency, len(targets))
-	for i, t := range targets {
-		targetCurrencies[i] = domain.Currency(strings.TrimSpace(t))
+	switch strings.ToLower(vizChart) {
+	case "line":
+	case "candle":
+		interval, aggregation = service.CandleResampling(interval)
+	default:
+		return fmt.Errorf("unsupported chart type: %s (use 'line' or 'candle')", vizChart)
 	}
 
-	fmt.Println("Fetching exchange rate data...")
-	data, err := svc.FetchTimeSeriesData(ctx, service.FetchOptions{
-		Base:      domain.Currency(vizBase),
-		Targets:   targetCurrencies,
-		StartDate: startDate,
-		EndDate:   endDate,
-		UseCache:  !vizNoCache,
+	fmt.Print("Fetching exchange rate data...")
+	fetchCtx := providers.WithProgress(ctx, func(done, total int) {
+		fmt.Printf("\rFetching exchange rate data... %d/%d chunks", done, total)
+	})
+	data, err := svc.FetchTimeSeriesData(fetchCtx, service.FetchOptions{
+		Base:        series.Base,
+		Targets:     series.Targets,
+		StartDate:   series.StartDate,
+		EndDate:     series.EndDate,
+		UseCache:    cfg.Cache.Enabled && !vizNoCache,
+		Rebase:      rebase,
+		Interval:    interval,
+		Aggregation: aggregation,
+		Gaps:        gaps,
 	})
+	fmt.Println()
 	if err != nil {
 		return fmt.Errorf("failed to fetch data: %w", err)
 	}
@@ -131,15 +181,27 @@ func runVisualize(cmd *cobra.Command, args []string) error {
 
 	stats := svc.CalculateStatistics(data)
 
-	switch strings.ToLower(vizOutput) {
+	switch strings.ToLower(output) {
 	case "browser":
-		renderer := browser.NewRenderer(vizPort)
+		renderer := browser.NewRenderer(port,
+			browser.WithTheme(cfg.Visualization.Theme),
+			browser.WithCorrelations(svc.CalculateCorrelations(data)),
+		)
 		return renderer.Render(data, stats)
 	case "terminal":
-		renderer := terminal.NewRenderer(vizHeight, vizWidth)
-		return renderer.Render(data, stats)
+		renderer := terminal.NewRenderer(height, width,
+			terminal.WithVolatility(cfg.Statistics.ShowVolatility),
+			terminal.WithTrends(cfg.Statistics.ShowTrends),
+			terminal.WithIndicators(cfg.Statistics.ShowIndicators),
+			terminal.WithCandles(aggregation == service.AggregateOHLC),
+		)
+		if err := renderer.Render(data, stats); err != nil {
+			return err
+		}
+		renderer.RenderCorrelations(svc.CalculateCorrelations(data))
+		return nil
 	default:
-		return fmt.Errorf("unsupported output mode: %s (use 'terminal' or 'browser')", vizOutput)
+		return fmt.Errorf("unsupported output mode: %s (use 'terminal' or 'browser')", output)
 	}
 }
 
@@ -150,6 +212,9 @@ func invertRates(data *domain.TimeSeriesData) *domain.TimeSeriesData {
 		StartDate:  data.StartDate,
 		EndDate:    data.EndDate,
 		DataPoints: make([]domain.DataPoint, len(data.DataPoints)),
+		Sources:    data.Sources,
+		DerivedVia: data.DerivedVia,
+		Interval:   data.Interval,
 	}
 
 	for i, dp := range data.DataPoints {
@@ -163,11 +228,24 @@ func invertRates(data *domain.TimeSeriesData) *domain.TimeSeriesData {
 			Date:  dp.Date,
 			Rates: invertedRates,
 		}
+		if dp.OHLC != nil {
+			inverted.DataPoints[i].OHLC = make(map[domain.Currency]domain.OHLC, len(dp.OHLC))
+			for currency, bar := range dp.OHLC {
+				inverted.DataPoints[i].OHLC[currency] = bar.Invert()
+			}
+		}
 	}
 
 	return inverted
 }
 
+func parseDate(value string, base time.Time) (time.Time, error) {
+	if date, err := time.Parse("2006-01-02", value); err == nil {
+		return date, nil
+	}
+	return parseRelativeDate(value, base)
+}
+
 func parseRelativeDate(relative string, base time.Time) (time.Time, error) {
 	relative = strings.ToLower(strings.TrimSpace(relative))
 
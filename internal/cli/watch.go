@@ -0,0 +1,171 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kaze/xrv/internal/alerts"
+	"github.com/kaze/xrv/internal/domain"
+	"github.com/kaze/xrv/internal/service"
+	"github.com/kaze/xrv/internal/statistics"
+	"github.com/kaze/xrv/internal/visualization/terminal"
+	"github.com/kaze/xrv/internal/watch"
+)
+
+var (
+	watchBase       string
+	watchCurrencies string
+	watchFrom       string
+	watchRules      string
+	watchGaps       string
+	watchHeight     int
+	watchWidth      int
+)
+
+// watchAlertHistory is how many of the latest alerts stay on screen.
+const watchAlertHistory = 5
+
+func NewWatchCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Keep the terminal chart up to date and highlight alerts as fixings arrive",
+		Long: `Poll the provider for new fixings and redraw the terminal chart in place.
+
+Reference rates are published once per business day, around 16:00 CET for
+the ECB. The watch polls at watch.publication (in watch.timezone) on every
+business day of calendar.name, then every watch.retry until the day's
+fixing arrives, and sleeps until the next business day once it has. Only
+the days missing from the cache are fetched.
+
+The rules of alerts.rules_file are checked on every poll and a highlighted
+line is printed when one fires on a new fixing. Stop with Ctrl+C.`,
+		Example: `  xrv watch --base EUR --currencies HUF,USD
+  xrv watch -b EUR -c HUF --from "90 days ago" --rules configs/alerts.yaml`,
+		Args: cobra.NoArgs,
+		RunE: runWatch,
+	}
+
+	cmd.Flags().StringVarP(&watchBase, "base", "b", "", "Base currency, defaults to cli.default_base")
+	cmd.Flags().StringVarP(&watchCurrencies, "currencies", "c", "", "Target currencies, defaults to cli.default_targets")
+	cmd.Flags().StringVarP(&watchFrom, "from", "f", "", "Start of the rolling window, relative (e.g., '90 days ago') or YYYY-MM-DD")
+	cmd.Flags().StringVarP(&watchRules, "rules", "r", "", "Alert rules file, defaults to alerts.rules_file when it exists")
+	cmd.Flags().StringVar(&watchGaps, "gaps", "", "Missing fixings: omit, ffill, interpolate, null (default from calendar.gaps)")
+	cmd.Flags().IntVar(&watchHeight, "height", 15, "Chart height")
+	cmd.Flags().IntVar(&watchWidth, "width", 80, "Chart width")
+
+	return cmd
+}
+
+func runWatch(cmd *cobra.Command, args []string) error {
+	cfg, err := currentConfig()
+	if err != nil {
+		return err
+	}
+
+	series, err := resolveSeriesArgs(cfg, watchBase, watchCurrencies, watchFrom, "")
+	if err != nil {
+		return err
+	}
+	days := int(series.EndDate.Sub(series.StartDate).Hours() / 24)
+	if days < 1 {
+		return fmt.Errorf("start date must be before today")
+	}
+
+	gapsValue := cfg.Calendar.Gaps
+	if cmd.Flags().Changed("gaps") {
+		gapsValue = watchGaps
+	}
+	gaps, err := service.ParseGapPolicy(gapsValue)
+	if err != nil {
+		return err
+	}
+
+	var rules []alerts.Rule
+	if watchRules != "" {
+		if rules, err = alerts.LoadRules(watchRules); err != nil {
+			return err
+		}
+	} else if rules, err = alerts.LoadRules(cfg.Alerts.RulesFile); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	schedule, err := watch.NewSchedule(newCalendar(cfg), cfg.Watch.Timezone, cfg.Watch.Publication, cfg.Watch.Retry)
+	if err != nil {
+		return err
+	}
+
+	apiClient, err := newAPIClient(cfg)
+	if err != nil {
+		return err
+	}
+
+	dataCache, err := openCache(cfg)
+	if err != nil {
+		return err
+	}
+	defer dataCache.Close()
+
+	// The day's entries must expire before the next retry, or a fixing
+	// cached as missing before publication would hide the published one.
+	svc := newService(cfg, apiClient, dataCache,
+		service.WithCurrentDayTTL(min(cfg.Cache.TTLCurrentDay, cfg.Watch.Retry)))
+
+	height := cfg.Visualization.ChartHeight
+	if cmd.Flags().Changed("height") {
+		height = watchHeight
+	}
+	width := cfg.Visualization.ChartWidth
+	if cmd.Flags().Changed("width") {
+		width = watchWidth
+	}
+	renderer := terminal.NewRenderer(height, width,
+		terminal.WithVolatility(false),
+		terminal.WithTrends(cfg.Statistics.ShowTrends),
+		terminal.WithIndicators(false),
+	)
+
+	watcher := watch.New(svc, watch.Options{
+		Base:     series.Base,
+		Targets:  series.Targets,
+		Days:     days,
+		UseCache: cfg.Cache.Enabled,
+		Rebase:   cfg.Cache.Rebase,
+		Gaps:     gaps,
+		Rules:    rules,
+		Schedule: schedule,
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var data *domain.TimeSeriesData
+	var stats map[string]statistics.Statistics
+	var fired []alerts.Alert
+	var renderErr error
+	watcher.Run(ctx, func(update watch.Update) {
+		if update.Data != nil {
+			data, stats = update.Data, svc.CalculateStatistics(update.Data)
+		}
+		fired = append(fired, update.Alerts...)
+		if len(fired) > watchAlertHistory {
+			fired = fired[len(fired)-watchAlertHistory:]
+		}
+		if err := renderer.RenderWatch(data, stats, fired, update); err != nil {
+			renderErr = err
+			stop()
+		}
+	})
+
+	if renderErr != nil {
+		return renderErr
+	}
+	fmt.Println("\nStopped watching.")
+	return nil
+}
@@ -0,0 +1,16 @@
+package cli
+
+import "testing"
+
+func TestWatchCommand(t *testing.T) {
+	cmd := NewWatchCommand()
+	for _, flag := range []string{"base", "currencies", "from", "rules", "gaps", "height", "width"} {
+		if cmd.Flags().Lookup(flag) == nil {
+			t.Errorf("Flag %s not defined", flag)
+		}
+	}
+
+	if found, _, err := NewRootCommand().Find([]string{"watch"}); err != nil || found.Name() != "watch" {
+		t.Errorf("watch is not registered: %v", err)
+	}
+}
@@ -0,0 +1,325 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	_ "time/tzdata" // watch.timezone must resolve without a system zoneinfo
+
+	"github.com/spf13/viper"
+
+	"github.com/kaze/xrv/internal/calendar"
+	"github.com/kaze/xrv/internal/statistics"
+)
+
+const EnvPrefix = "XRV"
+
+type APIConfig struct {
+	BaseURL       string        `mapstructure:"base_url"`
+	Timeout       time.Duration `mapstructure:"timeout"`
+	RetryAttempts int           `mapstructure:"retry_attempts"`
+	ChunkDays     int           `mapstructure:"chunk_days"`
+	Concurrency   int           `mapstructure:"concurrency"`
+	RateLimit     float64       `mapstructure:"rate_limit"`
+	RateBurst     int           `mapstructure:"rate_burst"`
+}
+
+type ProviderConfig struct {
+	Name      string   `mapstructure:"name"`
+	Fallback  []string `mapstructure:"fallback"`
+	DataDir   string   `mapstructure:"data_dir"`
+	ECBSource string   `mapstructure:"ecb_source"`
+	Pivot     string   `mapstructure:"pivot"`
+}
+
+type CacheConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	Type          string        `mapstructure:"type"`
+	Directory     string        `mapstructure:"directory"`
+	TTLCurrentDay time.Duration `mapstructure:"ttl_current_day"`
+	Rebase        bool          `mapstructure:"rebase"`
+}
+
+type VisualizationConfig struct {
+	DefaultOutput string `mapstructure:"default_output"`
+	Theme         string `mapstructure:"theme"`
+	ChartHeight   int    `mapstructure:"chart_height"`
+	ChartWidth    int    `mapstructure:"chart_width"`
+	Port          int    `mapstructure:"port"`
+}
+
+type CLIConfig struct {
+	DefaultBase    string   `mapstructure:"default_base"`
+	DefaultTargets []string `mapstructure:"default_targets"`
+	DefaultFrom    string   `mapstructure:"default_from"`
+}
+
+type ConvertConfig struct {
+	LookbackDays int    `mapstructure:"lookback_days"`
+	RateRule     string `mapstructure:"rate_rule"`
+}
+
+type CalendarConfig struct {
+	Name     string   `mapstructure:"name"`
+	Holidays []string `mapstructure:"holidays"`
+	Gaps     string   `mapstructure:"gaps"`
+}
+
+type StatisticsConfig struct {
+	SMAPeriods        []int     `mapstructure:"sma_periods"`
+	EMAPeriods        []int     `mapstructure:"ema_periods"`
+	WMAPeriods        []int     `mapstructure:"wma_periods"`
+	RSIPeriod         int       `mapstructure:"rsi_period"`
+	MACD              []int     `mapstructure:"macd"`
+	BollingerPeriod   int       `mapstructure:"bollinger_period"`
+	BollingerWidth    float64   `mapstructure:"bollinger_width"`
+	CorrelationWindow int       `mapstructure:"correlation_window"`
+	VolatilityWindows []int     `mapstructure:"volatility_windows"`
+	EWMALambda        float64   `mapstructure:"ewma_lambda"`
+	VaRConfidence     []float64 `mapstructure:"var_confidence"`
+	VaRHorizons       []int     `mapstructure:"var_horizons"`
+	ShowVolatility    bool      `mapstructure:"show_volatility"`
+	ShowTrends        bool      `mapstructure:"show_trends"`
+	ShowIndicators    bool      `mapstructure:"show_indicators"`
+}
+
+type AlertsConfig struct {
+	RulesFile      string        `mapstructure:"rules_file"`
+	Notifiers      []string      `mapstructure:"notifiers"`
+	LogFile        string        `mapstructure:"log_file"`
+	WebhookURL     string        `mapstructure:"webhook_url"`
+	WebhookTimeout time.Duration `mapstructure:"webhook_timeout"`
+}
+
+type WatchConfig struct {
+	Publication string        `mapstructure:"publication"`
+	Timezone    string        `mapstructure:"timezone"`
+	Retry       time.Duration `mapstructure:"retry"`
+}
+
+type Config struct {
+	API           APIConfig           `mapstructure:"api"`
+	Provider      ProviderConfig      `mapstructure:"provider"`
+	Cache         CacheConfig         `mapstructure:"cache"`
+	Visualization VisualizationConfig `mapstructure:"visualization"`
+	CLI           CLIConfig           `mapstructure:"cli"`
+	Convert       ConvertConfig       `mapstructure:"convert"`
+	Calendar      CalendarConfig      `mapstructure:"calendar"`
+	Statistics    StatisticsConfig    `mapstructure:"statistics"`
+	Alerts        AlertsConfig        `mapstructure:"alerts"`
+	Watch         WatchConfig         `mapstructure:"watch"`
+}
+
+func SetDefaults(v *viper.Viper) {
+	v.SetDefault("api.base_url", "https://api.frankfurter.dev/v1")
+	v.SetDefault("api.timeout", 30*time.Second)
+	v.SetDefault("api.retry_attempts", 3)
+	v.SetDefault("api.chunk_days", 365)
+	v.SetDefault("api.concurrency", 4)
+	v.SetDefault("api.rate_limit", 5.0)
+	v.SetDefault("api.rate_burst", 5)
+
+	v.SetDefault("provider.name", "frankfurter")
+	v.SetDefault("provider.fallback", []string{})
+	v.SetDefault("provider.data_dir", "")
+	v.SetDefault("provider.pivot", "EUR")
+	v.SetDefault("provider.ecb_source", "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-hist.zip")
+
+	v.SetDefault("cache.enabled", true)
+	v.SetDefault("cache.type", "badger")
+	v.SetDefault("cache.directory", "~/.xrv/cache")
+	v.SetDefault("cache.ttl_current_day", time.Hour)
+	v.SetDefault("cache.rebase", false)
+
+	v.SetDefault("visualization.default_output", "terminal")
+	v.SetDefault("visualization.theme", "default")
+	v.SetDefault("visualization.chart_height", 15)
+	v.SetDefault("visualization.chart_width", 80)
+	v.SetDefault("visualization.port", 8080)
+
+	v.SetDefault("cli.default_base", "USD")
+	v.SetDefault("cli.default_targets", []string{"EUR", "GBP", "JPY"})
+	v.SetDefault("cli.default_from", "1 year ago")
+
+	v.SetDefault("convert.lookback_days", 7)
+	v.SetDefault("convert.rate_rule", "previous")
+
+	v.SetDefault("calendar.name", "target2")
+	v.SetDefault("calendar.holidays", []string{})
+	v.SetDefault("calendar.gaps", "omit")
+
+	v.SetDefault("statistics.sma_periods", []int{20, 50})
+	v.SetDefault("statistics.ema_periods", []int{12, 26})
+	v.SetDefault("statistics.wma_periods", []int{})
+	v.SetDefault("statistics.rsi_period", 14)
+	v.SetDefault("statistics.macd", []int{12, 26, 9})
+	v.SetDefault("statistics.bollinger_period", 20)
+	v.SetDefault("statistics.bollinger_width", 2.0)
+	v.SetDefault("statistics.correlation_window", 30)
+	v.SetDefault("statistics.volatility_windows", []int{30, 90})
+	v.SetDefault("statistics.ewma_lambda", 0.94)
+	v.SetDefault("statistics.var_confidence", []float64{0.95, 0.99})
+	v.SetDefault("statistics.var_horizons", []int{1, 10})
+	v.SetDefault("statistics.show_volatility", true)
+	v.SetDefault("statistics.show_trends", true)
+	v.SetDefault("statistics.show_indicators", true)
+
+	v.SetDefault("alerts.rules_file", "~/.xrv/alerts.yaml")
+	v.SetDefault("alerts.notifiers", []string{"stdout"})
+	v.SetDefault("alerts.log_file", "~/.xrv/alerts.log")
+	v.SetDefault("alerts.webhook_url", "")
+	v.SetDefault("alerts.webhook_timeout", 10*time.Second)
+
+	v.SetDefault("watch.publication", "16:10")
+	v.SetDefault("watch.timezone", "Europe/Berlin")
+	v.SetDefault("watch.retry", 15*time.Minute)
+}
+
+func Load(v *viper.Viper, file string) (*Config, error) {
+	SetDefaults(v)
+
+	v.SetEnvPrefix(EnvPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_", "-", "_"))
+	v.AutomaticEnv()
+
+	if file != "" {
+		path, err := ExpandPath(file)
+		if err != nil {
+			return nil, err
+		}
+		v.SetConfigFile(path)
+		if err := v.ReadInConfig(); err != nil {
+			return nil, fmt.Errorf("failed to read config file %s: %w", file, err)
+		}
+	} else {
+		v.SetConfigName("config")
+		v.SetConfigType("yaml")
+		if home, err := os.UserHomeDir(); err == nil {
+			v.AddConfigPath(filepath.Join(home, ".xrv"))
+		}
+		if err := v.ReadInConfig(); err != nil {
+			var notFound viper.ConfigFileNotFoundError
+			if !errors.As(err, &notFound) {
+				return nil, fmt.Errorf("failed to read config file: %w", err)
+			}
+		}
+	}
+
+	var cfg Config
+	if err := v.Unmarshal(&cfg); err != nil {
+		return nil, fmt.Errorf("failed to decode config: %w", err)
+	}
+
+	for i, t := range cfg.CLI.DefaultTargets {
+		cfg.CLI.DefaultTargets[i] = strings.ToUpper(strings.TrimSpace(t))
+	}
+	cfg.CLI.DefaultBase = strings.ToUpper(strings.TrimSpace(cfg.CLI.DefaultBase))
+
+	cfg.Provider.Name = strings.ToLower(strings.TrimSpace(cfg.Provider.Name))
+	cfg.Provider.Pivot = strings.ToUpper(strings.TrimSpace(cfg.Provider.Pivot))
+	for i, name := range cfg.Provider.Fallback {
+		cfg.Provider.Fallback[i] = strings.ToLower(strings.TrimSpace(name))
+	}
+
+	cfg.Calendar.Name = strings.ToLower(strings.TrimSpace(cfg.Calendar.Name))
+	if _, err := calendar.Parse(cfg.Calendar.Name); err != nil {
+		return nil, err
+	}
+	for _, day := range cfg.Calendar.Holidays {
+		if _, err := time.Parse("2006-01-02", day); err != nil {
+			return nil, fmt.Errorf("invalid calendar holiday %s: %w", day, err)
+		}
+	}
+
+	if len(cfg.Statistics.MACD) != 0 && len(cfg.Statistics.MACD) != 3 {
+		return nil, fmt.Errorf("statistics.macd must list the fast, slow and signal periods, got %v", cfg.Statistics.MACD)
+	}
+
+	if lambda := cfg.Statistics.EWMALambda; lambda <= 0 || lambda >= 1 {
+		return nil, fmt.Errorf("statistics.ewma_lambda must lie between 0 and 1, got %g", lambda)
+	}
+
+	if err := statistics.ValidateRiskOptions(statistics.RiskOptions{
+		Confidences: cfg.Statistics.VaRConfidence,
+		Horizons:    cfg.Statistics.VaRHorizons,
+	}); err != nil {
+		return nil, fmt.Errorf("statistics.var_confidence or var_horizons: %w", err)
+	}
+
+	if err := expandPaths(&cfg); err != nil {
+		return nil, err
+	}
+
+	for i, name := range cfg.Alerts.Notifiers {
+		cfg.Alerts.Notifiers[i] = strings.ToLower(strings.TrimSpace(name))
+		switch cfg.Alerts.Notifiers[i] {
+		case "stdout", "file":
+		case "webhook":
+			if cfg.Alerts.WebhookURL == "" {
+				return nil, fmt.Errorf("alerts.webhook_url is required by the webhook notifier")
+			}
+		default:
+			return nil, fmt.Errorf("unknown alert notifier %s (use 'stdout', 'file' or 'webhook')", name)
+		}
+	}
+	if _, err := time.Parse("15:04", cfg.Watch.Publication); err != nil {
+		return nil, fmt.Errorf("watch.publication must be HH:MM, got %q", cfg.Watch.Publication)
+	}
+	if _, err := time.LoadLocation(cfg.Watch.Timezone); err != nil {
+		return nil, fmt.Errorf("invalid watch.timezone: %w", err)
+	}
+	if cfg.Watch.Retry <= 0 {
+		return nil, fmt.Errorf("watch.retry must be positive, got %s", cfg.Watch.Retry)
+	}
+
+	return &cfg, nil
+}
+
+func Default() (*Config, error) {
+	v := viper.New()
+	SetDefaults(v)
+
+	var cfg Config
+	if err := v.Unmarshal(&cfg); err != nil {
+		return nil, fmt.Errorf("failed to decode default config: %w", err)
+	}
+	if err := expandPaths(&cfg); err != nil {
+		return nil, err
+	}
+
+	return &cfg, nil
+}
+
+// expandPaths resolves a leading ~ in every path setting.
+func expandPaths(cfg *Config) error {
+	for _, path := range []*string{
+		&cfg.Cache.Directory,
+		&cfg.Provider.DataDir,
+		&cfg.Provider.ECBSource,
+		&cfg.Alerts.RulesFile,
+		&cfg.Alerts.LogFile,
+	} {
+		expanded, err := ExpandPath(*path)
+		if err != nil {
+			return err
+		}
+		*path = expanded
+	}
+	return nil
+}
+
+func ExpandPath(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	return filepath.Join(home, strings.TrimPrefix(path, "~")), nil
+}
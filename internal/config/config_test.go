@@ -0,0 +1,235 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	return path
+}
+
+func TestDefault(t *testing.T) {
+	cfg, err := Default()
+	if err != nil {
+		t.Fatalf("Default() error = %v", err)
+	}
+
+	if cfg.API.BaseURL != "https://api.frankfurter.dev/v1" {
+		t.Errorf("API.BaseURL = %s, want https://api.frankfurter.dev/v1", cfg.API.BaseURL)
+	}
+	if cfg.API.Timeout != 30*time.Second {
+		t.Errorf("API.Timeout = %v, want 30s", cfg.API.Timeout)
+	}
+	if cfg.API.RetryAttempts != 3 {
+		t.Errorf("API.RetryAttempts = %d, want 3", cfg.API.RetryAttempts)
+	}
+	if cfg.CLI.DefaultBase != "USD" {
+		t.Errorf("CLI.DefaultBase = %s, want USD", cfg.CLI.DefaultBase)
+	}
+	if len(cfg.CLI.DefaultTargets) != 3 {
+		t.Errorf("CLI.DefaultTargets = %v, want 3 currencies", cfg.CLI.DefaultTargets)
+	}
+	if filepath.Base(cfg.Cache.Directory) != "cache" || cfg.Cache.Directory[0] == '~' {
+		t.Errorf("Cache.Directory = %s, want expanded ~/.xrv/cache", cfg.Cache.Directory)
+	}
+	if filepath.Base(cfg.Alerts.RulesFile) != "alerts.yaml" || cfg.Alerts.RulesFile[0] == '~' {
+		t.Errorf("Alerts.RulesFile = %s, want expanded ~/.xrv/alerts.yaml", cfg.Alerts.RulesFile)
+	}
+}
+
+func TestLoad_File(t *testing.T) {
+	path := writeConfig(t, `
+api:
+  base_url: "http://localhost:9000"
+  timeout: 5s
+  retry_attempts: 1
+cache:
+  directory: "/tmp/xrv-test-cache"
+  ttl_current_day: "10m"
+visualization:
+  chart_height: 20
+cli:
+  default_base: "eur"
+  default_targets: ["USD", "GBP"]
+statistics:
+  sma_periods: [20, 50, 200]
+`)
+
+	cfg, err := Load(viper.New(), path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if cfg.API.BaseURL != "http://localhost:9000" {
+		t.Errorf("API.BaseURL = %s, want http://localhost:9000", cfg.API.BaseURL)
+	}
+	if cfg.API.Timeout != 5*time.Second {
+		t.Errorf("API.Timeout = %v, want 5s", cfg.API.Timeout)
+	}
+	if cfg.Cache.TTLCurrentDay != 10*time.Minute {
+		t.Errorf("Cache.TTLCurrentDay = %v, want 10m", cfg.Cache.TTLCurrentDay)
+	}
+	if cfg.Cache.Directory != "/tmp/xrv-test-cache" {
+		t.Errorf("Cache.Directory = %s, want /tmp/xrv-test-cache", cfg.Cache.Directory)
+	}
+	if cfg.Visualization.ChartHeight != 20 {
+		t.Errorf("Visualization.ChartHeight = %d, want 20", cfg.Visualization.ChartHeight)
+	}
+	if cfg.Visualization.ChartWidth != 80 {
+		t.Errorf("Visualization.ChartWidth = %d, want default 80", cfg.Visualization.ChartWidth)
+	}
+	if cfg.CLI.DefaultBase != "EUR" {
+		t.Errorf("CLI.DefaultBase = %s, want EUR", cfg.CLI.DefaultBase)
+	}
+	if len(cfg.Statistics.SMAPeriods) != 3 || cfg.Statistics.SMAPeriods[2] != 200 {
+		t.Errorf("Statistics.SMAPeriods = %v, want [20 50 200]", cfg.Statistics.SMAPeriods)
+	}
+}
+
+func TestLoad_MissingFile(t *testing.T) {
+	_, err := Load(viper.New(), filepath.Join(t.TempDir(), "missing.yaml"))
+	if err == nil {
+		t.Error("Expected error for missing explicit config file, got nil")
+	}
+}
+
+func TestLoad_InvalidCalendar(t *testing.T) {
+	for _, content := range []string{
+		"calendar:\n  name: \"nyse\"\n",
+		"calendar:\n  holidays: [\"15/03/2024\"]\n",
+	} {
+		if _, err := Load(viper.New(), writeConfig(t, content)); err == nil {
+			t.Errorf("Load() with %q should fail", content)
+		}
+	}
+}
+
+func TestLoad_InvalidStatistics(t *testing.T) {
+	for _, content := range []string{
+		"statistics:\n  macd: [12, 26]\n",
+		"statistics:\n  ewma_lambda: 1.5\n",
+		"statistics:\n  var_confidence: [95]\n",
+	} {
+		if _, err := Load(viper.New(), writeConfig(t, content)); err == nil {
+			t.Errorf("Load() with %q should fail", content)
+		}
+	}
+}
+
+func TestLoad_Alerts(t *testing.T) {
+	cfg, err := Load(viper.New(), writeConfig(t, "alerts:\n  notifiers: [Webhook, file]\n  webhook_url: http://localhost/hook\n"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(cfg.Alerts.Notifiers) != 2 || cfg.Alerts.Notifiers[0] != "webhook" || strings.HasPrefix(cfg.Alerts.RulesFile, "~") {
+		t.Errorf("Alerts = %+v, want lower-case notifiers and an expanded rules file", cfg.Alerts)
+	}
+
+	for _, content := range []string{
+		"alerts:\n  notifiers: [pager]\n",
+		"alerts:\n  notifiers: [webhook]\n",
+	} {
+		if _, err := Load(viper.New(), writeConfig(t, content)); err == nil {
+			t.Errorf("Load() with %q should fail", content)
+		}
+	}
+}
+
+func TestLoad_Watch(t *testing.T) {
+	cfg, err := Load(viper.New(), writeConfig(t, "watch:\n  publication: \"16:30\"\n  retry: 5m\n"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Watch.Publication != "16:30" || cfg.Watch.Timezone != "Europe/Berlin" || cfg.Watch.Retry != 5*time.Minute {
+		t.Errorf("Watch = %+v", cfg.Watch)
+	}
+
+	for _, content := range []string{
+		"watch:\n  publication: 4pm\n",
+		"watch:\n  timezone: Europe/Atlantis\n",
+		"watch:\n  retry: 0s\n",
+	} {
+		if _, err := Load(viper.New(), writeConfig(t, content)); err == nil {
+			t.Errorf("Load() with %q should fail", content)
+		}
+	}
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	path := writeConfig(t, `
+api:
+  retry_attempts: 5
+cli:
+  default_base: "EUR"
+cache:
+  directory: "/from/file"
+`)
+
+	t.Setenv("XRV_API_RETRY_ATTEMPTS", "7")
+	t.Setenv("XRV_CLI_DEFAULT_TARGETS", "CHF,JPY")
+
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.String("cache-dir", "", "")
+	if err := flags.Parse([]string{"--cache-dir", "/from/flag"}); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	v := viper.New()
+	v.BindPFlag("cache.directory", flags.Lookup("cache-dir"))
+
+	cfg, err := Load(v, path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if cfg.API.RetryAttempts != 7 {
+		t.Errorf("API.RetryAttempts = %d, want 7 from environment", cfg.API.RetryAttempts)
+	}
+	if cfg.CLI.DefaultBase != "EUR" {
+		t.Errorf("CLI.DefaultBase = %s, want EUR from file", cfg.CLI.DefaultBase)
+	}
+	if len(cfg.CLI.DefaultTargets) != 2 || cfg.CLI.DefaultTargets[0] != "CHF" {
+		t.Errorf("CLI.DefaultTargets = %v, want [CHF JPY] from environment", cfg.CLI.DefaultTargets)
+	}
+	if cfg.Cache.Directory != "/from/flag" {
+		t.Errorf("Cache.Directory = %s, want /from/flag", cfg.Cache.Directory)
+	}
+}
+
+func TestExpandPath(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory")
+	}
+
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"~/.xrv/cache", filepath.Join(home, ".xrv/cache")},
+		{"/var/cache/xrv", "/var/cache/xrv"},
+		{"relative/dir", "relative/dir"},
+	}
+
+	for _, tt := range tests {
+		got, err := ExpandPath(tt.in)
+		if err != nil {
+			t.Fatalf("ExpandPath(%s) error = %v", tt.in, err)
+		}
+		if got != tt.want {
+			t.Errorf("ExpandPath(%s) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
@@ -11,9 +11,36 @@ type ExchangeRate struct {
 	Rate   float64
 }
 
+type OHLC struct {
+	Open  float64 `json:"open"`
+	High  float64 `json:"high"`
+	Low   float64 `json:"low"`
+	Close float64 `json:"close"`
+}
+
+// Invert returns the bar quoted the other way round; high and low swap
+// places because 1/x reverses the order.
+func (o OHLC) Invert() OHLC {
+	inverted := OHLC{}
+	if o.Open != 0 {
+		inverted.Open = 1 / o.Open
+	}
+	if o.Low != 0 {
+		inverted.High = 1 / o.Low
+	}
+	if o.High != 0 {
+		inverted.Low = 1 / o.High
+	}
+	if o.Close != 0 {
+		inverted.Close = 1 / o.Close
+	}
+	return inverted
+}
+
 type DataPoint struct {
 	Date  time.Time
 	Rates map[Currency]float64
+	OHLC  map[Currency]OHLC `json:",omitempty"`
 }
 
 type TimeSeriesData struct {
@@ -22,4 +49,7 @@ type TimeSeriesData struct {
 	StartDate  time.Time
 	EndDate    time.Time
 	DataPoints []DataPoint
+	Sources    map[Currency][]string
+	DerivedVia map[Currency]Currency
+	Interval   string
 }
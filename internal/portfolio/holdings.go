@@ -0,0 +1,190 @@
+package portfolio
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+
+	"github.com/kaze/xrv/internal/domain"
+)
+
+// Holding is a balance in one currency. Acquired and Rate are optional; Rate
+// is the price of one unit of the currency in the reporting currency when it
+// was acquired, the same direction as the rate column of convert-file.
+type Holding struct {
+	Currency domain.Currency
+	Amount   float64
+	Acquired time.Time
+	Rate     float64
+}
+
+// Portfolio is a holdings file. Reporting is empty unless the file names it.
+type Portfolio struct {
+	Reporting domain.Currency
+	Holdings  []Holding
+}
+
+// Load reads holdings from a .yaml, .yml or .csv file.
+func Load(path string) (*Portfolio, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open holdings: %w", err)
+	}
+	defer f.Close()
+
+	var p *Portfolio
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		p, err = ParseYAML(f)
+	case ".csv":
+		p, err = ParseCSV(f)
+	default:
+		return nil, fmt.Errorf("unsupported holdings file %s (use .yaml, .yml or .csv)", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return p, nil
+}
+
+// ParseYAML reads
+//
+//	reporting: EUR
+//	holdings:
+//	  - currency: USD
+//	    amount: 250000
+//	    acquired: 2024-03-01
+//	    rate: 0.92
+func ParseYAML(r io.Reader) (*Portfolio, error) {
+	var file struct {
+		Reporting string `yaml:"reporting"`
+		Holdings  []struct {
+			Currency string  `yaml:"currency"`
+			Amount   float64 `yaml:"amount"`
+			Acquired string  `yaml:"acquired"`
+			Rate     float64 `yaml:"rate"`
+		} `yaml:"holdings"`
+	}
+
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	p := &Portfolio{Reporting: domain.Currency(strings.ToUpper(strings.TrimSpace(file.Reporting)))}
+	for i, h := range file.Holdings {
+		holding, err := newHolding(h.Currency, h.Amount, h.Acquired, h.Rate)
+		if err != nil {
+			return nil, fmt.Errorf("holding %d: %w", i+1, err)
+		}
+		p.Holdings = append(p.Holdings, holding)
+	}
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// ParseCSV reads a header with currency and amount columns and optional
+// acquired and rate columns.
+func ParseCSV(r io.Reader) (*Portfolio, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, required := range []string{"currency", "amount"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("missing %q column", required)
+		}
+	}
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	p := &Portfolio{}
+	line := 1
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		amount, err := parseNumber(field(record, "amount"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid amount %q", line, field(record, "amount"))
+		}
+		var rate float64
+		if value := field(record, "rate"); value != "" {
+			if rate, err = parseNumber(value); err != nil {
+				return nil, fmt.Errorf("line %d: invalid rate %q", line, value)
+			}
+		}
+
+		holding, err := newHolding(field(record, "currency"), amount, field(record, "acquired"), rate)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		p.Holdings = append(p.Holdings, holding)
+	}
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func newHolding(currency string, amount float64, acquired string, rate float64) (Holding, error) {
+	holding := Holding{
+		Currency: domain.Currency(strings.ToUpper(strings.TrimSpace(currency))),
+		Amount:   amount,
+		Rate:     rate,
+	}
+	if holding.Currency == "" {
+		return Holding{}, fmt.Errorf("missing currency")
+	}
+	if rate < 0 {
+		return Holding{}, fmt.Errorf("negative acquisition rate %g", rate)
+	}
+	if acquired != "" {
+		date, err := time.Parse("2006-01-02", acquired)
+		if err != nil {
+			return Holding{}, fmt.Errorf("invalid acquisition date %q", acquired)
+		}
+		holding.Acquired = date
+	}
+	return holding, nil
+}
+
+func (p *Portfolio) validate() error {
+	if len(p.Holdings) == 0 {
+		return fmt.Errorf("no holdings")
+	}
+	return nil
+}
+
+func parseNumber(value string) (float64, error) {
+	cleaned := strings.ReplaceAll(strings.ReplaceAll(value, ",", ""), "_", "")
+	return strconv.ParseFloat(cleaned, 64)
+}
@@ -0,0 +1,105 @@
+package portfolio
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseYAML(t *testing.T) {
+	input := `reporting: eur
+holdings:
+  - currency: usd
+    amount: 250000
+    acquired: 2024-03-01
+    rate: 0.92
+  - currency: GBP
+    amount: 1000
+`
+	p, err := ParseYAML(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseYAML() error = %v", err)
+	}
+
+	if p.Reporting != "EUR" || len(p.Holdings) != 2 {
+		t.Fatalf("portfolio = %+v, want EUR with 2 holdings", p)
+	}
+	first := p.Holdings[0]
+	if first.Currency != "USD" || first.Amount != 250000 || first.Rate != 0.92 || first.Acquired.Format("2006-01-02") != "2024-03-01" {
+		t.Errorf("first holding = %+v", first)
+	}
+	if second := p.Holdings[1]; !second.Acquired.IsZero() || second.Rate != 0 {
+		t.Errorf("second holding = %+v, want no acquisition details", second)
+	}
+
+	if _, err := ParseYAML(strings.NewReader("holdings:\n  - currency: USD\n    amont: 1\n")); err == nil {
+		t.Error("ParseYAML() should reject unknown fields")
+	}
+	if _, err := ParseYAML(strings.NewReader("reporting: EUR\n")); err == nil {
+		t.Error("ParseYAML() should reject a file without holdings")
+	}
+}
+
+func TestParseCSV(t *testing.T) {
+	input := "Currency,Amount,Acquired,Rate\n" +
+		"usd,\"250,000\",2024-03-01,0.92\n" +
+		"GBP,1000,,\n"
+
+	p, err := ParseCSV(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseCSV() error = %v", err)
+	}
+
+	if p.Reporting != "" || len(p.Holdings) != 2 {
+		t.Fatalf("portfolio = %+v, want 2 holdings and no reporting currency", p)
+	}
+	if first := p.Holdings[0]; first.Currency != "USD" || first.Amount != 250000 || first.Rate != 0.92 {
+		t.Errorf("first holding = %+v", first)
+	}
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"missing amount column", "currency\nUSD\n"},
+		{"invalid amount", "currency,amount\nUSD,lots\n"},
+		{"invalid date", "currency,amount,acquired\nUSD,1,01/03/2024\n"},
+		{"negative rate", "currency,amount,rate\nUSD,1,-0.9\n"},
+		{"missing currency", "currency,amount\n,1\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseCSV(strings.NewReader(tt.input)); err == nil {
+				t.Errorf("ParseCSV(%q) should fail", tt.input)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "holdings.yml")
+	if err := os.WriteFile(path, []byte("holdings:\n  - currency: USD\n    amount: 10\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(p.Holdings) != 1 || p.Holdings[0].Currency != "USD" {
+		t.Errorf("holdings = %+v", p.Holdings)
+	}
+
+	if _, err := Load(filepath.Join(dir, "holdings.txt")); err == nil {
+		t.Error("Load() should fail for a missing file")
+	}
+	txt := filepath.Join(dir, "holdings.json")
+	if err := os.WriteFile(txt, []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(txt); err == nil || !strings.Contains(err.Error(), "unsupported") {
+		t.Errorf("Load() error = %v, want an unsupported file error", err)
+	}
+}
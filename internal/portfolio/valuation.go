@@ -0,0 +1,317 @@
+package portfolio
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/kaze/xrv/internal/domain"
+	"github.com/kaze/xrv/internal/service"
+	"github.com/kaze/xrv/internal/statistics"
+)
+
+// Source adds the conversion of holdings at their acquisition dates to the
+// rates a valuation fetches; *service.Service satisfies it.
+type Source interface {
+	service.Fetcher
+	ConvertTransactions(ctx context.Context, transactions []service.Transaction, opts service.BatchConvertOptions) ([]service.ConvertedTransaction, error)
+}
+
+// ValuationOptions selects the reporting currency and period. An empty
+// Reporting falls back to the one named in the holdings file.
+type ValuationOptions struct {
+	Reporting domain.Currency
+	StartDate time.Time
+	EndDate   time.Time
+	UseCache  bool
+	Rebase    bool
+}
+
+// CostSource tells where a position's cost basis comes from.
+type CostSource string
+
+const (
+	CostFromRate     CostSource = "rate"
+	CostFromAcquired CostSource = "acquired"
+	CostFromStart    CostSource = "start"
+)
+
+// Position is one holding valued in the reporting currency. Values is aligned
+// with Valuation.Dates and NaN before the first fixing; Value and Rate are
+// the latest, Rate being reporting-currency units per unit held. A holding
+// without any fixing in the period is Unpriced: it has no value or gain and
+// is left out of the totals.
+type Position struct {
+	Holding         Holding
+	Values          statistics.Series
+	Value           float64
+	Rate            float64
+	Share           float64
+	Change          float64
+	CostBasis       float64
+	CostSource      CostSource
+	GainLoss        float64
+	GainLossPercent float64
+	Unpriced        bool
+}
+
+// Contribution sums the positions held in one currency.
+type Contribution struct {
+	Currency domain.Currency
+	Amount   float64
+	Values   statistics.Series
+	Value    float64
+	Share    float64
+	Change   float64
+	GainLoss float64
+}
+
+// Valuation is the value of a portfolio over time. Total, like the
+// contribution values, leaves out positions without a fixing yet. Change is
+// the move of the total from the first date on which every priced position
+// has a fixing, and the contribution changes add up to it; GainLoss is
+// measured against each position's cost basis, which may predate the period.
+type Valuation struct {
+	Reporting     domain.Currency
+	StartDate     time.Time
+	EndDate       time.Time
+	Dates         []time.Time
+	Positions     []Position
+	ByCurrency    []Contribution
+	Total         statistics.Series
+	TotalValue    float64
+	TotalCost     float64
+	TotalGainLoss float64
+	Change        float64
+}
+
+// Value fetches the reporting-currency rates of every foreign holding over
+// the period, forward-filling missing fixings, and values each holding on
+// every business day. The cost basis of a holding is its acquisition rate
+// when given, else the fixing on or before its acquisition date, else its
+// value at the start of the period.
+func Value(ctx context.Context, src Source, p *Portfolio, opts ValuationOptions) (*Valuation, error) {
+	reporting := opts.Reporting
+	if reporting == "" {
+		reporting = p.Reporting
+	}
+	if reporting == "" {
+		return nil, fmt.Errorf("reporting currency is required")
+	}
+
+	var targets []domain.Currency
+	seen := map[domain.Currency]bool{reporting: true}
+	for _, h := range p.Holdings {
+		if !seen[h.Currency] {
+			seen[h.Currency] = true
+			targets = append(targets, h.Currency)
+		}
+	}
+
+	v := &Valuation{Reporting: reporting, StartDate: opts.StartDate, EndDate: opts.EndDate}
+
+	var data *domain.TimeSeriesData
+	if len(targets) > 0 {
+		var err error
+		data, err = src.FetchTimeSeriesData(ctx, service.FetchOptions{
+			Base:      reporting,
+			Targets:   targets,
+			StartDate: opts.StartDate,
+			EndDate:   opts.EndDate,
+			UseCache:  opts.UseCache,
+			Rebase:    opts.Rebase,
+			Gaps:      service.GapForwardFill,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch %s rates: %w", reporting, err)
+		}
+		for _, dp := range data.DataPoints {
+			v.Dates = append(v.Dates, dp.Date)
+		}
+	}
+
+	v.Positions = make([]Position, len(p.Holdings))
+	for i, h := range p.Holdings {
+		v.Positions[i] = Position{Holding: h, Values: holdingValues(h, reporting, data)}
+	}
+
+	if err := v.costBasis(ctx, src, opts.UseCache); err != nil {
+		return nil, err
+	}
+	v.summarize()
+	return v, nil
+}
+
+// holdingValues converts the holding with each rate, which counts units of
+// the holding per unit of the reporting currency.
+func holdingValues(h Holding, reporting domain.Currency, data *domain.TimeSeriesData) statistics.Series {
+	if data == nil {
+		return statistics.Series{}
+	}
+
+	values := make(statistics.Series, len(data.DataPoints))
+	last := math.NaN()
+	for i, dp := range data.DataPoints {
+		switch rate, exists := dp.Rates[h.Currency]; {
+		case h.Currency == reporting:
+			last = h.Amount
+		case exists && rate > 0:
+			last = h.Amount / rate
+		}
+		values[i] = last
+	}
+	return values
+}
+
+func (v *Valuation) costBasis(ctx context.Context, src Source, useCache bool) error {
+	var transactions []service.Transaction
+	var acquired []int
+	for i := range v.Positions {
+		position := &v.Positions[i]
+		h := position.Holding
+		switch {
+		case h.Currency == v.Reporting:
+			position.CostBasis, position.CostSource = h.Amount, CostFromRate
+		case h.Rate > 0:
+			position.CostBasis, position.CostSource = h.Amount*h.Rate, CostFromRate
+		case !h.Acquired.IsZero():
+			transactions = append(transactions, service.Transaction{Date: h.Acquired, Amount: h.Amount, Currency: h.Currency})
+			acquired = append(acquired, i)
+		default:
+			position.CostBasis, position.CostSource = firstValue(position.Values), CostFromStart
+		}
+	}
+	if len(transactions) == 0 {
+		return nil
+	}
+
+	converted, err := src.ConvertTransactions(ctx, transactions, service.BatchConvertOptions{
+		To:       v.Reporting,
+		Rule:     service.RatePrevious,
+		UseCache: useCache,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to value holdings at acquisition: %w", err)
+	}
+	for j, i := range acquired {
+		if converted[j].Err != nil {
+			return fmt.Errorf("%s holding acquired %s: %w", transactions[j].Currency, transactions[j].Date.Format("2006-01-02"), converted[j].Err)
+		}
+		v.Positions[i].CostBasis, v.Positions[i].CostSource = converted[j].Converted, CostFromAcquired
+	}
+	return nil
+}
+
+// summarize fills the latest figures, the totals and the per-currency
+// contributions from the position values and cost bases.
+func (v *Valuation) summarize() {
+	v.Total = undefinedSeries(len(v.Dates))
+	index := make(map[domain.Currency]int)
+	start := 0
+
+	for i := range v.Positions {
+		position := &v.Positions[i]
+		h := position.Holding
+
+		if h.Currency == v.Reporting {
+			position.Value, position.Rate = h.Amount, 1
+		} else if value := position.Values.Last(); !math.IsNaN(value) {
+			position.Value = value
+			if h.Amount != 0 {
+				position.Rate = value / h.Amount
+			}
+		} else {
+			position.Unpriced = true
+		}
+
+		if !position.Unpriced {
+			if len(position.Values) > 0 {
+				position.Change = position.Value - firstValue(position.Values)
+				start = max(start, firstDefined(position.Values))
+			}
+			position.GainLoss = position.Value - position.CostBasis
+			if position.CostBasis != 0 {
+				position.GainLossPercent = position.GainLoss / math.Abs(position.CostBasis) * 100
+			}
+			v.TotalCost += position.CostBasis
+		}
+
+		addDefined(v.Total, position.Values)
+		v.TotalValue += position.Value
+
+		k, exists := index[h.Currency]
+		if !exists {
+			k = len(v.ByCurrency)
+			index[h.Currency] = k
+			v.ByCurrency = append(v.ByCurrency, Contribution{Currency: h.Currency, Values: undefinedSeries(len(v.Dates))})
+		}
+		contribution := &v.ByCurrency[k]
+		contribution.Amount += h.Amount
+		contribution.Value += position.Value
+		contribution.GainLoss += position.GainLoss
+		addDefined(contribution.Values, position.Values)
+	}
+
+	v.TotalGainLoss = v.TotalValue - v.TotalCost
+	if start < len(v.Total) && !math.IsNaN(v.Total[start]) {
+		v.Change = v.TotalValue - v.Total[start]
+		for k := range v.ByCurrency {
+			if first := v.ByCurrency[k].Values[start]; !math.IsNaN(first) {
+				v.ByCurrency[k].Change = v.ByCurrency[k].Value - first
+			}
+		}
+	}
+	if v.TotalValue != 0 {
+		for i := range v.Positions {
+			v.Positions[i].Share = v.Positions[i].Value / v.TotalValue * 100
+		}
+		for k := range v.ByCurrency {
+			v.ByCurrency[k].Share = v.ByCurrency[k].Value / v.TotalValue * 100
+		}
+	}
+}
+
+// undefinedSeries returns a series of n NaN values.
+func undefinedSeries(n int) statistics.Series {
+	series := make(statistics.Series, n)
+	for i := range series {
+		series[i] = math.NaN()
+	}
+	return series
+}
+
+// addDefined adds values into sum, leaving out the NaN values of positions
+// that have no fixing yet. A date stays NaN until some position is defined.
+func addDefined(sum, values statistics.Series) {
+	for j, value := range values {
+		switch {
+		case math.IsNaN(value):
+		case math.IsNaN(sum[j]):
+			sum[j] = value
+		default:
+			sum[j] += value
+		}
+	}
+}
+
+// firstDefined returns the index of the first defined value, or len(values)
+// if there is none.
+func firstDefined(values statistics.Series) int {
+	for i, value := range values {
+		if !math.IsNaN(value) {
+			return i
+		}
+	}
+	return len(values)
+}
+
+// firstValue returns the first defined value, or zero if there is none.
+func firstValue(values statistics.Series) float64 {
+	for _, value := range values {
+		if !math.IsNaN(value) {
+			return value
+		}
+	}
+	return 0
+}
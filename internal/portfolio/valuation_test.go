@@ -0,0 +1,188 @@
+package portfolio
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/kaze/xrv/internal/domain"
+	"github.com/kaze/xrv/internal/service"
+)
+
+type fakeSource struct {
+	data      *domain.TimeSeriesData
+	fetched   service.FetchOptions
+	converted []service.Transaction
+	rate      float64
+}
+
+func (f *fakeSource) FetchTimeSeriesData(ctx context.Context, opts service.FetchOptions) (*domain.TimeSeriesData, error) {
+	f.fetched = opts
+	return f.data, nil
+}
+
+func (f *fakeSource) ConvertTransactions(ctx context.Context, transactions []service.Transaction, opts service.BatchConvertOptions) ([]service.ConvertedTransaction, error) {
+	f.converted = transactions
+	result := make([]service.ConvertedTransaction, len(transactions))
+	for i, tx := range transactions {
+		result[i] = service.ConvertedTransaction{Transaction: tx, Rate: f.rate, Converted: tx.Amount * f.rate}
+	}
+	return result, nil
+}
+
+func day(value string) time.Time {
+	t, _ := time.Parse("2006-01-02", value)
+	return t
+}
+
+func testSource() *fakeSource {
+	return &fakeSource{
+		data: &domain.TimeSeriesData{
+			Base:    "EUR",
+			Targets: []domain.Currency{"USD", "GBP"},
+			DataPoints: []domain.DataPoint{
+				{Date: day("2024-01-01"), Rates: map[domain.Currency]float64{"USD": 1.25}},
+				{Date: day("2024-01-02"), Rates: map[domain.Currency]float64{"USD": 1.00, "GBP": 0.80}},
+				{Date: day("2024-01-03"), Rates: map[domain.Currency]float64{"USD": 1.00, "GBP": 0.50}},
+			},
+		},
+		rate: 0.5,
+	}
+}
+
+func TestValue(t *testing.T) {
+	src := testSource()
+	p := &Portfolio{
+		Reporting: "EUR",
+		Holdings: []Holding{
+			{Currency: "USD", Amount: 100, Rate: 0.9},
+			{Currency: "GBP", Amount: 40, Acquired: day("2023-06-01")},
+			{Currency: "EUR", Amount: 10},
+			{Currency: "USD", Amount: 50},
+		},
+	}
+
+	v, err := Value(context.Background(), src, p, ValuationOptions{})
+	if err != nil {
+		t.Fatalf("Value() error = %v", err)
+	}
+
+	if src.fetched.Base != "EUR" || len(src.fetched.Targets) != 2 || src.fetched.Gaps != service.GapForwardFill {
+		t.Errorf("fetched %+v, want EUR rates of USD and GBP with forward fill", src.fetched)
+	}
+	if len(src.converted) != 1 || src.converted[0].Currency != "GBP" {
+		t.Errorf("converted %+v, want only the GBP holding", src.converted)
+	}
+
+	usd := v.Positions[0]
+	if usd.Values[0] != 80 || usd.Value != 100 || usd.Rate != 1 || usd.Change != 20 {
+		t.Errorf("USD position = %+v", usd)
+	}
+	if usd.CostSource != CostFromRate || usd.CostBasis != 90 || usd.GainLoss != 10 {
+		t.Errorf("USD cost = %v from %s, gain %v; want 90 from rate, gain 10", usd.CostBasis, usd.CostSource, usd.GainLoss)
+	}
+
+	gbp := v.Positions[1]
+	if !math.IsNaN(gbp.Values[0]) || gbp.Value != 80 || gbp.Change != 30 {
+		t.Errorf("GBP position = %+v, want NaN before the first fixing and 80 at the end", gbp)
+	}
+	if gbp.CostSource != CostFromAcquired || gbp.CostBasis != 20 || gbp.GainLossPercent != 300 {
+		t.Errorf("GBP cost = %v from %s, gain %v%%", gbp.CostBasis, gbp.CostSource, gbp.GainLossPercent)
+	}
+
+	if eur := v.Positions[2]; eur.Value != 10 || eur.Values[0] != 10 || eur.GainLoss != 0 {
+		t.Errorf("EUR position = %+v, want a constant 10", eur)
+	}
+	if lot := v.Positions[3]; lot.CostSource != CostFromStart || lot.CostBasis != 40 {
+		t.Errorf("second USD lot cost = %v from %s, want 40 from start", lot.CostBasis, lot.CostSource)
+	}
+
+	if v.TotalValue != 240 || v.TotalCost != 160 || v.TotalGainLoss != 80 {
+		t.Errorf("totals = %v value, %v cost, %v gain", v.TotalValue, v.TotalCost, v.TotalGainLoss)
+	}
+	if v.Total[0] != 130 || v.Total[1] != 210 || v.Change != 30 {
+		t.Errorf("Total = %v, Change = %v", v.Total, v.Change)
+	}
+
+	if len(v.ByCurrency) != 3 {
+		t.Fatalf("ByCurrency = %+v, want USD, GBP and EUR", v.ByCurrency)
+	}
+	if usd := v.ByCurrency[0]; usd.Currency != "USD" || usd.Amount != 150 || usd.Value != 150 || usd.Share != 62.5 || usd.Values[0] != 120 {
+		t.Errorf("USD contribution = %+v", usd)
+	}
+
+	if _, err := json.Marshal(v); err != nil {
+		t.Errorf("json.Marshal(valuation) error = %v", err)
+	}
+}
+
+func TestValue_Reporting(t *testing.T) {
+	p := &Portfolio{Holdings: []Holding{{Currency: "EUR", Amount: 5}}}
+
+	if _, err := Value(context.Background(), testSource(), p, ValuationOptions{}); err == nil {
+		t.Error("Value() should fail without a reporting currency")
+	}
+
+	src := testSource()
+	v, err := Value(context.Background(), src, p, ValuationOptions{Reporting: "EUR"})
+	if err != nil {
+		t.Fatalf("Value() error = %v", err)
+	}
+	if src.fetched.Base != "" || v.TotalValue != 5 || len(v.Dates) != 0 {
+		t.Errorf("valuation = %+v, want 5 EUR without fetching rates", v)
+	}
+}
+
+func TestValue_FirstFixingsDiffer(t *testing.T) {
+	src := &fakeSource{data: &domain.TimeSeriesData{
+		Base:    "EUR",
+		Targets: []domain.Currency{"USD", "GBP", "JPY"},
+		DataPoints: []domain.DataPoint{
+			{Date: day("2024-01-01"), Rates: map[domain.Currency]float64{}},
+			{Date: day("2024-01-02"), Rates: map[domain.Currency]float64{"USD": 1.00}},
+			{Date: day("2024-01-03"), Rates: map[domain.Currency]float64{"USD": 1.00, "GBP": 0.50}},
+			{Date: day("2024-01-04"), Rates: map[domain.Currency]float64{"USD": 0.80, "GBP": 0.40}},
+		},
+	}}
+	p := &Portfolio{
+		Reporting: "EUR",
+		Holdings: []Holding{
+			{Currency: "USD", Amount: 100},
+			{Currency: "GBP", Amount: 40},
+			{Currency: "JPY", Amount: 1000, Rate: 0.006},
+		},
+	}
+
+	v, err := Value(context.Background(), src, p, ValuationOptions{})
+	if err != nil {
+		t.Fatalf("Value() error = %v", err)
+	}
+
+	if !math.IsNaN(v.Total[0]) || v.Total[1] != 100 || v.Total[2] != 180 || v.Total[3] != 225 {
+		t.Errorf("Total = %v, want NaN before any fixing, then 100, 180 and 225", v.Total)
+	}
+	if gbp := v.ByCurrency[1]; !math.IsNaN(gbp.Values[1]) || gbp.Values[2] != 80 {
+		t.Errorf("GBP contribution values = %v, want NaN before its first fixing", gbp.Values)
+	}
+
+	if v.TotalValue != 225 || v.Change != 45 {
+		t.Errorf("TotalValue = %v, Change = %v; want 225 and 45 since both positions are fixed", v.TotalValue, v.Change)
+	}
+	var sum float64
+	for _, c := range v.ByCurrency {
+		sum += c.Change
+	}
+	if sum != v.Change {
+		t.Errorf("contribution changes add up to %v, want %v", sum, v.Change)
+	}
+
+	jpy := v.Positions[2]
+	if !jpy.Unpriced || jpy.Value != 0 || jpy.GainLoss != 0 {
+		t.Errorf("JPY position = %+v, want unpriced without a gain or loss", jpy)
+	}
+	if v.TotalCost != 180 || v.TotalGainLoss != 45 {
+		t.Errorf("TotalCost = %v, TotalGainLoss = %v; want 180 and 45 without the unpriced JPY", v.TotalCost, v.TotalGainLoss)
+	}
+}
@@ -0,0 +1,365 @@
+package providers
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const DefaultECBSource = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-hist.zip"
+
+var ecbCurrencyNames = CurrenciesResponse{
+	"AUD": "Australian Dollar",
+	"BGN": "Bulgarian Lev",
+	"BRL": "Brazilian Real",
+	"CAD": "Canadian Dollar",
+	"CHF": "Swiss Franc",
+	"CNY": "Chinese Renminbi Yuan",
+	"CYP": "Cypriot Pound",
+	"CZK": "Czech Koruna",
+	"DKK": "Danish Krone",
+	"EEK": "Estonian Kroon",
+	"EUR": "Euro",
+	"GBP": "British Pound",
+	"HKD": "Hong Kong Dollar",
+	"HRK": "Croatian Kuna",
+	"HUF": "Hungarian Forint",
+	"IDR": "Indonesian Rupiah",
+	"ILS": "Israeli New Sheqel",
+	"INR": "Indian Rupee",
+	"ISK": "Icelandic Króna",
+	"JPY": "Japanese Yen",
+	"KRW": "South Korean Won",
+	"LTL": "Lithuanian Litas",
+	"LVL": "Latvian Lats",
+	"MTL": "Maltese Lira",
+	"MXN": "Mexican Peso",
+	"MYR": "Malaysian Ringgit",
+	"NOK": "Norwegian Krone",
+	"NZD": "New Zealand Dollar",
+	"PHP": "Philippine Peso",
+	"PLN": "Polish Złoty",
+	"ROL": "Romanian Leu (1952–2005)",
+	"RON": "Romanian Leu",
+	"RUB": "Russian Ruble",
+	"SEK": "Swedish Krona",
+	"SGD": "Singapore Dollar",
+	"SIT": "Slovenian Tolar",
+	"SKK": "Slovak Koruna",
+	"THB": "Thai Baht",
+	"TRL": "Turkish Lira (1922–2005)",
+	"TRY": "Turkish Lira",
+	"USD": "United States Dollar",
+	"ZAR": "South African Rand",
+}
+
+// ECBCurrencies lists the currencies of the ECB reference rates, including
+// those no longer quoted, in alphabetical order.
+func ECBCurrencies() []string {
+	currencies := make([]string, 0, len(ecbCurrencyNames))
+	for code := range ecbCurrencyNames {
+		currencies = append(currencies, code)
+	}
+	sort.Strings(currencies)
+	return currencies
+}
+
+type ECBClient struct {
+	source     string
+	httpClient *http.Client
+
+	mu     sync.Mutex
+	rates  map[string]map[string]float64
+	latest string
+	// version identifies the loaded feed: the Last-Modified header of a
+	// download, or the modification time and size of a file.
+	version string
+}
+
+func NewECBClient(source string, timeout time.Duration) *ECBClient {
+	if source == "" {
+		source = DefaultECBSource
+	}
+	return &ECBClient{
+		source: source,
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+	}
+}
+
+func (c *ECBClient) GetTimeSeriesRates(ctx context.Context, startDate, endDate time.Time, base string, targets []string) (*TimeSeriesResponse, error) {
+	rates, err := c.load(ctx, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	base = strings.ToUpper(base)
+	if base != "EUR" {
+		rates = rebaseRates(rates, "EUR", base)
+		if len(rates) == 0 {
+			return nil, &APIError{
+				StatusCode: http.StatusNotFound,
+				Message:    fmt.Sprintf("the ECB reference rates do not quote %s", base),
+				URL:        c.source,
+			}
+		}
+	}
+
+	return filterRates(rates, startDate, endDate, base, targets), nil
+}
+
+func (c *ECBClient) GetSupportedCurrencies(ctx context.Context) (CurrenciesResponse, error) {
+	rates, err := c.load(ctx, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	currencies := CurrenciesResponse{"EUR": ecbCurrencyNames["EUR"]}
+	for _, dayRates := range rates {
+		for code := range dayRates {
+			if _, exists := currencies[code]; exists {
+				continue
+			}
+			if name, ok := ecbCurrencyNames[code]; ok {
+				currencies[code] = name
+			} else {
+				currencies[code] = code
+			}
+		}
+	}
+
+	return currencies, nil
+}
+
+// load returns the parsed feed. The ECB adds a fixing every business day, so
+// the feed is read again when a request reaches past its latest fixing; the
+// read is conditional and returns nothing while the feed is unchanged.
+func (c *ECBClient) load(ctx context.Context, until time.Time) (map[string]map[string]float64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.rates != nil && (until.IsZero() || until.Format("2006-01-02") <= c.latest) {
+		return c.rates, nil
+	}
+
+	content, version, err := c.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if content == nil {
+		return c.rates, nil
+	}
+
+	rates, err := parseECBFeed(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ECB feed %s: %w", c.source, err)
+	}
+	if len(rates) == 0 {
+		return nil, fmt.Errorf("ECB feed %s contains no rates", c.source)
+	}
+
+	c.rates, c.version = rates, version
+	c.latest = ""
+	for date := range rates {
+		if date > c.latest {
+			c.latest = date
+		}
+	}
+	return rates, nil
+}
+
+// read returns the feed and its version, or no content when the version
+// matches the loaded one.
+func (c *ECBClient) read(ctx context.Context) ([]byte, string, error) {
+	if !strings.HasPrefix(c.source, "http://") && !strings.HasPrefix(c.source, "https://") {
+		info, err := os.Stat(c.source)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read ECB feed: %w", err)
+		}
+		version := fmt.Sprintf("%s %d", info.ModTime().UTC().Format(time.RFC3339Nano), info.Size())
+		if c.rates != nil && version == c.version {
+			return nil, version, nil
+		}
+
+		content, err := os.ReadFile(c.source)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read ECB feed: %w", err)
+		}
+		return content, version, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.source, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	if c.rates != nil && c.version != "" {
+		req.Header.Set("If-Modified-Since", c.version)
+	}
+
+	httpResp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode == http.StatusNotModified {
+		return nil, c.version, nil
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(httpResp.Body)
+		return nil, "", &APIError{
+			StatusCode: httpResp.StatusCode,
+			Message:    fmt.Sprintf("ECB download failed: %s", string(body)),
+			URL:        c.source,
+		}
+	}
+
+	content, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, "", err
+	}
+	return content, httpResp.Header.Get("Last-Modified"), nil
+}
+
+func parseECBFeed(content []byte) (map[string]map[string]float64, error) {
+	if bytes.HasPrefix(content, []byte("PK")) {
+		return parseECBZip(content)
+	}
+
+	trimmed := bytes.TrimSpace(bytes.TrimPrefix(content, []byte("\xef\xbb\xbf")))
+	if bytes.HasPrefix(trimmed, []byte("<")) {
+		return parseECBXML(trimmed)
+	}
+
+	return parseECBCSV(trimmed)
+}
+
+func parseECBZip(content []byte) (map[string]map[string]float64, error) {
+	archive, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid zip archive: %w", err)
+	}
+
+	for _, file := range archive.File {
+		ext := strings.ToLower(path.Ext(file.Name))
+		if ext != ".csv" && ext != ".xml" {
+			continue
+		}
+
+		f, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract %s: %w", file.Name, err)
+		}
+
+		return parseECBFeed(data)
+	}
+
+	return nil, fmt.Errorf("zip archive contains no CSV or XML file")
+}
+
+type ecbEnvelope struct {
+	Days []struct {
+		Time  string `xml:"time,attr"`
+		Rates []struct {
+			Currency string `xml:"currency,attr"`
+			Rate     string `xml:"rate,attr"`
+		} `xml:"Cube"`
+	} `xml:"Cube>Cube"`
+}
+
+func parseECBXML(content []byte) (map[string]map[string]float64, error) {
+	var envelope ecbEnvelope
+	if err := xml.Unmarshal(content, &envelope); err != nil {
+		return nil, fmt.Errorf("invalid XML: %w", err)
+	}
+
+	rates := make(map[string]map[string]float64, len(envelope.Days))
+	for _, d := range envelope.Days {
+		if _, err := time.Parse("2006-01-02", d.Time); err != nil {
+			return nil, fmt.Errorf("invalid date %q", d.Time)
+		}
+
+		dayRates := make(map[string]float64, len(d.Rates))
+		for _, r := range d.Rates {
+			rate, err := strconv.ParseFloat(strings.TrimSpace(r.Rate), 64)
+			if err != nil || rate == 0 {
+				continue
+			}
+			dayRates[strings.ToUpper(r.Currency)] = rate
+		}
+		if len(dayRates) > 0 {
+			rates[d.Time] = dayRates
+		}
+	}
+
+	return rates, nil
+}
+
+func parseECBCSV(content []byte) (map[string]map[string]float64, error) {
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+	if len(header) < 2 || !strings.EqualFold(strings.TrimSpace(header[0]), "date") {
+		return nil, fmt.Errorf("first column must be 'Date'")
+	}
+
+	rates := make(map[string]map[string]float64)
+	line := 1
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		date := strings.TrimSpace(record[0])
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return nil, fmt.Errorf("line %d: invalid date %q", line, date)
+		}
+
+		dayRates := make(map[string]float64, len(header)-1)
+		for i := 1; i < len(record) && i < len(header); i++ {
+			currency := strings.ToUpper(strings.TrimSpace(header[i]))
+			value := strings.TrimSpace(record[i])
+			if currency == "" || value == "" || value == "N/A" {
+				continue
+			}
+			rate, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid rate %q", line, value)
+			}
+			dayRates[currency] = rate
+		}
+		if len(dayRates) > 0 {
+			rates[date] = dayRates
+		}
+	}
+
+	return rates, nil
+}
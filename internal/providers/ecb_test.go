@@ -0,0 +1,222 @@
+package providers
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const ecbXML = `<?xml version="1.0" encoding="UTF-8"?>
+<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref">
+	<gesmes:subject>Reference rates</gesmes:subject>
+	<Cube>
+		<Cube time="2024-01-03">
+			<Cube currency="USD" rate="1.0919"/>
+			<Cube currency="JPY" rate="155.86"/>
+			<Cube currency="GBP" rate="0.86265"/>
+		</Cube>
+		<Cube time="2024-01-02">
+			<Cube currency="USD" rate="1.0956"/>
+			<Cube currency="JPY" rate="155.29"/>
+			<Cube currency="GBP" rate="0.86675"/>
+		</Cube>
+	</Cube>
+</gesmes:Envelope>`
+
+const ecbCSV = `Date,USD,JPY,GBP,CYP,
+2024-01-03,1.0919,155.86,0.86265,N/A,
+2024-01-02,1.0956,155.29,0.86675,N/A,
+`
+
+func zipFeed(t *testing.T, name, content string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	f, err := w.Create(name)
+	if err != nil {
+		t.Fatalf("failed to create zip entry: %v", err)
+	}
+	f.Write([]byte(content))
+	if err := w.Close(); err != nil {
+		t.Fatalf("failed to close zip: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestECBClient_Formats(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content []byte
+	}{
+		{"xml", "eurofxref-hist.xml", []byte(ecbXML)},
+		{"csv", "eurofxref-hist.csv", []byte(ecbCSV)},
+		{"zip", "eurofxref-hist.zip", zipFeed(t, "eurofxref-hist.csv", ecbCSV)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, tt.content, 0644); err != nil {
+				t.Fatalf("failed to write feed: %v", err)
+			}
+
+			client := NewECBClient(path, time.Second)
+			resp, err := client.GetTimeSeriesRates(context.Background(),
+				time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC),
+				"EUR", []string{"USD", "GBP"})
+			if err != nil {
+				t.Fatalf("GetTimeSeriesRates() error = %v", err)
+			}
+
+			if resp.Base != "EUR" || len(resp.Rates) != 2 {
+				t.Fatalf("response = %+v, want 2 EUR-based days", resp)
+			}
+			if resp.Rates["2024-01-02"]["USD"] != 1.0956 {
+				t.Errorf("USD rate = %v, want 1.0956", resp.Rates["2024-01-02"]["USD"])
+			}
+			if _, exists := resp.Rates["2024-01-02"]["JPY"]; exists {
+				t.Error("JPY should be filtered out")
+			}
+		})
+	}
+}
+
+func TestECBClient_Rebase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "eurofxref-hist.xml")
+	os.WriteFile(path, []byte(ecbXML), 0644)
+
+	client := NewECBClient(path, time.Second)
+	ctx := context.Background()
+	start := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	resp, err := client.GetTimeSeriesRates(ctx, start, start, "USD", []string{"EUR", "JPY"})
+	if err != nil {
+		t.Fatalf("GetTimeSeriesRates() error = %v", err)
+	}
+	if got := resp.Rates["2024-01-02"]["EUR"]; math.Abs(got-1/1.0956) > 1e-9 {
+		t.Errorf("USD/EUR = %v, want %v", got, 1/1.0956)
+	}
+	if got := resp.Rates["2024-01-02"]["JPY"]; math.Abs(got-155.29/1.0956) > 1e-9 {
+		t.Errorf("USD/JPY = %v, want %v", got, 155.29/1.0956)
+	}
+
+	_, err = client.GetTimeSeriesRates(ctx, start, start, "XXX", []string{"EUR"})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("unknown base error = %v, want 404 APIError", err)
+	}
+
+	currencies, err := client.GetSupportedCurrencies(ctx)
+	if err != nil {
+		t.Fatalf("GetSupportedCurrencies() error = %v", err)
+	}
+	if currencies["EUR"] != "Euro" || currencies["GBP"] != "British Pound" || len(currencies) != 4 {
+		t.Errorf("currencies = %v", currencies)
+	}
+}
+
+func TestECBClient_Download(t *testing.T) {
+	feed := zipFeed(t, "eurofxref-hist.xml", ecbXML)
+	requests := 0
+	failing := true
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if failing {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write(feed)
+	}))
+	defer server.Close()
+
+	client := NewECBClient(server.URL+"/eurofxref-hist.zip", time.Second)
+	ctx := context.Background()
+	start := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	_, err := client.GetTimeSeriesRates(ctx, start, start, "EUR", []string{"USD"})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("error = %v, want 503 APIError", err)
+	}
+
+	failing = false
+	for i := 0; i < 2; i++ {
+		resp, err := client.GetTimeSeriesRates(ctx, start, start, "EUR", []string{"USD"})
+		if err != nil {
+			t.Fatalf("GetTimeSeriesRates() error = %v", err)
+		}
+		if resp.Rates["2024-01-02"]["USD"] != 1.0956 {
+			t.Errorf("USD rate = %v, want 1.0956", resp.Rates["2024-01-02"]["USD"])
+		}
+	}
+
+	if requests != 2 {
+		t.Errorf("requests = %d, want 2 (feed downloaded once after the failure)", requests)
+	}
+}
+
+func TestECBClient_Refresh(t *testing.T) {
+	feed := []byte(ecbCSV)
+	modified := "Wed, 03 Jan 2024 15:00:00 GMT"
+	var requests, notModified int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-Modified-Since") == modified {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Last-Modified", modified)
+		w.Write(feed)
+	}))
+	defer server.Close()
+
+	client := NewECBClient(server.URL+"/eurofxref-hist.csv", time.Second)
+	ctx := context.Background()
+	day := func(value string) time.Time {
+		d, _ := time.Parse("2006-01-02", value)
+		return d
+	}
+
+	if _, err := client.GetTimeSeriesRates(ctx, day("2024-01-02"), day("2024-01-03"), "EUR", []string{"USD"}); err != nil {
+		t.Fatalf("GetTimeSeriesRates() error = %v", err)
+	}
+	if _, err := client.GetTimeSeriesRates(ctx, day("2024-01-02"), day("2024-01-03"), "EUR", []string{"USD"}); err != nil {
+		t.Fatalf("GetTimeSeriesRates() error = %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("requests = %d, want 1 while the loaded feed covers the range", requests)
+	}
+
+	resp, err := client.GetTimeSeriesRates(ctx, day("2024-01-02"), day("2024-01-04"), "EUR", []string{"USD"})
+	if err != nil {
+		t.Fatalf("GetTimeSeriesRates() error = %v", err)
+	}
+	if requests != 2 || notModified != 1 || len(resp.Rates) != 2 {
+		t.Errorf("requests = %d (%d not modified), rates = %v; want a conditional reload of the unchanged feed", requests, notModified, resp.Rates)
+	}
+
+	feed = []byte(strings.Replace(ecbCSV, "2024-01-03,", "2024-01-04,1.0953,155.1,0.8621,N/A,\n2024-01-03,", 1))
+	modified = "Thu, 04 Jan 2024 15:00:00 GMT"
+
+	resp, err = client.GetTimeSeriesRates(ctx, day("2024-01-02"), day("2024-01-04"), "EUR", []string{"USD"})
+	if err != nil {
+		t.Fatalf("GetTimeSeriesRates() error = %v", err)
+	}
+	if resp.Rates["2024-01-04"]["USD"] != 1.0953 {
+		t.Errorf("rates = %v, want the new fixing of 2024-01-04", resp.Rates)
+	}
+}
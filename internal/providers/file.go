@@ -0,0 +1,356 @@
+package providers
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type FileClient struct {
+	dataDir string
+
+	once     sync.Once
+	loadErr  error
+	datasets map[string]map[string]map[string]float64
+	names    CurrenciesResponse
+}
+
+func NewFileClient(dataDir string) *FileClient {
+	return &FileClient{dataDir: dataDir}
+}
+
+func (c *FileClient) GetTimeSeriesRates(ctx context.Context, startDate, endDate time.Time, base string, targets []string) (*TimeSeriesResponse, error) {
+	if err := c.load(); err != nil {
+		return nil, err
+	}
+
+	base = strings.ToUpper(base)
+	rates, ok := c.ratesForBase(base)
+	if !ok {
+		return nil, &APIError{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("no snapshot data for base currency %s in %s", base, c.dataDir),
+			URL:        c.dataDir,
+		}
+	}
+
+	return filterRates(rates, startDate, endDate, base, targets), nil
+}
+
+func (c *FileClient) GetSupportedCurrencies(ctx context.Context) (CurrenciesResponse, error) {
+	if err := c.load(); err != nil {
+		return nil, err
+	}
+
+	currencies := make(CurrenciesResponse)
+	for base, dates := range c.datasets {
+		currencies[base] = c.currencyName(base)
+		for _, dayRates := range dates {
+			for target := range dayRates {
+				currencies[target] = c.currencyName(target)
+			}
+		}
+	}
+
+	return currencies, nil
+}
+
+func (c *FileClient) currencyName(code string) string {
+	if name, ok := c.names[code]; ok {
+		return name
+	}
+	return code
+}
+
+func (c *FileClient) ratesForBase(base string) (map[string]map[string]float64, bool) {
+	if rates, ok := c.datasets[base]; ok {
+		return rates, true
+	}
+
+	bases := make([]string, 0, len(c.datasets))
+	for pivot := range c.datasets {
+		bases = append(bases, pivot)
+	}
+	sort.Strings(bases)
+
+	for _, pivot := range bases {
+		if rebased := rebaseRates(c.datasets[pivot], pivot, base); len(rebased) > 0 {
+			return rebased, true
+		}
+	}
+
+	return nil, false
+}
+
+func (c *FileClient) load() error {
+	c.once.Do(func() {
+		c.loadErr = c.loadDir()
+	})
+	return c.loadErr
+}
+
+func (c *FileClient) loadDir() error {
+	entries, err := os.ReadDir(c.dataDir)
+	if err != nil {
+		return fmt.Errorf("failed to read data directory: %w", err)
+	}
+
+	c.datasets = make(map[string]map[string]map[string]float64)
+	c.names = make(CurrenciesResponse)
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		path := filepath.Join(c.dataDir, entry.Name())
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		stem := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+
+		switch {
+		case strings.EqualFold(stem, "currencies") && ext == ".json":
+			err = c.loadCurrencyNames(path)
+		case ext == ".json":
+			err = c.loadJSON(path, baseFromFilename(stem))
+		case ext == ".csv":
+			err = c.loadCSV(path, baseFromFilename(stem))
+		default:
+			continue
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to load %s: %w", entry.Name(), err)
+		}
+	}
+
+	if len(c.datasets) == 0 {
+		return fmt.Errorf("no rate snapshots (*.json, *.csv) found in %s", c.dataDir)
+	}
+
+	return nil
+}
+
+func (c *FileClient) loadCurrencyNames(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var names CurrenciesResponse
+	if err := json.Unmarshal(content, &names); err != nil {
+		return fmt.Errorf("failed to parse currencies: %w", err)
+	}
+
+	for code, name := range names {
+		c.names[strings.ToUpper(code)] = name
+	}
+	return nil
+}
+
+func (c *FileClient) loadJSON(path, fallbackBase string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var resp TimeSeriesResponse
+	if err := json.Unmarshal(content, &resp); err != nil {
+		return fmt.Errorf("failed to parse snapshot: %w", err)
+	}
+
+	base := strings.ToUpper(resp.Base)
+	if base == "" {
+		base = fallbackBase
+	}
+	if base == "" {
+		return fmt.Errorf("snapshot has no base currency")
+	}
+
+	for date, dayRates := range resp.Rates {
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return fmt.Errorf("invalid date %q", date)
+		}
+		for target, rate := range dayRates {
+			c.addRate(base, date, strings.ToUpper(target), rate)
+		}
+	}
+
+	return nil
+}
+
+func (c *FileClient) loadCSV(path, fallbackBase string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return fmt.Errorf("failed to read header: %w", err)
+	}
+	for i := range header {
+		header[i] = strings.TrimSpace(header[i])
+	}
+
+	long := len(header) == 4 &&
+		strings.EqualFold(header[0], "date") &&
+		strings.EqualFold(header[1], "base") &&
+		strings.EqualFold(header[2], "target") &&
+		strings.EqualFold(header[3], "rate")
+
+	if !long && fallbackBase == "" {
+		return fmt.Errorf("cannot determine base currency from file name")
+	}
+	if len(header) < 2 || !strings.EqualFold(header[0], "date") {
+		return fmt.Errorf("first column must be 'date'")
+	}
+
+	line := 1
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+
+		date := strings.TrimSpace(record[0])
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return fmt.Errorf("line %d: invalid date %q", line, date)
+		}
+
+		if long {
+			rate, err := strconv.ParseFloat(strings.TrimSpace(record[3]), 64)
+			if err != nil {
+				return fmt.Errorf("line %d: invalid rate %q", line, record[3])
+			}
+			c.addRate(strings.ToUpper(strings.TrimSpace(record[1])), date, strings.ToUpper(strings.TrimSpace(record[2])), rate)
+			continue
+		}
+
+		for i := 1; i < len(record) && i < len(header); i++ {
+			value := strings.TrimSpace(record[i])
+			if value == "" {
+				continue
+			}
+			rate, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return fmt.Errorf("line %d: invalid rate %q", line, value)
+			}
+			c.addRate(fallbackBase, date, strings.ToUpper(header[i]), rate)
+		}
+	}
+
+	return nil
+}
+
+func (c *FileClient) addRate(base, date, target string, rate float64) {
+	if base == target || rate == 0 {
+		return
+	}
+	if c.datasets[base] == nil {
+		c.datasets[base] = make(map[string]map[string]float64)
+	}
+	if c.datasets[base][date] == nil {
+		c.datasets[base][date] = make(map[string]float64)
+	}
+	c.datasets[base][date][target] = rate
+}
+
+func baseFromFilename(stem string) string {
+	code := stem
+	if i := strings.IndexAny(stem, "-_."); i >= 0 {
+		code = stem[:i]
+	}
+	if len(code) != 3 {
+		return ""
+	}
+	return strings.ToUpper(code)
+}
+
+func rebaseRates(rates map[string]map[string]float64, pivot, base string) map[string]map[string]float64 {
+	rebased := make(map[string]map[string]float64)
+
+	for date, dayRates := range rates {
+		pivotToBase, ok := dayRates[base]
+		if !ok || pivotToBase == 0 {
+			continue
+		}
+
+		converted := make(map[string]float64, len(dayRates))
+		converted[pivot] = 1 / pivotToBase
+		for target, rate := range dayRates {
+			if target == base {
+				continue
+			}
+			converted[target] = rate / pivotToBase
+		}
+		rebased[date] = converted
+	}
+
+	return rebased
+}
+
+func filterRates(rates map[string]map[string]float64, startDate, endDate time.Time, base string, targets []string) *TimeSeriesResponse {
+	startStr := startDate.Format("2006-01-02")
+	endStr := endDate.Format("2006-01-02")
+
+	resp := &TimeSeriesResponse{
+		Amount: 1.0,
+		Base:   base,
+		Rates:  make(map[string]map[string]float64),
+	}
+
+	for date, dayRates := range rates {
+		if date < startStr || date > endStr {
+			continue
+		}
+
+		filtered := make(map[string]float64, len(targets))
+		if len(targets) == 0 {
+			for target, rate := range dayRates {
+				filtered[target] = rate
+			}
+		}
+		for _, target := range targets {
+			target = strings.ToUpper(target)
+			if rate, ok := dayRates[target]; ok {
+				filtered[target] = rate
+			}
+		}
+		if len(filtered) == 0 {
+			continue
+		}
+
+		resp.Rates[date] = filtered
+		if resp.StartDate == "" || date < resp.StartDate {
+			resp.StartDate = date
+		}
+		if date > resp.EndDate {
+			resp.EndDate = date
+		}
+	}
+
+	if resp.StartDate == "" {
+		resp.StartDate = startStr
+		resp.EndDate = endStr
+	}
+
+	return resp
+}
@@ -0,0 +1,159 @@
+package providers
+
+import (
+	"context"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeSnapshot(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+}
+
+func TestFileClient_JSONSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	writeSnapshot(t, dir, "eur-2023.json", `{
+		"amount": 1.0,
+		"base": "EUR",
+		"start_date": "2023-01-02",
+		"end_date": "2023-01-04",
+		"rates": {
+			"2023-01-02": {"USD": 1.0683, "GBP": 0.88383},
+			"2023-01-03": {"USD": 1.0545, "GBP": 0.87728},
+			"2023-01-04": {"USD": 1.0599, "GBP": 0.87720}
+		}
+	}`)
+
+	client := NewFileClient(dir)
+	ctx := context.Background()
+
+	resp, err := client.GetTimeSeriesRates(ctx,
+		time.Date(2023, 1, 3, 0, 0, 0, 0, time.UTC),
+		time.Date(2023, 1, 10, 0, 0, 0, 0, time.UTC),
+		"EUR", []string{"USD"})
+	if err != nil {
+		t.Fatalf("GetTimeSeriesRates() error = %v", err)
+	}
+
+	if resp.Base != "EUR" {
+		t.Errorf("Base = %s, want EUR", resp.Base)
+	}
+	if resp.StartDate != "2023-01-03" || resp.EndDate != "2023-01-04" {
+		t.Errorf("range = %s..%s, want 2023-01-03..2023-01-04", resp.StartDate, resp.EndDate)
+	}
+	if len(resp.Rates) != 2 {
+		t.Errorf("Rates length = %d, want 2", len(resp.Rates))
+	}
+	if _, exists := resp.Rates["2023-01-03"]["GBP"]; exists {
+		t.Error("GBP should be filtered out")
+	}
+	if resp.Rates["2023-01-04"]["USD"] != 1.0599 {
+		t.Errorf("USD rate = %v, want 1.0599", resp.Rates["2023-01-04"]["USD"])
+	}
+}
+
+func TestFileClient_CSVSnapshots(t *testing.T) {
+	dir := t.TempDir()
+	writeSnapshot(t, dir, "USD.csv", "Date,EUR,GBP\n2023-01-02,0.936,0.827\n2023-01-03,0.948,\n")
+	writeSnapshot(t, dir, "rates.csv", "date,base,target,rate\n2023-01-02,CHF,EUR,1.012\n")
+
+	client := NewFileClient(dir)
+	ctx := context.Background()
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2023, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	resp, err := client.GetTimeSeriesRates(ctx, start, end, "USD", []string{"EUR", "GBP"})
+	if err != nil {
+		t.Fatalf("GetTimeSeriesRates(USD) error = %v", err)
+	}
+	if resp.Rates["2023-01-02"]["GBP"] != 0.827 {
+		t.Errorf("GBP rate = %v, want 0.827", resp.Rates["2023-01-02"]["GBP"])
+	}
+	if _, exists := resp.Rates["2023-01-03"]["GBP"]; exists {
+		t.Error("empty CSV cell should not produce a rate")
+	}
+
+	resp, err = client.GetTimeSeriesRates(ctx, start, end, "CHF", []string{"EUR"})
+	if err != nil {
+		t.Fatalf("GetTimeSeriesRates(CHF) error = %v", err)
+	}
+	if resp.Rates["2023-01-02"]["EUR"] != 1.012 {
+		t.Errorf("EUR rate = %v, want 1.012", resp.Rates["2023-01-02"]["EUR"])
+	}
+}
+
+func TestFileClient_Rebase(t *testing.T) {
+	dir := t.TempDir()
+	writeSnapshot(t, dir, "EUR.csv", "Date,USD,GBP\n2023-01-02,1.25,0.8\n")
+
+	client := NewFileClient(dir)
+	resp, err := client.GetTimeSeriesRates(context.Background(),
+		time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+		time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+		"USD", []string{"EUR", "GBP"})
+	if err != nil {
+		t.Fatalf("GetTimeSeriesRates() error = %v", err)
+	}
+
+	if got := resp.Rates["2023-01-02"]["EUR"]; math.Abs(got-0.8) > 1e-9 {
+		t.Errorf("USD/EUR = %v, want 0.8", got)
+	}
+	if got := resp.Rates["2023-01-02"]["GBP"]; math.Abs(got-0.64) > 1e-9 {
+		t.Errorf("USD/GBP = %v, want 0.64", got)
+	}
+}
+
+func TestFileClient_GetSupportedCurrencies(t *testing.T) {
+	dir := t.TempDir()
+	writeSnapshot(t, dir, "EUR.csv", "Date,USD,HUF\n2023-01-02,1.07,400.5\n")
+	writeSnapshot(t, dir, "currencies.json", `{"EUR": "Euro", "USD": "United States Dollar"}`)
+
+	client := NewFileClient(dir)
+	currencies, err := client.GetSupportedCurrencies(context.Background())
+	if err != nil {
+		t.Fatalf("GetSupportedCurrencies() error = %v", err)
+	}
+
+	if len(currencies) != 3 {
+		t.Errorf("Currencies length = %d, want 3", len(currencies))
+	}
+	if currencies["EUR"] != "Euro" {
+		t.Errorf("EUR = %s, want Euro", currencies["EUR"])
+	}
+	if currencies["HUF"] != "HUF" {
+		t.Errorf("HUF = %s, want HUF", currencies["HUF"])
+	}
+}
+
+func TestFileClient_Errors(t *testing.T) {
+	client := NewFileClient(filepath.Join(t.TempDir(), "missing"))
+	_, err := client.GetSupportedCurrencies(context.Background())
+	if err == nil {
+		t.Error("Expected error for missing data directory, got nil")
+	}
+
+	dir := t.TempDir()
+	writeSnapshot(t, dir, "EUR.csv", "Date,USD\n2023-01-02,1.07\n")
+	client = NewFileClient(dir)
+
+	_, err = client.GetTimeSeriesRates(context.Background(), time.Now(), time.Now(), "JPY", []string{"CHF"})
+	apiErr, ok := err.(*APIError)
+	if !ok {
+		t.Fatalf("Expected APIError, got %T", err)
+	}
+	if apiErr.StatusCode != 404 {
+		t.Errorf("StatusCode = %d, want 404", apiErr.StatusCode)
+	}
+
+	writeSnapshot(t, dir, "GBP.csv", "Date,USD\nnot-a-date,1.2\n")
+	client = NewFileClient(dir)
+	if _, err := client.GetSupportedCurrencies(context.Background()); err == nil {
+		t.Error("Expected error for invalid CSV date, got nil")
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -16,129 +17,306 @@ type TimeSeriesResponse struct {
 	StartDate string                        `json:"start_date"`
 	EndDate   string                        `json:"end_date"`
 	Rates     map[string]map[string]float64 `json:"rates"`
+	Provider  string                        `json:"provider,omitempty"`
 }
 
 type CurrenciesResponse map[string]string
 
+const (
+	defaultChunkDays   = 365
+	defaultConcurrency = 4
+
+	defaultRequestsPerSecond = 5
+	defaultBurst             = 5
+	defaultBackoffBase       = 500 * time.Millisecond
+	defaultBackoffMax        = 30 * time.Second
+)
+
 type FrankfurterClient struct {
-	baseURL       string
-	timeout       time.Duration
-	retryAttempts int
-	httpClient    *http.Client
+	baseURL     string
+	chunkDays   int
+	concurrency int
+	retry       retryPolicy
+	limiter     *RateLimiter
+	httpClient  *http.Client
 }
 
-func NewFrankfurterClient(baseURL string, timeout time.Duration, retryAttempts int) *FrankfurterClient {
-	return &FrankfurterClient{
-		baseURL:       strings.TrimSuffix(baseURL, "/"),
-		timeout:       timeout,
-		retryAttempts: retryAttempts,
+type FrankfurterOption func(*FrankfurterClient)
+
+func WithChunkDays(days int) FrankfurterOption {
+	return func(c *FrankfurterClient) {
+		if days > 0 {
+			c.chunkDays = days
+		}
+	}
+}
+
+func WithConcurrency(n int) FrankfurterOption {
+	return func(c *FrankfurterClient) {
+		if n > 0 {
+			c.concurrency = n
+		}
+	}
+}
+
+func WithRateLimit(perSecond float64, burst int) FrankfurterOption {
+	return func(c *FrankfurterClient) {
+		c.limiter = NewRateLimiter(perSecond, burst)
+	}
+}
+
+func WithRateLimiter(limiter *RateLimiter) FrankfurterOption {
+	return func(c *FrankfurterClient) {
+		if limiter != nil {
+			c.limiter = limiter
+		}
+	}
+}
+
+func NewFrankfurterClient(baseURL string, timeout time.Duration, retryAttempts int, opts ...FrankfurterOption) *FrankfurterClient {
+	c := &FrankfurterClient{
+		baseURL:     strings.TrimSuffix(baseURL, "/"),
+		chunkDays:   defaultChunkDays,
+		concurrency: defaultConcurrency,
+		retry: retryPolicy{
+			attempts: retryAttempts,
+			base:     defaultBackoffBase,
+			max:      defaultBackoffMax,
+		},
+		limiter: NewRateLimiter(defaultRequestsPerSecond, defaultBurst),
 		httpClient: &http.Client{
 			Timeout: timeout,
 		},
 	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+type dateChunk struct {
+	start time.Time
+	end   time.Time
+}
+
+func splitRange(startDate, endDate time.Time, days int) []dateChunk {
+	if days <= 0 || endDate.Before(startDate) {
+		return []dateChunk{{start: startDate, end: endDate}}
+	}
+
+	var chunks []dateChunk
+	for start := startDate; !start.After(endDate); {
+		end := start.AddDate(0, 0, days-1)
+		if end.After(endDate) {
+			end = endDate
+		}
+		chunks = append(chunks, dateChunk{start: start, end: end})
+		start = end.AddDate(0, 0, 1)
+	}
+	return chunks
 }
 
 func (c *FrankfurterClient) GetTimeSeriesRates(ctx context.Context, startDate, endDate time.Time, base string, targets []string) (*TimeSeriesResponse, error) {
-	startStr := startDate.Format("2006-01-02")
-	endStr := endDate.Format("2006-01-02")
+	chunks := splitRange(startDate, endDate, c.chunkDays)
+	if len(chunks) == 1 {
+		return c.fetchChunk(ctx, startDate, endDate, base, targets)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
+	progress := progressFromContext(ctx)
+	progress(0, len(chunks))
+
+	results := make([]*TimeSeriesResponse, len(chunks))
+	jobs := make(chan int)
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		done     int
+		firstErr error
+	)
+
+	workers := min(c.concurrency, len(chunks))
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				resp, err := c.fetchChunk(ctx, chunks[i].start, chunks[i].end, base, targets)
+
+				mu.Lock()
+				if err != nil {
+					if firstErr == nil {
+						firstErr = fmt.Errorf("failed to fetch %s..%s: %w",
+							chunks[i].start.Format("2006-01-02"), chunks[i].end.Format("2006-01-02"), err)
+						cancel()
+					}
+				} else {
+					results[i] = resp
+					done++
+					progress(done, len(chunks))
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for i := range chunks {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	return mergeResponses(results), nil
+}
+
+func mergeResponses(responses []*TimeSeriesResponse) *TimeSeriesResponse {
+	merged := &TimeSeriesResponse{
+		Rates: make(map[string]map[string]float64),
+	}
+
+	for _, resp := range responses {
+		if merged.Base == "" {
+			merged.Amount = resp.Amount
+			merged.Base = resp.Base
+			merged.StartDate = resp.StartDate
+		}
+		if resp.EndDate != "" {
+			merged.EndDate = resp.EndDate
+		}
+		for date, dayRates := range resp.Rates {
+			merged.Rates[date] = dayRates
+		}
+	}
+
+	return merged
+}
+
+func (c *FrankfurterClient) fetchChunk(ctx context.Context, startDate, endDate time.Time, base string, targets []string) (*TimeSeriesResponse, error) {
 	url := fmt.Sprintf("%s/%s..%s?from=%s&to=%s",
 		c.baseURL,
-		startStr,
-		endStr,
+		startDate.Format("2006-01-02"),
+		endDate.Format("2006-01-02"),
 		base,
 		strings.Join(targets, ","),
 	)
 
-	var resp *TimeSeriesResponse
+	body, err := c.get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &TimeSeriesResponse{}
+	if err := json.Unmarshal(body, resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return resp, nil
+}
+
+func (c *FrankfurterClient) GetSupportedCurrencies(ctx context.Context) (CurrenciesResponse, error) {
+	url := fmt.Sprintf("%s/currencies", c.baseURL)
+
+	body, err := c.get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp CurrenciesResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return resp, nil
+}
+
+func (c *FrankfurterClient) get(ctx context.Context, url string) ([]byte, error) {
 	var lastErr error
+	var wait time.Duration
 
-	for attempt := 0; attempt < c.retryAttempts; attempt++ {
+	for attempt := 0; attempt < max(c.retry.attempts, 1); attempt++ {
 		if attempt > 0 {
-			backoff := time.Duration(attempt*attempt) * time.Second
+			if wait <= 0 {
+				wait = c.retry.backoff(attempt)
+			}
+			timer := time.NewTimer(wait)
 			select {
-			case <-time.After(backoff):
+			case <-timer.C:
 			case <-ctx.Done():
+				timer.Stop()
 				return nil, ctx.Err()
 			}
+			wait = 0
+		}
+
+		if err := c.limiter.Wait(ctx); err != nil {
+			return nil, err
 		}
 
 		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 		if err != nil {
-			lastErr = err
-			continue
+			return nil, err
 		}
 
 		httpResp, err := c.httpClient.Do(req)
 		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
 			lastErr = err
 			continue
 		}
-		defer httpResp.Body.Close()
-
-		if httpResp.StatusCode != http.StatusOK {
-			body, _ := io.ReadAll(httpResp.Body)
-			lastErr = &APIError{
-				StatusCode: httpResp.StatusCode,
-				Message:    fmt.Sprintf("API request failed: %s", string(body)),
-				URL:        url,
-			}
-			if httpResp.StatusCode >= 500 && attempt < c.retryAttempts-1 {
-				continue
-			}
-			return nil, lastErr
-		}
 
 		body, err := io.ReadAll(httpResp.Body)
+		httpResp.Body.Close()
 		if err != nil {
 			lastErr = err
 			continue
 		}
 
-		resp = &TimeSeriesResponse{}
-		if err := json.Unmarshal(body, resp); err != nil {
-			lastErr = fmt.Errorf("failed to parse response: %w", err)
-			continue
+		if httpResp.StatusCode == http.StatusOK {
+			return body, nil
 		}
 
-		return resp, nil
-	}
-
-	return nil, lastErr
-}
-
-func (c *FrankfurterClient) GetSupportedCurrencies(ctx context.Context) (CurrenciesResponse, error) {
-	url := fmt.Sprintf("%s/currencies", c.baseURL)
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	httpResp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer httpResp.Body.Close()
-
-	if httpResp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(httpResp.Body)
-		return nil, &APIError{
+		lastErr = &APIError{
 			StatusCode: httpResp.StatusCode,
 			Message:    fmt.Sprintf("API request failed: %s", string(body)),
 			URL:        url,
 		}
-	}
-
-	body, err := io.ReadAll(httpResp.Body)
-	if err != nil {
-		return nil, err
-	}
 
-	var resp CurrenciesResponse
-	if err := json.Unmarshal(body, &resp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+		if httpResp.StatusCode != http.StatusTooManyRequests && httpResp.StatusCode < 500 {
+			return nil, lastErr
+		}
+		if d, ok := retryAfter(httpResp.Header, time.Now()); ok {
+			if d > c.retry.max {
+				return nil, fmt.Errorf("%w: server asked to retry after %s, longer than the %s limit", lastErr, d, c.retry.max)
+			}
+			wait = d
+		}
+		if httpResp.StatusCode == http.StatusTooManyRequests {
+			if wait <= 0 {
+				wait = c.retry.backoff(attempt + 1)
+			}
+			c.limiter.Pause(wait)
+		}
 	}
 
-	return resp, nil
+	return nil, lastErr
 }
@@ -2,8 +2,12 @@ package providers
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -19,12 +23,12 @@ func TestNewFrankfurterClient(t *testing.T) {
 		t.Errorf("baseURL = %v, want https://api.example.com", client.baseURL)
 	}
 
-	if client.timeout != 30*time.Second {
-		t.Errorf("timeout = %v, want 30s", client.timeout)
+	if client.httpClient.Timeout != 30*time.Second {
+		t.Errorf("httpClient.Timeout = %v, want 30s", client.httpClient.Timeout)
 	}
 
-	if client.retryAttempts != 3 {
-		t.Errorf("retryAttempts = %v, want 3", client.retryAttempts)
+	if client.retry.attempts != 3 {
+		t.Errorf("retry.attempts = %v, want 3", client.retry.attempts)
 	}
 }
 
@@ -203,3 +207,207 @@ func TestGetTimeSeriesRates_ContextCancellation(t *testing.T) {
 		t.Error("Expected error for cancelled context, got nil")
 	}
 }
+
+func TestSplitRange(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2022, 6, 30, 0, 0, 0, 0, time.UTC)
+
+	chunks := splitRange(start, end, 365)
+	if len(chunks) != 3 {
+		t.Fatalf("chunks = %d, want 3", len(chunks))
+	}
+	if !chunks[0].start.Equal(start) || !chunks[2].end.Equal(end) {
+		t.Errorf("chunks do not cover %s..%s: %+v", start, end, chunks)
+	}
+	for i := 1; i < len(chunks); i++ {
+		if !chunks[i].start.Equal(chunks[i-1].end.AddDate(0, 0, 1)) {
+			t.Errorf("chunk %d starts at %s, want the day after %s", i, chunks[i].start, chunks[i-1].end)
+		}
+	}
+
+	if single := splitRange(start, start, 365); len(single) != 1 {
+		t.Errorf("single day chunks = %d, want 1", len(single))
+	}
+}
+
+func TestGetTimeSeriesRates_Chunked(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	failures := map[string]bool{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		path := r.URL.Path
+		failOnce := strings.HasPrefix(path, "/2023-01-11") && !failures[path]
+		failures[path] = true
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+
+		if failOnce {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		dates := strings.SplitN(strings.TrimPrefix(path, "/"), "..", 2)
+		fmt.Fprintf(w, `{"amount":1.0,"base":"USD","start_date":"%s","end_date":"%s","rates":{"%s":{"EUR":0.9},"%s":{"EUR":0.91}}}`,
+			dates[0], dates[1], dates[0], dates[1])
+	}))
+	defer server.Close()
+
+	client := NewFrankfurterClient(server.URL, 5*time.Second, 2, WithChunkDays(5), WithConcurrency(2))
+	client.retry.base = time.Millisecond
+
+	var progress []int
+	ctx := WithProgress(context.Background(), func(done, total int) {
+		if total != 4 {
+			t.Errorf("progress total = %d, want 4", total)
+		}
+		progress = append(progress, done)
+	})
+
+	resp, err := client.GetTimeSeriesRates(ctx,
+		time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2023, 1, 20, 0, 0, 0, 0, time.UTC),
+		"USD", []string{"EUR"})
+	if err != nil {
+		t.Fatalf("GetTimeSeriesRates() error = %v", err)
+	}
+
+	if resp.StartDate != "2023-01-01" || resp.EndDate != "2023-01-20" {
+		t.Errorf("range = %s..%s, want 2023-01-01..2023-01-20", resp.StartDate, resp.EndDate)
+	}
+	if len(resp.Rates) != 8 {
+		t.Errorf("Rates length = %d, want 8", len(resp.Rates))
+	}
+	if maxInFlight > 2 {
+		t.Errorf("max concurrent requests = %d, want <= 2", maxInFlight)
+	}
+	if len(progress) != 5 || progress[len(progress)-1] != 4 {
+		t.Errorf("progress = %v, want 0..4", progress)
+	}
+}
+
+func TestGetTimeSeriesRates_ChunkFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/2023-01-06") {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"message":"bad request"}`))
+			return
+		}
+		w.Write([]byte(`{"amount":1.0,"base":"USD","rates":{}}`))
+	}))
+	defer server.Close()
+
+	client := NewFrankfurterClient(server.URL, 5*time.Second, 1, WithChunkDays(5))
+
+	_, err := client.GetTimeSeriesRates(context.Background(),
+		time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2023, 1, 20, 0, 0, 0, 0, time.UTC),
+		"USD", []string{"EUR"})
+	if err == nil {
+		t.Fatal("expected error when a chunk fails")
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("error = %v, want wrapped 400 APIError", err)
+	}
+}
+
+func TestGetTimeSeriesRates_TooManyRequests(t *testing.T) {
+	requests := 0
+	var firstAt, retryAt time.Time
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			firstAt = time.Now()
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		retryAt = time.Now()
+		w.Write([]byte(`{"amount":1.0,"base":"USD","rates":{"2023-01-02":{"EUR":0.9}}}`))
+	}))
+	defer server.Close()
+
+	client := NewFrankfurterClient(server.URL, 5*time.Second, 3)
+
+	resp, err := client.GetTimeSeriesRates(context.Background(),
+		time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+		time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+		"USD", []string{"EUR"})
+	if err != nil {
+		t.Fatalf("GetTimeSeriesRates() error = %v", err)
+	}
+	if resp.Rates["2023-01-02"]["EUR"] != 0.9 {
+		t.Errorf("EUR rate = %v, want 0.9", resp.Rates["2023-01-02"]["EUR"])
+	}
+	if requests != 2 {
+		t.Errorf("requests = %d, want 2", requests)
+	}
+	if waited := retryAt.Sub(firstAt); waited < 900*time.Millisecond {
+		t.Errorf("retried after %v, want Retry-After of 1s to be honored", waited)
+	}
+}
+
+func TestGetTimeSeriesRates_RetryAfterTooLong(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Retry-After", "86400")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := NewFrankfurterClient(server.URL, 5*time.Second, 3)
+
+	start := time.Now()
+	_, err := client.GetTimeSeriesRates(context.Background(),
+		time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+		time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+		"USD", []string{"EUR"})
+	if err == nil || !strings.Contains(err.Error(), "retry after 24h0m0s") {
+		t.Fatalf("GetTimeSeriesRates() error = %v, want the Retry-After refused", err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("error = %v, want wrapped 429 APIError", err)
+	}
+	if requests != 1 || time.Since(start) > time.Second {
+		t.Errorf("requests = %d after %v, want one request and no wait", requests, time.Since(start))
+	}
+}
+
+func TestGetSupportedCurrencies_Retry(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"EUR":"Euro"}`))
+	}))
+	defer server.Close()
+
+	client := NewFrankfurterClient(server.URL, 5*time.Second, 2)
+	client.retry.base = time.Millisecond
+
+	currencies, err := client.GetSupportedCurrencies(context.Background())
+	if err != nil {
+		t.Fatalf("GetSupportedCurrencies() error = %v", err)
+	}
+	if currencies["EUR"] != "Euro" || requests != 2 {
+		t.Errorf("currencies = %v after %d requests, want Euro after 2", currencies, requests)
+	}
+}
@@ -0,0 +1,18 @@
+package providers
+
+import "context"
+
+type ProgressFunc func(done, total int)
+
+type progressKey struct{}
+
+func WithProgress(ctx context.Context, fn ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+func progressFromContext(ctx context.Context) ProgressFunc {
+	if fn, ok := ctx.Value(progressKey{}).(ProgressFunc); ok && fn != nil {
+		return fn
+	}
+	return func(done, total int) {}
+}
@@ -0,0 +1,219 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Capabilities struct {
+	Currencies   []string
+	EarliestDate time.Time
+	MaxRange     time.Duration
+}
+
+func (c Capabilities) Supports(startDate, endDate time.Time, base string, targets []string) bool {
+	if !c.EarliestDate.IsZero() && endDate.Before(c.EarliestDate) {
+		return false
+	}
+
+	if c.MaxRange > 0 && endDate.Sub(startDate) > c.MaxRange {
+		return false
+	}
+
+	if len(c.Currencies) > 0 {
+		supported := make(map[string]bool, len(c.Currencies))
+		for _, currency := range c.Currencies {
+			supported[strings.ToUpper(currency)] = true
+		}
+		if !supported[strings.ToUpper(base)] {
+			return false
+		}
+		for _, target := range targets {
+			if !supported[strings.ToUpper(target)] {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+type registeredProvider struct {
+	name   string
+	client APIClient
+	caps   Capabilities
+}
+
+type Registry struct {
+	mu        sync.RWMutex
+	providers map[string]registeredProvider
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		providers: make(map[string]registeredProvider),
+	}
+}
+
+func (r *Registry) Register(name string, client APIClient, caps Capabilities) error {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return fmt.Errorf("provider name cannot be empty")
+	}
+	if client == nil {
+		return fmt.Errorf("provider %s has no client", name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.providers[name]; exists {
+		return fmt.Errorf("provider %s is already registered", name)
+	}
+
+	r.providers[name] = registeredProvider{name: name, client: client, caps: caps}
+	return nil
+}
+
+func (r *Registry) Get(name string) (APIClient, Capabilities, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	p, ok := r.providers[strings.ToLower(name)]
+	return p.client, p.caps, ok
+}
+
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.namesLocked()
+}
+
+func (r *Registry) Chain(names ...string) (*FallbackClient, error) {
+	if len(names) == 0 {
+		return nil, fmt.Errorf("provider chain cannot be empty")
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	chain := make([]registeredProvider, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if seen[name] {
+			continue
+		}
+		p, ok := r.providers[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown provider: %s (available: %s)", name, strings.Join(r.namesLocked(), ", "))
+		}
+		seen[name] = true
+		chain = append(chain, p)
+	}
+
+	return &FallbackClient{providers: chain}, nil
+}
+
+func (r *Registry) namesLocked() []string {
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+type FallbackClient struct {
+	providers []registeredProvider
+}
+
+func (c *FallbackClient) GetTimeSeriesRates(ctx context.Context, startDate, endDate time.Time, base string, targets []string) (*TimeSeriesResponse, error) {
+	var errs []error
+
+	for _, p := range c.providers {
+		if !p.caps.Supports(startDate, endDate, base, targets) {
+			errs = append(errs, fmt.Errorf("%s: %s/%s from %s to %s is not supported", p.name,
+				base, strings.Join(targets, ","), startDate.Format("2006-01-02"), endDate.Format("2006-01-02")))
+			continue
+		}
+
+		resp, err := p.client.GetTimeSeriesRates(ctx, startDate, endDate, base, targets)
+		if err == nil {
+			resp.Provider = p.name
+			return resp, nil
+		}
+
+		if !shouldFallback(ctx, err) {
+			return nil, err
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", p.name, err))
+	}
+
+	return nil, chainError(errs)
+}
+
+// GetSupportedCurrencies returns the list of the first provider that
+// answers, so that only currencies it can quote are offered; the next
+// provider is asked only when one fails.
+func (c *FallbackClient) GetSupportedCurrencies(ctx context.Context) (CurrenciesResponse, error) {
+	var errs []error
+
+	for _, p := range c.providers {
+		resp, err := p.client.GetSupportedCurrencies(ctx)
+		if err == nil {
+			return resp, nil
+		}
+
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", p.name, err))
+	}
+
+	return nil, chainError(errs)
+}
+
+func (c *FallbackClient) Names() []string {
+	names := make([]string, len(c.providers))
+	for i, p := range c.providers {
+		names[i] = p.name
+	}
+	return names
+}
+
+func shouldFallback(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= 500 || apiErr.StatusCode == http.StatusTooManyRequests
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	return errors.Is(err, context.DeadlineExceeded)
+}
+
+func chainError(errs []error) error {
+	if len(errs) == 0 {
+		return fmt.Errorf("no providers configured")
+	}
+	if len(errs) == 1 {
+		return errs[0]
+	}
+	return fmt.Errorf("all providers failed: %w", errors.Join(errs...))
+}
@@ -0,0 +1,194 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type stubClient struct {
+	resp       *TimeSeriesResponse
+	err        error
+	currencies CurrenciesResponse
+	calls      int
+	lookups    int
+}
+
+func (s *stubClient) GetTimeSeriesRates(ctx context.Context, startDate, endDate time.Time, base string, targets []string) (*TimeSeriesResponse, error) {
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+	resp := *s.resp
+	return &resp, nil
+}
+
+func (s *stubClient) GetSupportedCurrencies(ctx context.Context) (CurrenciesResponse, error) {
+	s.lookups++
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.currencies, nil
+}
+
+func newStubResponse() *TimeSeriesResponse {
+	return &TimeSeriesResponse{
+		Base:  "USD",
+		Rates: map[string]map[string]float64{"2023-01-02": {"EUR": 0.93}},
+	}
+}
+
+func TestRegistry_RegisterAndChain(t *testing.T) {
+	registry := NewRegistry()
+
+	if err := registry.Register("Primary", &stubClient{}, Capabilities{}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if err := registry.Register("primary", &stubClient{}, Capabilities{}); err == nil {
+		t.Error("Register() should reject duplicate names")
+	}
+	if err := registry.Register("", &stubClient{}, Capabilities{}); err == nil {
+		t.Error("Register() should reject empty names")
+	}
+
+	if _, _, ok := registry.Get("PRIMARY"); !ok {
+		t.Error("Get() should be case-insensitive")
+	}
+
+	if _, err := registry.Chain("primary", "missing"); err == nil {
+		t.Error("Chain() should fail for unknown providers")
+	}
+
+	chain, err := registry.Chain("primary", "primary")
+	if err != nil {
+		t.Fatalf("Chain() error = %v", err)
+	}
+	if names := chain.Names(); len(names) != 1 || names[0] != "primary" {
+		t.Errorf("Names() = %v, want [primary]", names)
+	}
+}
+
+func TestFallbackClient_FallsBackOnServerError(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		wantFallback bool
+	}{
+		{"server error", &APIError{StatusCode: http.StatusBadGateway, Message: "bad gateway"}, true},
+		{"rate limited", &APIError{StatusCode: http.StatusTooManyRequests, Message: "slow down"}, true},
+		{"timeout", context.DeadlineExceeded, true},
+		{"not found", &APIError{StatusCode: http.StatusNotFound, Message: "not found"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primary := &stubClient{err: tt.err}
+			backup := &stubClient{resp: newStubResponse()}
+
+			registry := NewRegistry()
+			registry.Register("primary", primary, Capabilities{})
+			registry.Register("backup", backup, Capabilities{})
+			chain, _ := registry.Chain("primary", "backup")
+
+			resp, err := chain.GetTimeSeriesRates(context.Background(), time.Now(), time.Now(), "USD", []string{"EUR"})
+
+			if !tt.wantFallback {
+				if !errors.Is(err, tt.err) {
+					t.Errorf("error = %v, want %v", err, tt.err)
+				}
+				if backup.calls != 0 {
+					t.Errorf("backup calls = %d, want 0", backup.calls)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("GetTimeSeriesRates() error = %v", err)
+			}
+			if resp.Provider != "backup" {
+				t.Errorf("Provider = %s, want backup", resp.Provider)
+			}
+		})
+	}
+}
+
+func TestFallbackClient_AllProvidersFail(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register("a", &stubClient{err: &APIError{StatusCode: 500, Message: "a down"}}, Capabilities{})
+	registry.Register("b", &stubClient{err: &APIError{StatusCode: 503, Message: "b down"}}, Capabilities{})
+	chain, _ := registry.Chain("a", "b")
+
+	_, err := chain.GetTimeSeriesRates(context.Background(), time.Now(), time.Now(), "USD", []string{"EUR"})
+	if err == nil {
+		t.Fatal("expected error when every provider fails")
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Errorf("error should wrap the provider errors, got %v", err)
+	}
+}
+
+func TestFallbackClient_SkipsUnsupportedRequests(t *testing.T) {
+	limited := &stubClient{resp: newStubResponse()}
+	full := &stubClient{resp: newStubResponse()}
+
+	registry := NewRegistry()
+	registry.Register("limited", limited, Capabilities{
+		Currencies:   []string{"USD", "GBP"},
+		EarliestDate: time.Date(1999, 1, 4, 0, 0, 0, 0, time.UTC),
+		MaxRange:     30 * 24 * time.Hour,
+	})
+	registry.Register("full", full, Capabilities{})
+	chain, _ := registry.Chain("limited", "full")
+
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	resp, err := chain.GetTimeSeriesRates(context.Background(), start, start.AddDate(0, 0, 7), "USD", []string{"EUR"})
+	if err != nil {
+		t.Fatalf("GetTimeSeriesRates() error = %v", err)
+	}
+	if resp.Provider != "full" || limited.calls != 0 {
+		t.Errorf("unsupported currency should skip provider: provider=%s limited calls=%d", resp.Provider, limited.calls)
+	}
+
+	resp, err = chain.GetTimeSeriesRates(context.Background(), start, start.AddDate(0, 0, 7), "USD", []string{"GBP"})
+	if err != nil {
+		t.Fatalf("GetTimeSeriesRates() error = %v", err)
+	}
+	if resp.Provider != "limited" {
+		t.Errorf("Provider = %s, want limited", resp.Provider)
+	}
+
+	resp, _ = chain.GetTimeSeriesRates(context.Background(), start, start.AddDate(1, 0, 0), "USD", []string{"GBP"})
+	if resp.Provider != "full" {
+		t.Errorf("range beyond MaxRange: Provider = %s, want full", resp.Provider)
+	}
+
+	old := time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)
+	resp, _ = chain.GetTimeSeriesRates(context.Background(), old, old.AddDate(0, 0, 7), "USD", []string{"GBP"})
+	if resp.Provider != "full" {
+		t.Errorf("range before EarliestDate: Provider = %s, want full", resp.Provider)
+	}
+}
+
+func TestFallbackClient_GetSupportedCurrencies(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register("down", &stubClient{err: &APIError{StatusCode: 500, Message: "down"}}, Capabilities{})
+	registry.Register("a", &stubClient{currencies: CurrenciesResponse{"USD": "US Dollar"}}, Capabilities{})
+	b := &stubClient{currencies: CurrenciesResponse{"USD": "USD", "EUR": "Euro"}}
+	registry.Register("b", b, Capabilities{})
+	chain, _ := registry.Chain("down", "a", "b")
+
+	currencies, err := chain.GetSupportedCurrencies(context.Background())
+	if err != nil {
+		t.Fatalf("GetSupportedCurrencies() error = %v", err)
+	}
+	if len(currencies) != 1 || currencies["USD"] != "US Dollar" {
+		t.Errorf("currencies = %v, want the list of the first provider that answers", currencies)
+	}
+	if b.lookups != 0 {
+		t.Errorf("lookups of b = %d, want none after a answered", b.lookups)
+	}
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kaze/xrv/internal/calendar"
+	"github.com/kaze/xrv/internal/domain"
+)
+
+// PeriodRate summarizes one target over one period for accounting closes.
+// Average, High and Low cover the rate in effect on each business day, which
+// is the day's fixing or, when the day has none, the previous one; Filled
+// counts those carried days. Close is the rate in effect on the last business
+// day, CloseDate. Partial marks periods with business days before the first
+// or after the last fixing, such as the current month.
+type PeriodRate struct {
+	Start     time.Time
+	End       time.Time
+	Average   float64
+	Close     float64
+	CloseDate time.Time
+	High      float64
+	Low       float64
+	Days      int
+	Filled    int
+	Partial   bool
+}
+
+type PeriodAverages struct {
+	Base    domain.Currency
+	Period  Interval
+	Targets []domain.Currency
+	Rates   map[domain.Currency][]PeriodRate
+}
+
+// PeriodAverages fetches daily fixings and summarizes them per opts.Interval,
+// which must be longer than a day. Aggregation and Gaps are ignored.
+func (s *Service) PeriodAverages(ctx context.Context, opts FetchOptions) (*PeriodAverages, error) {
+	period := opts.Interval
+	if period == "" || period == IntervalDay {
+		return nil, fmt.Errorf("period must be week, month, quarter or year")
+	}
+
+	opts.Interval, opts.Aggregation, opts.Gaps = IntervalDay, "", GapOmit
+	data, err := s.FetchTimeSeriesData(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return s.CalculatePeriodAverages(data, period), nil
+}
+
+// CalculatePeriodAverages summarizes daily data per period using the
+// service's business-day calendar. Fixings on other days are left out, and
+// each target only covers the days from its first to its last fixing.
+func (s *Service) CalculatePeriodAverages(data *domain.TimeSeriesData, period Interval) *PeriodAverages {
+	result := &PeriodAverages{
+		Base:    data.Base,
+		Period:  period,
+		Targets: data.Targets,
+		Rates:   make(map[domain.Currency][]PeriodRate, len(data.Targets)),
+	}
+
+	for _, target := range data.Targets {
+		fixings := make(map[time.Time]float64)
+		var first, last time.Time
+		for _, dp := range data.DataPoints {
+			rate, exists := dp.Rates[target]
+			if !exists || !s.calendar.IsBusinessDay(dp.Date) {
+				continue
+			}
+			if first.IsZero() {
+				first = dp.Date
+			}
+			fixings[dp.Date], last = rate, dp.Date
+		}
+		if first.IsZero() {
+			continue
+		}
+
+		result.Rates[target] = periodRates(s.calendar, period, fixings, first, last)
+	}
+
+	return result
+}
+
+func periodRates(cal calendar.Calendar, period Interval, fixings map[time.Time]float64, first, last time.Time) []PeriodRate {
+	var rates []PeriodRate
+	var sum, current float64
+	for _, date := range calendar.BusinessDays(cal, first, last) {
+		rate, fixed := fixings[date]
+		if fixed {
+			current = rate
+		}
+
+		if len(rates) == 0 || date.After(rates[len(rates)-1].End) {
+			if len(rates) > 0 {
+				rates[len(rates)-1].Average = sum / float64(rates[len(rates)-1].Days)
+			}
+			sum = 0
+			rates = append(rates, PeriodRate{
+				Start: period.Start(date),
+				End:   period.End(date),
+				High:  current,
+				Low:   current,
+			})
+			days := calendar.BusinessDays(cal, period.Start(date), period.End(date))
+			rates[len(rates)-1].Partial = days[0].Before(first) || days[len(days)-1].After(last)
+		}
+
+		p := &rates[len(rates)-1]
+		sum += current
+		p.Days++
+		if !fixed {
+			p.Filled++
+		}
+		p.Close, p.CloseDate = current, date
+		if current > p.High {
+			p.High = current
+		}
+		if current < p.Low {
+			p.Low = current
+		}
+	}
+	if len(rates) > 0 {
+		rates[len(rates)-1].Average = sum / float64(rates[len(rates)-1].Days)
+	}
+
+	return rates
+}
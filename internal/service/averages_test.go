@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/kaze/xrv/internal/calendar"
+	"github.com/kaze/xrv/internal/domain"
+)
+
+func TestService_CalculatePeriodAverages(t *testing.T) {
+	observations := map[time.Time]map[domain.Currency]float64{
+		day("2024-01-01"): {"USD": 1.00},
+		// 2 to 30 January carry the first fixing.
+		day("2024-01-31"): {"USD": 1.20},
+		// Saturday: left out of the average.
+		day("2024-02-03"): {"USD": 9.00},
+		day("2024-02-05"): {"USD": 1.30},
+		// No fixing on Tuesday 6 February: 1.30 is carried.
+		day("2024-02-07"): {"USD": 1.10},
+	}
+	data := buildTimeSeriesData("EUR", []domain.Currency{"USD"}, day("2024-01-01"), day("2024-02-10"), observations)
+
+	svc := NewService(nil, nil, WithCalendar(calendar.Weekends()))
+	got := svc.CalculatePeriodAverages(data, IntervalMonth)
+
+	periods := got.Rates["USD"]
+	if len(periods) != 2 {
+		t.Fatalf("periods = %+v, want January and February", periods)
+	}
+
+	jan := periods[0]
+	if !jan.Start.Equal(day("2024-01-01")) || !jan.End.Equal(day("2024-01-31")) || jan.Partial {
+		t.Errorf("January = %+v, want a complete month", jan)
+	}
+	if jan.Days != 23 || jan.Filled != 21 || math.Abs(jan.Average-(22*1.00+1.20)/23) > 1e-12 || jan.Close != 1.20 || jan.High != 1.20 || jan.Low != 1.00 {
+		t.Errorf("January = %+v", jan)
+	}
+
+	// Thursday 1 and Friday 2 February carry January's close.
+	feb := periods[1]
+	if !feb.Partial || feb.Days != 5 || feb.Filled != 3 {
+		t.Errorf("February = %+v, want 5 business days, 3 carried, partial at the last fixing", feb)
+	}
+	if math.Abs(feb.Average-1.22) > 1e-12 || feb.Close != 1.10 || !feb.CloseDate.Equal(day("2024-02-07")) {
+		t.Errorf("February average %v, close %v on %s", feb.Average, feb.Close, feb.CloseDate.Format("2006-01-02"))
+	}
+	if feb.High != 1.30 || feb.Low != 1.10 {
+		t.Errorf("February high/low = %v/%v, want the Saturday fixing left out", feb.High, feb.Low)
+	}
+}
+
+func TestService_PeriodAverages_RequiresPeriod(t *testing.T) {
+	svc := NewService(nil, nil)
+	for _, interval := range []Interval{"", IntervalDay} {
+		if _, err := svc.PeriodAverages(context.Background(), FetchOptions{Interval: interval}); err == nil {
+			t.Errorf("PeriodAverages(%q) should fail", interval)
+		}
+	}
+}
+
+func TestInterval_EndAndLabel(t *testing.T) {
+	tests := []struct {
+		interval  Interval
+		wantEnd   string
+		wantLabel string
+	}{
+		{IntervalDay, "2024-02-14", "2024-02-14"},
+		{IntervalWeek, "2024-02-18", "2024-W07"},
+		{IntervalMonth, "2024-02-29", "2024-02"},
+		{IntervalQuarter, "2024-03-31", "2024-Q1"},
+		{IntervalYear, "2024-12-31", "2024"},
+	}
+	for _, tt := range tests {
+		if got := tt.interval.End(day("2024-02-14")); got.Format("2006-01-02") != tt.wantEnd {
+			t.Errorf("%s.End() = %s, want %s", tt.interval, got.Format("2006-01-02"), tt.wantEnd)
+		}
+		if got := tt.interval.Label(day("2024-02-14")); got != tt.wantLabel {
+			t.Errorf("%s.Label() = %s, want %s", tt.interval, got, tt.wantLabel)
+		}
+	}
+}
@@ -0,0 +1,430 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kaze/xrv/internal/cache"
+	"github.com/kaze/xrv/internal/domain"
+	"github.com/kaze/xrv/internal/providers"
+)
+
+const rateKeyPrefix = "rate:"
+
+// missingTTL is how long a past business day without a fixing stays cached
+// as missing.
+const missingTTL = 7 * 24 * time.Hour
+
+type cachedRate struct {
+	Rate    float64 `json:"rate"`
+	Missing bool    `json:"missing,omitempty"`
+	Source  string  `json:"source,omitempty"`
+}
+
+type CacheEntryInfo struct {
+	Key       string
+	Base      domain.Currency
+	Target    domain.Currency
+	Date      time.Time
+	Rate      float64
+	Missing   bool
+	Source    string
+	Size      int64
+	ExpiresAt time.Time
+}
+
+type CacheSeriesInfo struct {
+	Base         domain.Currency
+	Target       domain.Currency
+	StartDate    time.Time
+	EndDate      time.Time
+	Observations int
+	Missing      int
+	Size         int64
+	ExpiresAt    time.Time
+}
+
+type CacheFilter struct {
+	Base   domain.Currency
+	Target domain.Currency
+	From   time.Time
+	To     time.Time
+}
+
+type CacheReport struct {
+	Checked int
+	Valid   int
+	Corrupt []string
+	Removed int
+}
+
+func rateCacheKey(base, target domain.Currency, date time.Time) string {
+	return fmt.Sprintf("%s%s:%s:%s", rateKeyPrefix, base, target, date.Format("2006-01-02"))
+}
+
+func (s *Service) loadCachedRates(ctx context.Context, base domain.Currency, targets []domain.Currency, start, end time.Time, observations map[time.Time]map[domain.Currency]float64, sources sourceSet) []dateRange {
+	var keys []string
+	for date := start; !date.After(end); date = date.AddDate(0, 0, 1) {
+		for _, target := range targets {
+			keys = append(keys, rateCacheKey(base, target, date))
+		}
+	}
+	values := s.getCached(ctx, keys)
+
+	var gaps []dateRange
+	var gap *dateRange
+
+	for date := start; !date.After(end); date = date.AddDate(0, 0, 1) {
+		complete := true
+
+		for _, target := range targets {
+			value, exists := values[rateCacheKey(base, target, date)]
+			if !exists {
+				complete = false
+				continue
+			}
+
+			var cached cachedRate
+			if err := json.Unmarshal(value, &cached); err != nil {
+				complete = false
+				continue
+			}
+
+			if cached.Missing {
+				continue
+			}
+
+			if observations[date] == nil {
+				observations[date] = make(map[domain.Currency]float64, len(targets))
+			}
+			observations[date][target] = cached.Rate
+			sources.add(target, cached.Source)
+		}
+
+		if complete {
+			gap = nil
+			continue
+		}
+
+		if gap == nil {
+			gaps = append(gaps, dateRange{start: date, end: date})
+			gap = &gaps[len(gaps)-1]
+		} else {
+			gap.end = date
+		}
+	}
+
+	return gaps
+}
+
+func (s *Service) storeRates(ctx context.Context, base domain.Currency, targets []domain.Currency, fetched dateRange, rates map[time.Time]map[domain.Currency]float64, source string) {
+	var items []cache.Item
+	add := func(target domain.Currency, date time.Time, entry cachedRate) {
+		value, err := json.Marshal(entry)
+		if err != nil {
+			return
+		}
+		items = append(items, cache.Item{Key: rateCacheKey(base, target, date), Value: value, TTL: s.entryTTL(date, entry)})
+	}
+
+	for date, dayRates := range rates {
+		if !date.Before(fetched.start) && !date.After(fetched.end) {
+			continue
+		}
+		for target, rate := range dayRates {
+			add(target, date, cachedRate{Rate: rate, Source: source})
+		}
+	}
+
+	for date := fetched.start; !date.After(fetched.end); date = date.AddDate(0, 0, 1) {
+		for _, target := range targets {
+			entry := cachedRate{Missing: true, Source: source}
+			if rate, exists := rates[date][target]; exists {
+				entry = cachedRate{Rate: rate, Source: source}
+			}
+			add(target, date, entry)
+		}
+	}
+
+	s.setCached(ctx, items)
+}
+
+// entryTTL keeps past fixings for good. A past business day without a fixing
+// expires after missingTTL, so that a day lost to an outage or backfilled by
+// the provider later is fetched again; weekends and holidays stay missing.
+func (s *Service) entryTTL(date time.Time, entry cachedRate) time.Duration {
+	ttl := s.calculateTTL(date)
+	if ttl == 0 && entry.Missing && s.calendar.IsBusinessDay(date) {
+		return missingTTL
+	}
+	return ttl
+}
+
+// getCached reads keys in one transaction when the cache supports batches;
+// keys that cannot be read are left out.
+func (s *Service) getCached(ctx context.Context, keys []string) map[string][]byte {
+	if batcher, ok := s.cache.(cache.Batcher); ok {
+		values, err := batcher.GetMany(ctx, keys)
+		if err != nil {
+			return nil
+		}
+		return values
+	}
+
+	values := make(map[string][]byte, len(keys))
+	for _, key := range keys {
+		if value, err := s.cache.Get(ctx, key); err == nil {
+			values[key] = value
+		}
+	}
+	return values
+}
+
+func (s *Service) setCached(ctx context.Context, items []cache.Item) {
+	if batcher, ok := s.cache.(cache.Batcher); ok {
+		batcher.SetMany(ctx, items)
+		return
+	}
+
+	for _, item := range items {
+		s.cache.Set(ctx, item.Key, item.Value, item.TTL)
+	}
+}
+
+func (f CacheFilter) IsEmpty() bool {
+	return f.Base == "" && f.Target == "" && f.From.IsZero() && f.To.IsZero()
+}
+
+func (f CacheFilter) Matches(info CacheEntryInfo) bool {
+	if f.Base != "" && info.Base != f.Base {
+		return false
+	}
+	if f.Target != "" && info.Target != f.Target {
+		return false
+	}
+	if !f.From.IsZero() && info.Date.Before(truncateDay(f.From)) {
+		return false
+	}
+	if !f.To.IsZero() && info.Date.After(truncateDay(f.To)) {
+		return false
+	}
+	return true
+}
+
+func (f CacheFilter) prefix() string {
+	if f.Base == "" {
+		return rateKeyPrefix
+	}
+	if f.Target == "" {
+		return fmt.Sprintf("%s%s:", rateKeyPrefix, f.Base)
+	}
+	return fmt.Sprintf("%s%s:%s:", rateKeyPrefix, f.Base, f.Target)
+}
+
+func DescribeCacheEntry(entry cache.Entry) (CacheEntryInfo, error) {
+	parts := strings.Split(entry.Key, ":")
+	if len(parts) != 4 || parts[0]+":" != rateKeyPrefix {
+		return CacheEntryInfo{}, fmt.Errorf("unrecognized cache key %s", entry.Key)
+	}
+
+	date, err := time.Parse("2006-01-02", parts[3])
+	if err != nil {
+		return CacheEntryInfo{}, fmt.Errorf("invalid date in cache key %s: %w", entry.Key, err)
+	}
+
+	var cached cachedRate
+	if err := json.Unmarshal(entry.Value, &cached); err != nil {
+		return CacheEntryInfo{}, fmt.Errorf("failed to decode cache entry %s: %w", entry.Key, err)
+	}
+
+	return CacheEntryInfo{
+		Key:       entry.Key,
+		Base:      domain.Currency(parts[1]),
+		Target:    domain.Currency(parts[2]),
+		Date:      date,
+		Rate:      cached.Rate,
+		Missing:   cached.Missing,
+		Source:    cached.Source,
+		Size:      entry.Size,
+		ExpiresAt: entry.ExpiresAt,
+	}, nil
+}
+
+func (s *Service) CacheEntries(ctx context.Context, filter CacheFilter) ([]CacheEntryInfo, error) {
+	inspector, err := s.cacheInspector()
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []CacheEntryInfo
+	err = inspector.Iterate(ctx, filter.prefix(), func(entry cache.Entry) error {
+		info, err := DescribeCacheEntry(entry)
+		if err != nil {
+			return nil
+		}
+		if filter.Matches(info) {
+			entries = append(entries, info)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cache entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+func (s *Service) CacheSeries(ctx context.Context, filter CacheFilter) ([]CacheSeriesInfo, error) {
+	entries, err := s.CacheEntries(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	type seriesKey struct {
+		base   domain.Currency
+		target domain.Currency
+	}
+
+	series := make(map[seriesKey]*CacheSeriesInfo)
+	for _, entry := range entries {
+		key := seriesKey{base: entry.Base, target: entry.Target}
+		info, exists := series[key]
+		if !exists {
+			info = &CacheSeriesInfo{
+				Base:      entry.Base,
+				Target:    entry.Target,
+				StartDate: entry.Date,
+				EndDate:   entry.Date,
+			}
+			series[key] = info
+		}
+
+		if entry.Date.Before(info.StartDate) {
+			info.StartDate = entry.Date
+		}
+		if entry.Date.After(info.EndDate) {
+			info.EndDate = entry.Date
+		}
+		if entry.Missing {
+			info.Missing++
+		} else {
+			info.Observations++
+		}
+		info.Size += entry.Size
+		if !entry.ExpiresAt.IsZero() && (info.ExpiresAt.IsZero() || entry.ExpiresAt.Before(info.ExpiresAt)) {
+			info.ExpiresAt = entry.ExpiresAt
+		}
+	}
+
+	result := make([]CacheSeriesInfo, 0, len(series))
+	for _, info := range series {
+		result = append(result, *info)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Base != result[j].Base {
+			return result[i].Base < result[j].Base
+		}
+		return result[i].Target < result[j].Target
+	})
+
+	return result, nil
+}
+
+func (s *Service) EvictCache(ctx context.Context, filter CacheFilter) (int, error) {
+	if filter.IsEmpty() {
+		stats, err := s.CacheStats(ctx)
+		if err != nil {
+			return 0, err
+		}
+		if err := s.cache.Clear(ctx); err != nil {
+			return 0, err
+		}
+		return stats.Entries, nil
+	}
+
+	entries, err := s.CacheEntries(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, entry := range entries {
+		if err := s.cache.Delete(ctx, entry.Key); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(entries), nil
+}
+
+func (s *Service) CacheStats(ctx context.Context) (cache.Stats, error) {
+	inspector, err := s.cacheInspector()
+	if err != nil {
+		return cache.Stats{}, err
+	}
+	return inspector.Stats(ctx)
+}
+
+func (s *Service) PurgeExpiredCache(ctx context.Context) (int, error) {
+	inspector, err := s.cacheInspector()
+	if err != nil {
+		return 0, err
+	}
+	return inspector.PurgeExpired(ctx)
+}
+
+func (s *Service) VerifyCache(ctx context.Context, repair bool) (CacheReport, error) {
+	inspector, err := s.cacheInspector()
+	if err != nil {
+		return CacheReport{}, err
+	}
+
+	if err := inspector.Verify(ctx); err != nil {
+		return CacheReport{}, err
+	}
+
+	var report CacheReport
+	err = inspector.Iterate(ctx, "", func(entry cache.Entry) error {
+		report.Checked++
+		if entry.Key == currenciesCacheKey {
+			var currencies providers.CurrenciesResponse
+			if err := json.Unmarshal(entry.Value, &currencies); err != nil {
+				report.Corrupt = append(report.Corrupt, entry.Key)
+				return nil
+			}
+			report.Valid++
+			return nil
+		}
+		if _, err := DescribeCacheEntry(entry); err != nil {
+			report.Corrupt = append(report.Corrupt, entry.Key)
+			return nil
+		}
+		report.Valid++
+		return nil
+	})
+	if err != nil {
+		return report, fmt.Errorf("failed to verify cache entries: %w", err)
+	}
+
+	if repair {
+		for _, key := range report.Corrupt {
+			if err := s.cache.Delete(ctx, key); err != nil {
+				return report, err
+			}
+			report.Removed++
+		}
+	}
+
+	return report, nil
+}
+
+func (s *Service) cacheInspector() (cache.Inspector, error) {
+	inspector, ok := s.cache.(cache.Inspector)
+	if !ok {
+		return nil, fmt.Errorf("cache does not support inspection")
+	}
+	return inspector, nil
+}
@@ -0,0 +1,375 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kaze/xrv/internal/cache"
+	"github.com/kaze/xrv/internal/domain"
+	"github.com/kaze/xrv/internal/providers"
+)
+
+type recordedRequest struct {
+	start time.Time
+	end   time.Time
+}
+
+type dailyAPIClient struct {
+	rates    map[string]map[string]float64
+	provider string
+	requests []recordedRequest
+}
+
+func (m *dailyAPIClient) GetTimeSeriesRates(ctx context.Context, startDate, endDate time.Time, base string, targets []string) (*providers.TimeSeriesResponse, error) {
+	m.requests = append(m.requests, recordedRequest{start: startDate, end: endDate})
+
+	resp := &providers.TimeSeriesResponse{
+		Base:      base,
+		StartDate: startDate.Format("2006-01-02"),
+		EndDate:   endDate.Format("2006-01-02"),
+		Rates:     make(map[string]map[string]float64),
+		Provider:  m.provider,
+	}
+	for date := startDate; !date.After(endDate); date = date.AddDate(0, 0, 1) {
+		key := date.Format("2006-01-02")
+		if dayRates, exists := m.rates[key]; exists {
+			resp.Rates[key] = make(map[string]float64)
+			for _, t := range targets {
+				if rate, ok := dayRates[t]; ok {
+					resp.Rates[key][t] = rate
+				}
+			}
+		}
+	}
+
+	return resp, nil
+}
+
+func (m *dailyAPIClient) GetSupportedCurrencies(ctx context.Context) (providers.CurrenciesResponse, error) {
+	return providers.CurrenciesResponse{}, nil
+}
+
+func day(s string) time.Time {
+	d, _ := time.Parse("2006-01-02", s)
+	return d
+}
+
+func seedCache(t *testing.T, svc *Service, base string, start, end string) {
+	t.Helper()
+	svc.apiClient = &mockAPIClient{
+		timeSeriesResponse: &providers.TimeSeriesResponse{
+			Base:      base,
+			StartDate: start,
+			EndDate:   end,
+			Rates: map[string]map[string]float64{
+				start: {"EUR": 0.85},
+				end:   {"EUR": 0.86},
+			},
+		},
+	}
+
+	_, err := svc.FetchTimeSeriesData(context.Background(), FetchOptions{
+		Base:      domain.Currency(base),
+		Targets:   []domain.Currency{"EUR"},
+		StartDate: day(start),
+		EndDate:   day(end),
+		UseCache:  true,
+	})
+	if err != nil {
+		t.Fatalf("FetchTimeSeriesData() error = %v", err)
+	}
+}
+
+func TestService_FetchTimeSeriesData_PartialCache(t *testing.T) {
+	api := &dailyAPIClient{rates: map[string]map[string]float64{
+		"2023-01-02": {"EUR": 0.90, "GBP": 0.80},
+		"2023-01-03": {"EUR": 0.91, "GBP": 0.81},
+		"2023-01-04": {"EUR": 0.92, "GBP": 0.82},
+		"2023-01-05": {"EUR": 0.93, "GBP": 0.83},
+	}}
+
+	memCache := cache.NewMemoryCache()
+	defer memCache.Close()
+
+	svc := NewService(api, memCache)
+	ctx := context.Background()
+
+	opts := FetchOptions{
+		Base:      "USD",
+		Targets:   []domain.Currency{"EUR", "GBP"},
+		StartDate: day("2023-01-01"),
+		EndDate:   day("2023-01-03"),
+		UseCache:  true,
+	}
+	if _, err := svc.FetchTimeSeriesData(ctx, opts); err != nil {
+		t.Fatalf("FetchTimeSeriesData() error = %v", err)
+	}
+
+	opts.EndDate = day("2023-01-05")
+	data, err := svc.FetchTimeSeriesData(ctx, opts)
+	if err != nil {
+		t.Fatalf("FetchTimeSeriesData() error = %v", err)
+	}
+
+	if len(api.requests) != 2 {
+		t.Fatalf("API requests = %d, want 2", len(api.requests))
+	}
+	second := api.requests[1]
+	if !second.start.Equal(day("2023-01-04")) || !second.end.Equal(day("2023-01-05")) {
+		t.Errorf("second request = %s..%s, want 2023-01-04..2023-01-05",
+			second.start.Format("2006-01-02"), second.end.Format("2006-01-02"))
+	}
+
+	if len(data.DataPoints) != 4 {
+		t.Fatalf("DataPoints = %d, want 4", len(data.DataPoints))
+	}
+	if data.DataPoints[0].Rates["EUR"] != 0.90 || data.DataPoints[3].Rates["GBP"] != 0.83 {
+		t.Errorf("unexpected merged rates: %+v", data.DataPoints)
+	}
+	if !data.StartDate.Equal(day("2023-01-02")) || !data.EndDate.Equal(day("2023-01-05")) {
+		t.Errorf("range = %s..%s, want 2023-01-02..2023-01-05",
+			data.StartDate.Format("2006-01-02"), data.EndDate.Format("2006-01-02"))
+	}
+
+	if _, err := svc.FetchTimeSeriesData(ctx, opts); err != nil {
+		t.Fatalf("FetchTimeSeriesData() error = %v", err)
+	}
+	if len(api.requests) != 2 {
+		t.Errorf("API requests after fully cached fetch = %d, want 2", len(api.requests))
+	}
+}
+
+func TestService_FetchTimeSeriesData_RecordsSources(t *testing.T) {
+	api := &dailyAPIClient{
+		provider: "primary",
+		rates: map[string]map[string]float64{
+			"2023-01-02": {"EUR": 0.90},
+			"2023-01-03": {"EUR": 0.91},
+		},
+	}
+
+	memCache := cache.NewMemoryCache()
+	defer memCache.Close()
+
+	svc := NewService(api, memCache)
+	ctx := context.Background()
+
+	opts := FetchOptions{
+		Base:      "USD",
+		Targets:   []domain.Currency{"EUR"},
+		StartDate: day("2023-01-02"),
+		EndDate:   day("2023-01-02"),
+		UseCache:  true,
+	}
+	if _, err := svc.FetchTimeSeriesData(ctx, opts); err != nil {
+		t.Fatalf("FetchTimeSeriesData() error = %v", err)
+	}
+
+	api.provider = "backup"
+	opts.EndDate = day("2023-01-03")
+	data, err := svc.FetchTimeSeriesData(ctx, opts)
+	if err != nil {
+		t.Fatalf("FetchTimeSeriesData() error = %v", err)
+	}
+
+	sources := data.Sources["EUR"]
+	if len(sources) != 2 || sources[0] != "backup" || sources[1] != "primary" {
+		t.Errorf("Sources[EUR] = %v, want [backup primary]", sources)
+	}
+
+	entries, err := svc.CacheEntries(ctx, CacheFilter{Base: "USD", Target: "EUR", From: day("2023-01-03")})
+	if err != nil {
+		t.Fatalf("CacheEntries() error = %v", err)
+	}
+	if len(entries) != 1 || entries[0].Source != "backup" {
+		t.Errorf("cached entry source = %+v, want backup", entries)
+	}
+}
+
+func TestService_FetchTimeSeriesData_NewTargetFetchesOnlyMissing(t *testing.T) {
+	api := &dailyAPIClient{rates: map[string]map[string]float64{
+		"2023-01-02": {"EUR": 0.90, "GBP": 0.80},
+		"2023-01-03": {"EUR": 0.91, "GBP": 0.81},
+	}}
+
+	memCache := cache.NewMemoryCache()
+	defer memCache.Close()
+
+	svc := NewService(api, memCache)
+	ctx := context.Background()
+
+	opts := FetchOptions{
+		Base:      "USD",
+		Targets:   []domain.Currency{"EUR"},
+		StartDate: day("2023-01-02"),
+		EndDate:   day("2023-01-03"),
+		UseCache:  true,
+	}
+	svc.FetchTimeSeriesData(ctx, opts)
+
+	opts.Targets = []domain.Currency{"EUR", "GBP"}
+	data, err := svc.FetchTimeSeriesData(ctx, opts)
+	if err != nil {
+		t.Fatalf("FetchTimeSeriesData() error = %v", err)
+	}
+
+	if len(api.requests) != 2 {
+		t.Errorf("API requests = %d, want 2", len(api.requests))
+	}
+	if data.DataPoints[1].Rates["GBP"] != 0.81 {
+		t.Errorf("GBP rate = %v, want 0.81", data.DataPoints[1].Rates["GBP"])
+	}
+}
+
+// countingCache counts single-key reads and writes of the cache it wraps.
+type countingCache struct {
+	*cache.MemoryCache
+	gets, sets int
+}
+
+func (c *countingCache) Get(ctx context.Context, key string) ([]byte, error) {
+	c.gets++
+	return c.MemoryCache.Get(ctx, key)
+}
+
+func (c *countingCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	c.sets++
+	return c.MemoryCache.Set(ctx, key, value, ttl)
+}
+
+func TestService_FetchTimeSeriesData_BatchesCacheAccess(t *testing.T) {
+	api := &dailyAPIClient{rates: map[string]map[string]float64{
+		"2023-01-02": {"EUR": 0.90, "GBP": 0.80},
+		"2023-01-04": {"EUR": 0.92, "GBP": 0.82},
+	}}
+	counting := &countingCache{MemoryCache: cache.NewMemoryCache()}
+	defer counting.Close()
+
+	svc := NewService(api, counting)
+	ctx := context.Background()
+
+	opts := FetchOptions{
+		Base:      "USD",
+		Targets:   []domain.Currency{"EUR", "GBP"},
+		StartDate: day("2022-12-31"),
+		EndDate:   day("2023-01-04"),
+		UseCache:  true,
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := svc.FetchTimeSeriesData(ctx, opts); err != nil {
+			t.Fatalf("FetchTimeSeriesData() error = %v", err)
+		}
+	}
+
+	if counting.gets != 0 || counting.sets != 0 {
+		t.Errorf("single-key reads = %d, writes = %d, want batches only", counting.gets, counting.sets)
+	}
+	if len(api.requests) != 1 {
+		t.Errorf("API requests = %d, want 1", len(api.requests))
+	}
+
+	entries, err := svc.CacheEntries(ctx, CacheFilter{Target: "EUR"})
+	if err != nil {
+		t.Fatalf("CacheEntries() error = %v", err)
+	}
+	expires := make(map[string]time.Time)
+	for _, entry := range entries {
+		expires[entry.Date.Format("2006-01-02")] = entry.ExpiresAt
+	}
+	if !expires["2023-01-02"].IsZero() || !expires["2022-12-31"].IsZero() {
+		t.Errorf("fixings and weekend markers should not expire: %v", expires)
+	}
+	if until := time.Until(expires["2023-01-03"]); until <= 0 || until > missingTTL {
+		t.Errorf("missing business day expires in %v, want within %v", until, missingTTL)
+	}
+}
+
+func TestService_CacheEntries(t *testing.T) {
+	memCache := cache.NewMemoryCache()
+	defer memCache.Close()
+
+	svc := NewService(&mockAPIClient{}, memCache)
+	seedCache(t, svc, "USD", "2023-01-01", "2023-01-03")
+	seedCache(t, svc, "GBP", "2023-02-01", "2023-02-02")
+
+	entries, err := svc.CacheEntries(context.Background(), CacheFilter{})
+	if err != nil {
+		t.Fatalf("CacheEntries() error = %v", err)
+	}
+	if len(entries) != 5 {
+		t.Fatalf("CacheEntries() = %d entries, want 5", len(entries))
+	}
+
+	series, err := svc.CacheSeries(context.Background(), CacheFilter{Base: "USD"})
+	if err != nil {
+		t.Fatalf("CacheSeries() error = %v", err)
+	}
+	if len(series) != 1 {
+		t.Fatalf("CacheSeries(USD) = %d series, want 1", len(series))
+	}
+	if series[0].Observations != 2 || series[0].Missing != 1 {
+		t.Errorf("series = %+v, want 2 observations and 1 missing", series[0])
+	}
+	if !series[0].StartDate.Equal(day("2023-01-01")) || !series[0].EndDate.Equal(day("2023-01-03")) {
+		t.Errorf("series range = %v..%v, want 2023-01-01..2023-01-03", series[0].StartDate, series[0].EndDate)
+	}
+}
+
+func TestService_EvictCache(t *testing.T) {
+	memCache := cache.NewMemoryCache()
+	defer memCache.Close()
+
+	svc := NewService(&mockAPIClient{}, memCache)
+	seedCache(t, svc, "USD", "2023-01-01", "2023-01-02")
+	seedCache(t, svc, "USD", "2023-06-01", "2023-06-02")
+	seedCache(t, svc, "GBP", "2023-01-01", "2023-01-02")
+
+	removed, err := svc.EvictCache(context.Background(), CacheFilter{
+		Base: "USD",
+		From: day("2023-05-01"),
+	})
+	if err != nil {
+		t.Fatalf("EvictCache() error = %v", err)
+	}
+	if removed != 2 {
+		t.Errorf("EvictCache() = %d, want 2", removed)
+	}
+
+	stats, _ := svc.CacheStats(context.Background())
+	if stats.Entries != 4 {
+		t.Errorf("Entries after evict = %d, want 4", stats.Entries)
+	}
+
+	removed, err = svc.EvictCache(context.Background(), CacheFilter{})
+	if err != nil {
+		t.Fatalf("EvictCache() error = %v", err)
+	}
+	if removed != 4 {
+		t.Errorf("EvictCache(all) = %d, want 4", removed)
+	}
+}
+
+func TestService_VerifyCache(t *testing.T) {
+	memCache := cache.NewMemoryCache()
+	defer memCache.Close()
+
+	svc := NewService(&mockAPIClient{}, memCache)
+	seedCache(t, svc, "USD", "2023-01-01", "2023-01-02")
+	memCache.Set(context.Background(), "rate:USD:EUR:2023-01-05", []byte("{not json"), 0)
+
+	report, err := svc.VerifyCache(context.Background(), true)
+	if err != nil {
+		t.Fatalf("VerifyCache() error = %v", err)
+	}
+
+	if report.Checked != 3 || report.Valid != 2 {
+		t.Errorf("report = %+v, want 3 checked and 2 valid", report)
+	}
+	if len(report.Corrupt) != 1 || report.Corrupt[0] != "rate:USD:EUR:2023-01-05" {
+		t.Errorf("Corrupt = %v, want [rate:USD:EUR:2023-01-05]", report.Corrupt)
+	}
+	if report.Removed != 1 {
+		t.Errorf("Removed = %d, want 1", report.Removed)
+	}
+}
@@ -0,0 +1,337 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kaze/xrv/internal/calendar"
+	"github.com/kaze/xrv/internal/domain"
+)
+
+const defaultLookbackDays = 7
+
+type ConvertOptions struct {
+	Amount       float64
+	From         domain.Currency
+	Targets      []domain.Currency
+	Date         time.Time
+	LookbackDays int
+	UseCache     bool
+}
+
+// Conversion is the amount in one target currency, or the reason it could not
+// be converted in Err.
+type Conversion struct {
+	Target   domain.Currency
+	Rate     float64
+	RateDate time.Time
+	Amount   float64
+	Fallback bool
+	Err      error
+}
+
+type ConversionResult struct {
+	Amount      float64
+	From        domain.Currency
+	Date        time.Time
+	Conversions []Conversion
+}
+
+func (s *Service) Convert(ctx context.Context, opts ConvertOptions) (*ConversionResult, error) {
+	if len(opts.Targets) == 0 {
+		return nil, fmt.Errorf("at least one target currency is required")
+	}
+
+	date := truncateDay(opts.Date)
+	lookback := opts.LookbackDays
+	if lookback <= 0 {
+		lookback = defaultLookbackDays
+	}
+
+	result := &ConversionResult{
+		Amount:      opts.Amount,
+		From:        opts.From,
+		Date:        date,
+		Conversions: make([]Conversion, 0, len(opts.Targets)),
+	}
+
+	var quoted []domain.Currency
+	for _, target := range opts.Targets {
+		if target != opts.From {
+			quoted = append(quoted, target)
+		}
+	}
+
+	fetch := func(targets []domain.Currency) (*domain.TimeSeriesData, error) {
+		return s.FetchTimeSeriesData(ctx, FetchOptions{
+			Base:      opts.From,
+			Targets:   targets,
+			StartDate: addBusinessDays(s.calendar, date, -lookback),
+			EndDate:   date,
+			UseCache:  opts.UseCache,
+		})
+	}
+
+	series := make(map[domain.Currency]*domain.TimeSeriesData, len(quoted))
+	fetchErrs := make(map[domain.Currency]error)
+	if len(quoted) > 0 {
+		data, err := fetch(quoted)
+		switch {
+		case err == nil:
+			for _, target := range quoted {
+				series[target] = data
+			}
+		case len(quoted) == 1:
+			fetchErrs[quoted[0]] = err
+		default:
+			// A single unknown currency fails the whole request, so the
+			// targets are fetched one by one to keep the others.
+			for _, target := range quoted {
+				if data, err := fetch([]domain.Currency{target}); err != nil {
+					fetchErrs[target] = err
+				} else {
+					series[target] = data
+				}
+			}
+		}
+	}
+
+	for _, target := range opts.Targets {
+		if target == opts.From {
+			result.Conversions = append(result.Conversions, Conversion{
+				Target:   target,
+				Rate:     1,
+				RateDate: date,
+				Amount:   opts.Amount,
+			})
+			continue
+		}
+
+		if err, failed := fetchErrs[target]; failed {
+			result.Conversions = append(result.Conversions, Conversion{
+				Target: target,
+				Err:    fmt.Errorf("failed to fetch %s/%s rates: %w", opts.From, target, err),
+			})
+			continue
+		}
+
+		rate, rateDate, ok := findRate(s.calendar, series[target], target, date, RatePrevious, lookback)
+		if !ok {
+			result.Conversions = append(result.Conversions, Conversion{
+				Target: target,
+				Err: fmt.Errorf("no %s/%s fixing on %s or in the %d business days before",
+					opts.From, target, date.Format("2006-01-02"), lookback),
+			})
+			continue
+		}
+
+		result.Conversions = append(result.Conversions, Conversion{
+			Target:   target,
+			Rate:     rate,
+			RateDate: rateDate,
+			Amount:   opts.Amount * rate,
+			Fallback: !rateDate.Equal(date),
+		})
+	}
+
+	return result, nil
+}
+
+type RateRule string
+
+const (
+	RatePrevious RateRule = "previous"
+	RateNext     RateRule = "next"
+	RateExact    RateRule = "exact"
+)
+
+func ParseRateRule(value string) (RateRule, error) {
+	switch rule := RateRule(strings.ToLower(strings.TrimSpace(value))); rule {
+	case "":
+		return RatePrevious, nil
+	case RatePrevious, RateNext, RateExact:
+		return rule, nil
+	default:
+		return "", fmt.Errorf("unknown rate rule: %s (use 'previous', 'next' or 'exact')", value)
+	}
+}
+
+type Transaction struct {
+	Date     time.Time
+	Amount   float64
+	Currency domain.Currency
+}
+
+type ConvertedTransaction struct {
+	Transaction
+	Rate      float64
+	RateDate  time.Time
+	Converted float64
+	Err       error
+}
+
+type BatchConvertOptions struct {
+	To           domain.Currency
+	Rule         RateRule
+	LookbackDays int
+	UseCache     bool
+}
+
+func (s *Service) ConvertTransactions(ctx context.Context, transactions []Transaction, opts BatchConvertOptions) ([]ConvertedTransaction, error) {
+	rule := opts.Rule
+	if rule == "" {
+		rule = RatePrevious
+	}
+	lookback := opts.LookbackDays
+	if lookback <= 0 {
+		lookback = defaultLookbackDays
+	}
+
+	type dateSpan struct {
+		first time.Time
+		last  time.Time
+	}
+
+	spans := make(map[domain.Currency]*dateSpan)
+	var currencies []domain.Currency
+	for _, tx := range transactions {
+		if tx.Currency == opts.To {
+			continue
+		}
+		date := truncateDay(tx.Date)
+		span, exists := spans[tx.Currency]
+		if !exists {
+			spans[tx.Currency] = &dateSpan{first: date, last: date}
+			currencies = append(currencies, tx.Currency)
+			continue
+		}
+		if date.Before(span.first) {
+			span.first = date
+		}
+		if date.After(span.last) {
+			span.last = date
+		}
+	}
+
+	today := truncateDay(time.Now())
+	series := make(map[domain.Currency]*domain.TimeSeriesData, len(spans))
+	fetchErrs := make(map[domain.Currency]error)
+	for _, currency := range currencies {
+		span := spans[currency]
+		start, end := span.first, span.last
+		switch rule {
+		case RatePrevious:
+			start = addBusinessDays(s.calendar, start, -lookback)
+		case RateNext:
+			end = addBusinessDays(s.calendar, end, lookback)
+			if end.After(today) {
+				end = today
+			}
+		}
+
+		data, err := s.FetchTimeSeriesData(ctx, FetchOptions{
+			Base:      currency,
+			Targets:   []domain.Currency{opts.To},
+			StartDate: start,
+			EndDate:   end,
+			UseCache:  opts.UseCache,
+		})
+		if err != nil {
+			// The other currencies still convert; the rows of this one carry
+			// the error.
+			fetchErrs[currency] = fmt.Errorf("failed to fetch %s/%s rates: %w", currency, opts.To, err)
+			continue
+		}
+		series[currency] = data
+	}
+
+	converted := make([]ConvertedTransaction, len(transactions))
+	for i, tx := range transactions {
+		date := truncateDay(tx.Date)
+		converted[i] = ConvertedTransaction{Transaction: tx}
+
+		if tx.Currency == opts.To {
+			converted[i].Rate = 1
+			converted[i].RateDate = date
+			converted[i].Converted = tx.Amount
+			continue
+		}
+
+		if err, failed := fetchErrs[tx.Currency]; failed {
+			converted[i].Err = err
+			continue
+		}
+
+		rate, rateDate, ok := findRate(s.calendar, series[tx.Currency], opts.To, date, rule, lookback)
+		if !ok {
+			converted[i].Err = fmt.Errorf("no %s/%s fixing for %s (rule %s, lookback %d business days)",
+				tx.Currency, opts.To, date.Format("2006-01-02"), rule, lookback)
+			continue
+		}
+
+		converted[i].Rate = rate
+		converted[i].RateDate = rateDate
+		converted[i].Converted = tx.Amount * rate
+	}
+
+	return converted, nil
+}
+
+// findRate looks for the fixing of target on date, or under the previous and
+// next rules within lookback business days of it.
+func findRate(cal calendar.Calendar, data *domain.TimeSeriesData, target domain.Currency, date time.Time, rule RateRule, lookback int) (float64, time.Time, bool) {
+	if data == nil {
+		return 0, time.Time{}, false
+	}
+
+	switch rule {
+	case RateNext:
+		limit := addBusinessDays(cal, date, lookback)
+		for _, dp := range data.DataPoints {
+			if dp.Date.Before(date) || dp.Date.After(limit) {
+				continue
+			}
+			if rate, exists := dp.Rates[target]; exists {
+				return rate, dp.Date, true
+			}
+		}
+	case RateExact:
+		for _, dp := range data.DataPoints {
+			if dp.Date.Equal(date) {
+				rate, exists := dp.Rates[target]
+				return rate, dp.Date, exists
+			}
+		}
+	default:
+		limit := addBusinessDays(cal, date, -lookback)
+		for i := len(data.DataPoints) - 1; i >= 0; i-- {
+			dp := data.DataPoints[i]
+			if dp.Date.After(date) || dp.Date.Before(limit) {
+				continue
+			}
+			if rate, exists := dp.Rates[target]; exists {
+				return rate, dp.Date, true
+			}
+		}
+	}
+
+	return 0, time.Time{}, false
+}
+
+// addBusinessDays moves date by n business days of cal, backwards when n is
+// negative.
+func addBusinessDays(cal calendar.Calendar, date time.Time, n int) time.Time {
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+	for n > 0 {
+		date = date.AddDate(0, 0, step)
+		if cal.IsBusinessDay(date) {
+			n--
+		}
+	}
+	return date
+}
@@ -0,0 +1,272 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kaze/xrv/internal/cache"
+	"github.com/kaze/xrv/internal/domain"
+	"github.com/kaze/xrv/internal/providers"
+)
+
+func TestService_Convert(t *testing.T) {
+	api := &dailyAPIClient{rates: map[string]map[string]float64{
+		"2023-03-15": {"HUF": 380.25, "EUR": 0.94},
+		"2023-03-17": {"HUF": 376.10, "EUR": 0.93},
+	}}
+
+	memCache := cache.NewMemoryCache()
+	defer memCache.Close()
+
+	svc := NewService(api, memCache)
+	ctx := context.Background()
+
+	result, err := svc.Convert(ctx, ConvertOptions{
+		Amount:   12430.50,
+		From:     "USD",
+		Targets:  []domain.Currency{"HUF", "USD"},
+		Date:     day("2023-03-15"),
+		UseCache: true,
+	})
+	if err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+
+	if len(result.Conversions) != 2 {
+		t.Fatalf("Conversions = %d, want 2", len(result.Conversions))
+	}
+	huf := result.Conversions[0]
+	if huf.Fallback || !huf.RateDate.Equal(day("2023-03-15")) {
+		t.Errorf("HUF conversion should use the same-day fixing: %+v", huf)
+	}
+	if math.Abs(huf.Amount-12430.50*380.25) > 1e-6 {
+		t.Errorf("HUF amount = %v, want %v", huf.Amount, 12430.50*380.25)
+	}
+	if usd := result.Conversions[1]; usd.Rate != 1 || usd.Amount != 12430.50 {
+		t.Errorf("same-currency conversion = %+v, want identity", usd)
+	}
+
+	weekend, err := svc.Convert(ctx, ConvertOptions{
+		Amount:   100,
+		From:     "USD",
+		Targets:  []domain.Currency{"EUR"},
+		Date:     day("2023-03-19"),
+		UseCache: true,
+	})
+	if err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+	eur := weekend.Conversions[0]
+	if !eur.Fallback || !eur.RateDate.Equal(day("2023-03-17")) || eur.Rate != 0.93 {
+		t.Errorf("weekend conversion = %+v, want fallback to 2023-03-17", eur)
+	}
+}
+
+func TestService_Convert_NoFixingInLookback(t *testing.T) {
+	api := &dailyAPIClient{rates: map[string]map[string]float64{
+		"2023-03-01": {"EUR": 0.94},
+	}}
+
+	svc := NewService(api, cache.NewMemoryCache())
+
+	result, err := svc.Convert(context.Background(), ConvertOptions{
+		Amount:       100,
+		From:         "USD",
+		Targets:      []domain.Currency{"EUR"},
+		Date:         day("2023-03-15"),
+		LookbackDays: 3,
+	})
+	if err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+	if result.Conversions[0].Err == nil {
+		t.Error("expected an error when no fixing exists within the lookback window")
+	}
+}
+
+func TestService_Convert_PartialFailure(t *testing.T) {
+	api := &failingClient{
+		dailyAPIClient: dailyAPIClient{rates: map[string]map[string]float64{
+			"2023-03-15": {"EUR": 0.94, "HUF": 380.25},
+		}},
+		fail: "XXX",
+	}
+
+	svc := NewService(api, cache.NewMemoryCache())
+
+	result, err := svc.Convert(context.Background(), ConvertOptions{
+		Amount:       100,
+		From:         "USD",
+		Targets:      []domain.Currency{"EUR", "XXX", "GBP", "HUF"},
+		Date:         day("2023-03-15"),
+		LookbackDays: 3,
+	})
+	if err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+
+	if len(result.Conversions) != 4 {
+		t.Fatalf("Conversions = %d, want one per target", len(result.Conversions))
+	}
+	for _, i := range []int{0, 3} {
+		if c := result.Conversions[i]; c.Err != nil || c.Amount == 0 {
+			t.Errorf("%s conversion = %+v, want it kept", c.Target, c)
+		}
+	}
+	if c := result.Conversions[1]; c.Err == nil || !strings.Contains(c.Err.Error(), "failed to fetch USD/XXX") {
+		t.Errorf("XXX error = %v, want the fetch error", c.Err)
+	}
+	if c := result.Conversions[2]; c.Err == nil || !strings.Contains(c.Err.Error(), "no USD/GBP fixing") {
+		t.Errorf("GBP error = %v, want no fixing in the lookback", c.Err)
+	}
+}
+
+func TestService_ConvertTransactions(t *testing.T) {
+	api := &dailyAPIClient{rates: map[string]map[string]float64{
+		"2023-03-10": {"EUR": 0.94},
+		"2023-03-13": {"EUR": 0.93},
+		"2023-03-14": {"EUR": 0.92},
+	}}
+
+	svc := NewService(api, cache.NewMemoryCache())
+
+	transactions := []Transaction{
+		{Date: day("2023-03-13"), Amount: 100, Currency: "USD"},
+		{Date: day("2023-03-11"), Amount: 200, Currency: "USD"},
+		{Date: day("2023-03-14"), Amount: 50, Currency: "EUR"},
+		{Date: day("2023-03-12"), Amount: 10, Currency: "USD"},
+	}
+
+	tests := []struct {
+		rule      RateRule
+		wantDates []string
+		wantErr   []bool
+	}{
+		{RatePrevious, []string{"2023-03-13", "2023-03-10", "2023-03-14", "2023-03-10"}, []bool{false, false, false, false}},
+		{RateNext, []string{"2023-03-13", "2023-03-13", "2023-03-14", "2023-03-13"}, []bool{false, false, false, false}},
+		{RateExact, []string{"2023-03-13", "", "2023-03-14", ""}, []bool{false, true, false, true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.rule), func(t *testing.T) {
+			api.requests = nil
+
+			converted, err := svc.ConvertTransactions(context.Background(), transactions, BatchConvertOptions{
+				To:   "EUR",
+				Rule: tt.rule,
+			})
+			if err != nil {
+				t.Fatalf("ConvertTransactions() error = %v", err)
+			}
+
+			if len(api.requests) != 1 {
+				t.Errorf("API requests = %d, want 1 for a single foreign currency", len(api.requests))
+			}
+
+			for i, c := range converted {
+				if (c.Err != nil) != tt.wantErr[i] {
+					t.Errorf("row %d error = %v, want error: %v", i, c.Err, tt.wantErr[i])
+					continue
+				}
+				if c.Err == nil && c.RateDate.Format("2006-01-02") != tt.wantDates[i] {
+					t.Errorf("row %d rate date = %s, want %s", i, c.RateDate.Format("2006-01-02"), tt.wantDates[i])
+				}
+			}
+		})
+	}
+
+	converted, _ := svc.ConvertTransactions(context.Background(), transactions[:1], BatchConvertOptions{To: "EUR"})
+	if math.Abs(converted[0].Converted-93) > 1e-9 {
+		t.Errorf("Converted = %v, want 93", converted[0].Converted)
+	}
+}
+
+// failingClient fails every request that involves the fail currency.
+type failingClient struct {
+	dailyAPIClient
+	fail string
+}
+
+func (m *failingClient) GetTimeSeriesRates(ctx context.Context, startDate, endDate time.Time, base string, targets []string) (*providers.TimeSeriesResponse, error) {
+	if base == m.fail || slices.Contains(targets, m.fail) {
+		return nil, fmt.Errorf("currency not found: %s", m.fail)
+	}
+	return m.dailyAPIClient.GetTimeSeriesRates(ctx, startDate, endDate, base, targets)
+}
+
+func TestService_ConvertTransactions_FetchError(t *testing.T) {
+	api := &failingClient{
+		dailyAPIClient: dailyAPIClient{rates: map[string]map[string]float64{
+			"2023-03-13": {"EUR": 0.93},
+		}},
+		fail: "XXX",
+	}
+
+	svc := NewService(api, cache.NewMemoryCache())
+
+	transactions := []Transaction{
+		{Date: day("2023-03-13"), Amount: 100, Currency: "USD"},
+		{Date: day("2023-03-13"), Amount: 5, Currency: "XXX"},
+		{Date: day("2023-03-13"), Amount: 50, Currency: "EUR"},
+		{Date: day("2023-03-13"), Amount: 7, Currency: "XXX"},
+	}
+
+	converted, err := svc.ConvertTransactions(context.Background(), transactions, BatchConvertOptions{To: "EUR"})
+	if err != nil {
+		t.Fatalf("ConvertTransactions() error = %v", err)
+	}
+
+	for _, i := range []int{1, 3} {
+		if converted[i].Err == nil || !strings.Contains(converted[i].Err.Error(), "XXX/EUR") {
+			t.Errorf("row %d error = %v, want the XXX/EUR fetch error", i, converted[i].Err)
+		}
+	}
+	if converted[0].Err != nil || math.Abs(converted[0].Converted-93) > 1e-9 {
+		t.Errorf("USD row = %+v, want 93 EUR", converted[0])
+	}
+	if converted[2].Err != nil || converted[2].Converted != 50 {
+		t.Errorf("EUR row = %+v, want 50 EUR", converted[2])
+	}
+}
+
+func TestService_Convert_LookbackInBusinessDays(t *testing.T) {
+	// Good Friday and Easter Monday close TARGET2, so the Thursday before
+	// is one business day back from Tuesday 2023-04-11 but five calendar
+	// days.
+	api := &dailyAPIClient{rates: map[string]map[string]float64{
+		"2023-04-06": {"EUR": 0.92},
+	}}
+
+	svc := NewService(api, cache.NewMemoryCache())
+
+	result, err := svc.Convert(context.Background(), ConvertOptions{
+		Amount:       100,
+		From:         "USD",
+		Targets:      []domain.Currency{"EUR"},
+		Date:         day("2023-04-11"),
+		LookbackDays: 1,
+	})
+	if err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+	if eur := result.Conversions[0]; !eur.RateDate.Equal(day("2023-04-06")) {
+		t.Errorf("rate date = %s, want 2023-04-06", eur.RateDate.Format("2006-01-02"))
+	}
+}
+
+func TestParseRateRule(t *testing.T) {
+	if rule, err := ParseRateRule(" Next "); err != nil || rule != RateNext {
+		t.Errorf("ParseRateRule(Next) = %v, %v", rule, err)
+	}
+	if rule, err := ParseRateRule(""); err != nil || rule != RatePrevious {
+		t.Errorf("ParseRateRule(\"\") = %v, %v, want previous", rule, err)
+	}
+	if _, err := ParseRateRule("closest"); err == nil {
+		t.Error("ParseRateRule(closest) should fail")
+	}
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"time"
+
+	"github.com/kaze/xrv/internal/domain"
+	"github.com/kaze/xrv/internal/statistics"
+)
+
+// CalculateCorrelations correlates the log returns of the targets over the
+// dates on which every target has a rate, with rolling correlations over the
+// configured window. Dates missing a rate for any target are skipped so that
+// all pairs share one set of returns.
+func (s *Service) CalculateCorrelations(data *domain.TimeSeriesData) statistics.Correlations {
+	return Correlate(data, s.corrWindow)
+}
+
+// Correlate is CalculateCorrelations with an explicit rolling window; a
+// window below two skips the rolling correlations.
+func Correlate(data *domain.TimeSeriesData, window int) statistics.Correlations {
+	currencies := make([]string, len(data.Targets))
+	for i, target := range data.Targets {
+		currencies[i] = string(target)
+	}
+
+	var dates []time.Time
+	rates := make([][]float64, len(data.Targets))
+	for _, dp := range data.DataPoints {
+		if !hasAllRates(dp, data.Targets) {
+			continue
+		}
+		dates = append(dates, dp.Date)
+		for i, target := range data.Targets {
+			rates[i] = append(rates[i], dp.Rates[target])
+		}
+	}
+
+	return statistics.CalculateCorrelations(currencies, dates, rates, window)
+}
+
+func hasAllRates(dp domain.DataPoint, targets []domain.Currency) bool {
+	for _, target := range targets {
+		if _, exists := dp.Rates[target]; !exists {
+			return false
+		}
+	}
+	return true
+}
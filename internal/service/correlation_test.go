@@ -0,0 +1,34 @@
+package service
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/kaze/xrv/internal/domain"
+)
+
+func TestService_CalculateCorrelations_AlignsDates(t *testing.T) {
+	observations := map[time.Time]map[domain.Currency]float64{
+		day("2024-01-01"): {"USD": 1.00, "GBP": 0.80},
+		day("2024-01-02"): {"USD": 1.02, "GBP": 0.82},
+		// No GBP fixing: the date is left out for every pair.
+		day("2024-01-03"): {"USD": 5.00},
+		day("2024-01-04"): {"USD": 1.01, "GBP": 0.81},
+		day("2024-01-05"): {"USD": 1.04, "GBP": 0.84},
+	}
+	data := buildTimeSeriesData("EUR", []domain.Currency{"USD", "GBP"}, time.Time{}, time.Time{}, observations)
+
+	svc := NewService(nil, nil, WithCorrelationWindow(2))
+	got := svc.CalculateCorrelations(data)
+
+	if len(got.Dates) != 3 || !got.Dates[1].Equal(day("2024-01-04")) {
+		t.Fatalf("Dates = %v, want the returns of the 4 aligned dates", got.Dates)
+	}
+	if got.Matrix[0][1] < 0.9 {
+		t.Errorf("USD/GBP = %v, want a strong positive correlation", got.Matrix[0][1])
+	}
+	if got.Window != 2 || len(got.Rolling) != 1 || len(got.Rolling[0].Values) != 3 || math.IsNaN(got.Rolling[0].Values[1]) {
+		t.Errorf("Rolling = %+v, want one pair with a 2-return window", got.Rolling)
+	}
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kaze/xrv/internal/calendar"
+	"github.com/kaze/xrv/internal/domain"
+)
+
+type GapPolicy string
+
+const (
+	GapOmit        GapPolicy = "omit"
+	GapForwardFill GapPolicy = "ffill"
+	GapInterpolate GapPolicy = "interpolate"
+	GapNull        GapPolicy = "null"
+)
+
+func ParseGapPolicy(value string) (GapPolicy, error) {
+	switch policy := GapPolicy(strings.ToLower(strings.TrimSpace(value))); policy {
+	case "":
+		return GapOmit, nil
+	case GapOmit, GapForwardFill, GapInterpolate, GapNull:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unknown gap policy: %s (use 'omit', 'ffill', 'interpolate' or 'null')", value)
+	}
+}
+
+// FillGaps lays the series out on every business day of cal between its first
+// and last observation. Missing rates stay absent under GapNull, repeat the
+// previous fixing under GapForwardFill (also after the last one), and are
+// interpolated linearly in time between the surrounding fixings under
+// GapInterpolate. GapOmit keeps only the days that have a fixing.
+func FillGaps(data *domain.TimeSeriesData, cal calendar.Calendar, policy GapPolicy) *domain.TimeSeriesData {
+	if policy == "" || policy == GapOmit || len(data.DataPoints) == 0 {
+		return data
+	}
+
+	observed := make(map[time.Time]map[domain.Currency]float64, len(data.DataPoints))
+	for _, dp := range data.DataPoints {
+		observed[dp.Date] = dp.Rates
+	}
+
+	first := data.DataPoints[0].Date
+	last := data.DataPoints[len(data.DataPoints)-1].Date
+
+	var dates []time.Time
+	for date := first; !date.After(last); date = date.AddDate(0, 0, 1) {
+		if _, exists := observed[date]; exists || cal.IsBusinessDay(date) {
+			dates = append(dates, date)
+		}
+	}
+
+	points := make([]domain.DataPoint, len(dates))
+	for i, date := range dates {
+		points[i] = domain.DataPoint{Date: date, Rates: make(map[domain.Currency]float64, len(data.Targets))}
+		for currency, rate := range observed[date] {
+			points[i].Rates[currency] = rate
+		}
+	}
+
+	for _, target := range data.Targets {
+		previous := -1
+		for i := range points {
+			if _, exists := points[i].Rates[target]; !exists {
+				continue
+			}
+			if previous >= 0 && i-previous > 1 {
+				fillBetween(points, target, previous, i, policy)
+			}
+			previous = i
+		}
+		if policy == GapForwardFill && previous >= 0 {
+			for i := previous + 1; i < len(points); i++ {
+				points[i].Rates[target] = points[previous].Rates[target]
+			}
+		}
+	}
+
+	filled := *data
+	filled.DataPoints = points
+	return &filled
+}
+
+func fillBetween(points []domain.DataPoint, target domain.Currency, from, to int, policy GapPolicy) {
+	start, end := points[from].Rates[target], points[to].Rates[target]
+	span := points[to].Date.Sub(points[from].Date).Hours()
+
+	for i := from + 1; i < to; i++ {
+		switch policy {
+		case GapForwardFill:
+			points[i].Rates[target] = start
+		case GapInterpolate:
+			elapsed := points[i].Date.Sub(points[from].Date).Hours()
+			points[i].Rates[target] = start + (end-start)*elapsed/span
+		}
+	}
+}
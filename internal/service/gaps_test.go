@@ -0,0 +1,64 @@
+package service
+
+import (
+	"math"
+	"testing"
+
+	"github.com/kaze/xrv/internal/calendar"
+)
+
+func TestFillGaps(t *testing.T) {
+	// Friday, then the following Tuesday; Monday 2024-01-08 is a business day
+	// without a fixing.
+	data := dailySeries(map[string]float64{
+		"2024-01-05": 1.00,
+		"2024-01-09": 1.04,
+	})
+
+	tests := []struct {
+		policy GapPolicy
+		points int
+		monday float64
+	}{
+		{GapOmit, 2, math.NaN()},
+		{GapNull, 3, math.NaN()},
+		{GapForwardFill, 3, 1.00},
+		{GapInterpolate, 3, 1.03},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			filled := FillGaps(data, calendar.TARGET2(), tt.policy)
+			if len(filled.DataPoints) != tt.points {
+				t.Fatalf("DataPoints = %d, want %d", len(filled.DataPoints), tt.points)
+			}
+			if tt.points == 2 {
+				return
+			}
+
+			monday := filled.DataPoints[1]
+			if !monday.Date.Equal(day("2024-01-08")) {
+				t.Fatalf("second point dated %s, want 2024-01-08", monday.Date.Format("2006-01-02"))
+			}
+			rate, exists := monday.Rates["USD"]
+			if math.IsNaN(tt.monday) {
+				if exists {
+					t.Errorf("Monday rate = %v, want none", rate)
+				}
+				return
+			}
+			if !exists || math.Abs(rate-tt.monday) > 1e-12 {
+				t.Errorf("Monday rate = %v (%v), want %v", rate, exists, tt.monday)
+			}
+		})
+	}
+}
+
+func TestParseGapPolicy(t *testing.T) {
+	if got, err := ParseGapPolicy(""); err != nil || got != GapOmit {
+		t.Errorf("ParseGapPolicy(\"\") = %v, %v, want omit", got, err)
+	}
+	if _, err := ParseGapPolicy("zero"); err == nil {
+		t.Error("ParseGapPolicy(zero) should fail")
+	}
+}
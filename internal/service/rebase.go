@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/kaze/xrv/internal/domain"
+	"github.com/kaze/xrv/internal/providers"
+)
+
+// currenciesCacheKey holds the provider's currency list for a day, so that
+// rebasing onto cached days needs no request at all.
+const (
+	currenciesCacheKey = "currencies"
+	currenciesTTL      = 24 * time.Hour
+)
+
+func (s *Service) fetchRebased(ctx context.Context, opts FetchOptions, start, end time.Time) (*domain.TimeSeriesData, error) {
+	matrix, err := s.fetchDirect(ctx, FetchOptions{
+		Base:      s.pivot,
+		Targets:   s.legs(opts),
+		StartDate: start,
+		EndDate:   end,
+		UseCache:  opts.UseCache,
+	}, s.matrixFill(ctx, opts), start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	return Rebase(matrix, opts.Base, opts.Targets), nil
+}
+
+// matrixFill lists the currencies the provider quotes against the pivot
+// besides the requested legs. They are fetched along with any missing days,
+// so that the cached matrix fills up to serve later bases, but the legs alone
+// decide whether a day is cached. Nothing is added without the cache, whose
+// matrix nothing would reuse, or when the currency list is unavailable (e.g.
+// offline).
+func (s *Service) matrixFill(ctx context.Context, opts FetchOptions) []domain.Currency {
+	if !opts.UseCache {
+		return nil
+	}
+
+	supported, err := s.supportedCurrencies(ctx)
+	if err != nil || len(supported) == 0 {
+		return nil
+	}
+
+	legs := s.legs(opts)
+
+	seen := make(map[domain.Currency]bool, len(supported)+len(legs))
+	for _, leg := range legs {
+		seen[leg] = true
+	}
+
+	var extra []domain.Currency
+	for code := range supported {
+		currency := domain.Currency(code)
+		if currency == s.pivot || seen[currency] {
+			continue
+		}
+		seen[currency] = true
+		extra = append(extra, currency)
+	}
+	sort.Slice(extra, func(i, j int) bool { return extra[i] < extra[j] })
+
+	return extra
+}
+
+// supportedCurrencies returns the provider's currency list from the cache,
+// fetching and caching it when it has expired.
+func (s *Service) supportedCurrencies(ctx context.Context) (providers.CurrenciesResponse, error) {
+	if value, err := s.cache.Get(ctx, currenciesCacheKey); err == nil {
+		var cached providers.CurrenciesResponse
+		if err := json.Unmarshal(value, &cached); err == nil && len(cached) > 0 {
+			return cached, nil
+		}
+	}
+
+	supported, err := s.apiClient.GetSupportedCurrencies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if value, err := json.Marshal(supported); err == nil && len(supported) > 0 {
+		s.cache.Set(ctx, currenciesCacheKey, value, currenciesTTL)
+	}
+	return supported, nil
+}
+
+// Rebase derives base/target rates from a series quoted against a single
+// reference currency. Days on which the reference does not quote the base
+// are dropped; targets other than the reference itself are marked as derived.
+func Rebase(reference *domain.TimeSeriesData, base domain.Currency, targets []domain.Currency) *domain.TimeSeriesData {
+	pivot := reference.Base
+	observations := make(map[time.Time]map[domain.Currency]float64, len(reference.DataPoints))
+
+	for _, dp := range reference.DataPoints {
+		pivotToBase := 1.0
+		if base != pivot {
+			rate, ok := dp.Rates[base]
+			if !ok || rate == 0 {
+				continue
+			}
+			pivotToBase = rate
+		}
+
+		rates := make(map[domain.Currency]float64, len(targets))
+		for _, target := range targets {
+			switch target {
+			case base:
+				rates[target] = 1
+			case pivot:
+				rates[target] = 1 / pivotToBase
+			default:
+				if pivotToTarget, ok := dp.Rates[target]; ok {
+					rates[target] = pivotToTarget / pivotToBase
+				}
+			}
+		}
+		observations[dp.Date] = rates
+	}
+
+	data := buildTimeSeriesData(base, targets, reference.StartDate, reference.EndDate, observations)
+
+	sources := make(sourceSet)
+	for _, target := range targets {
+		if base != pivot && target != pivot && target != base {
+			if data.DerivedVia == nil {
+				data.DerivedVia = make(map[domain.Currency]domain.Currency, len(targets))
+			}
+			data.DerivedVia[target] = pivot
+		}
+		for _, leg := range []domain.Currency{base, target} {
+			for _, name := range reference.Sources[leg] {
+				sources.add(target, name)
+			}
+		}
+	}
+	data.Sources = sources.sorted()
+
+	return data
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/kaze/xrv/internal/cache"
+	"github.com/kaze/xrv/internal/domain"
+	"github.com/kaze/xrv/internal/providers"
+)
+
+type currenciesClient struct {
+	dailyAPIClient
+	currencies      providers.CurrenciesResponse
+	currencyLookups int
+}
+
+func (m *currenciesClient) GetSupportedCurrencies(ctx context.Context) (providers.CurrenciesResponse, error) {
+	m.currencyLookups++
+	return m.currencies, nil
+}
+
+func TestRebase(t *testing.T) {
+	reference := &domain.TimeSeriesData{
+		Base:    "EUR",
+		Targets: []domain.Currency{"USD", "HUF"},
+		DataPoints: []domain.DataPoint{
+			{Date: day("2023-01-02"), Rates: map[domain.Currency]float64{"USD": 1.0683, "HUF": 400.87}},
+			{Date: day("2023-01-03"), Rates: map[domain.Currency]float64{"HUF": 399.90}},
+		},
+		Sources: map[domain.Currency][]string{"USD": {"ecb"}, "HUF": {"ecb"}},
+	}
+
+	data := Rebase(reference, "USD", []domain.Currency{"HUF", "EUR"})
+	if len(data.DataPoints) != 1 {
+		t.Fatalf("DataPoints = %d, want 1 (no USD quote on 2023-01-03)", len(data.DataPoints))
+	}
+	if got, want := data.DataPoints[0].Rates["HUF"], 400.87/1.0683; math.Abs(got-want) > 1e-12 {
+		t.Errorf("USD/HUF = %v, want %v", got, want)
+	}
+	if got, want := data.DataPoints[0].Rates["EUR"], 1/1.0683; math.Abs(got-want) > 1e-12 {
+		t.Errorf("USD/EUR = %v, want %v", got, want)
+	}
+	if len(data.DerivedVia) != 1 || data.DerivedVia["HUF"] != "EUR" {
+		t.Errorf("DerivedVia = %v, want only HUF via EUR", data.DerivedVia)
+	}
+	if got := data.Sources["HUF"]; len(got) != 1 || got[0] != "ecb" {
+		t.Errorf("Sources[HUF] = %v, want [ecb]", got)
+	}
+
+	direct := Rebase(reference, "EUR", []domain.Currency{"HUF"})
+	if len(direct.DataPoints) != 2 || direct.DataPoints[1].Rates["HUF"] != 399.90 {
+		t.Errorf("rebasing onto the reference should keep its rates: %+v", direct.DataPoints)
+	}
+	if len(direct.DerivedVia) != 0 {
+		t.Errorf("DerivedVia = %v, want none for the reference base", direct.DerivedVia)
+	}
+}
+
+func TestService_FetchTimeSeriesData_Rebase(t *testing.T) {
+	api := &currenciesClient{
+		dailyAPIClient: dailyAPIClient{rates: eurRates()},
+		currencies:     providers.CurrenciesResponse{"EUR": "Euro", "USD": "US Dollar", "GBP": "British Pound", "HUF": "Hungarian Forint"},
+	}
+	memCache := cache.NewMemoryCache()
+	defer memCache.Close()
+
+	svc := NewService(api, memCache)
+	ctx := context.Background()
+
+	if _, err := svc.FetchTimeSeriesData(ctx, FetchOptions{
+		Base:      "HUF",
+		Targets:   []domain.Currency{"USD"},
+		StartDate: day("2023-01-02"),
+		EndDate:   day("2023-01-03"),
+		UseCache:  true,
+		Rebase:    true,
+	}); err != nil {
+		t.Fatalf("FetchTimeSeriesData() error = %v", err)
+	}
+
+	data, err := svc.FetchTimeSeriesData(ctx, FetchOptions{
+		Base:      "GBP",
+		Targets:   []domain.Currency{"HUF", "EUR"},
+		StartDate: day("2023-01-02"),
+		EndDate:   day("2023-01-03"),
+		UseCache:  true,
+		Rebase:    true,
+	})
+	if err != nil {
+		t.Fatalf("FetchTimeSeriesData() error = %v", err)
+	}
+
+	if len(api.requests) != 1 {
+		t.Errorf("API requests = %d, want 1 (second base derived from the cached matrix)", len(api.requests))
+	}
+	if api.currencyLookups != 1 {
+		t.Errorf("currency lookups = %d, want 1 (list cached with the matrix)", api.currencyLookups)
+	}
+	if report, err := svc.VerifyCache(ctx, false); err != nil || len(report.Corrupt) != 0 {
+		t.Errorf("VerifyCache() = %+v, %v, want the cached currency list accepted", report, err)
+	}
+	if got, want := data.DataPoints[1].Rates["HUF"], 399.90/0.87728; math.Abs(got-want) > 1e-9 {
+		t.Errorf("GBP/HUF = %v, want %v", got, want)
+	}
+}
+
+func TestService_FetchTimeSeriesData_RebaseWithoutCache(t *testing.T) {
+	api := &currenciesClient{
+		dailyAPIClient: dailyAPIClient{rates: eurRates()},
+		currencies:     providers.CurrenciesResponse{"EUR": "Euro", "USD": "US Dollar", "GBP": "British Pound", "HUF": "Hungarian Forint"},
+	}
+	memCache := cache.NewMemoryCache()
+	defer memCache.Close()
+
+	svc := NewService(api, memCache)
+	if _, err := svc.FetchTimeSeriesData(context.Background(), FetchOptions{
+		Base:      "HUF",
+		Targets:   []domain.Currency{"USD"},
+		StartDate: day("2023-01-02"),
+		EndDate:   day("2023-01-03"),
+		Rebase:    true,
+	}); err != nil {
+		t.Fatalf("FetchTimeSeriesData() error = %v", err)
+	}
+
+	if api.currencyLookups != 0 {
+		t.Errorf("currency lookups = %d, want none without the cache", api.currencyLookups)
+	}
+}
+
+func TestService_FetchTimeSeriesData_RebaseNewCurrency(t *testing.T) {
+	api := &currenciesClient{
+		dailyAPIClient: dailyAPIClient{rates: eurRates()},
+		currencies:     providers.CurrenciesResponse{"EUR": "Euro", "USD": "US Dollar", "HUF": "Hungarian Forint"},
+	}
+	memCache := cache.NewMemoryCache()
+	defer memCache.Close()
+
+	svc := NewService(api, memCache)
+	ctx := context.Background()
+	opts := FetchOptions{
+		Base:      "HUF",
+		Targets:   []domain.Currency{"USD"},
+		StartDate: day("2023-01-02"),
+		EndDate:   day("2023-01-03"),
+		UseCache:  true,
+		Rebase:    true,
+	}
+
+	if _, err := svc.FetchTimeSeriesData(ctx, opts); err != nil {
+		t.Fatalf("FetchTimeSeriesData() error = %v", err)
+	}
+
+	// The provider starts quoting GBP: the cached days still serve HUF/USD.
+	api.currencies["GBP"] = "British Pound"
+	memCache.Delete(ctx, currenciesCacheKey)
+
+	if _, err := svc.FetchTimeSeriesData(ctx, opts); err != nil {
+		t.Fatalf("FetchTimeSeriesData() error = %v", err)
+	}
+	if len(api.requests) != 1 {
+		t.Errorf("API requests = %d, want 1 (a new matrix currency leaves cached legs complete)", len(api.requests))
+	}
+}
@@ -0,0 +1,219 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kaze/xrv/internal/domain"
+)
+
+type Interval string
+
+const (
+	IntervalDay     Interval = "day"
+	IntervalWeek    Interval = "week"
+	IntervalMonth   Interval = "month"
+	IntervalQuarter Interval = "quarter"
+	IntervalYear    Interval = "year"
+)
+
+func ParseInterval(value string) (Interval, error) {
+	switch interval := Interval(strings.ToLower(strings.TrimSpace(value))); interval {
+	case "":
+		return IntervalDay, nil
+	case IntervalDay, IntervalWeek, IntervalMonth, IntervalQuarter, IntervalYear:
+		return interval, nil
+	default:
+		return "", fmt.Errorf("unknown interval: %s (use 'day', 'week', 'month', 'quarter' or 'year')", value)
+	}
+}
+
+// PeriodsPerYear is the number of observations in a year, used to annualize
+// volatility: trading days for daily fixings.
+func (i Interval) PeriodsPerYear() float64 {
+	switch i {
+	case IntervalWeek:
+		return 52
+	case IntervalMonth:
+		return 12
+	case IntervalQuarter:
+		return 4
+	case IntervalYear:
+		return 1
+	default:
+		return 252
+	}
+}
+
+// Start returns the first day of the period containing date. Weeks start on
+// Monday.
+func (i Interval) Start(date time.Time) time.Time {
+	date = truncateDay(date)
+
+	switch i {
+	case IntervalWeek:
+		offset := (int(date.Weekday()) + 6) % 7
+		return date.AddDate(0, 0, -offset)
+	case IntervalMonth:
+		return time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+	case IntervalQuarter:
+		month := time.Month((int(date.Month())-1)/3*3 + 1)
+		return time.Date(date.Year(), month, 1, 0, 0, 0, 0, time.UTC)
+	case IntervalYear:
+		return time.Date(date.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return date
+	}
+}
+
+// End returns the last day of the period containing date.
+func (i Interval) End(date time.Time) time.Time {
+	start := i.Start(date)
+
+	switch i {
+	case IntervalWeek:
+		return start.AddDate(0, 0, 6)
+	case IntervalMonth:
+		return start.AddDate(0, 1, -1)
+	case IntervalQuarter:
+		return start.AddDate(0, 3, -1)
+	case IntervalYear:
+		return start.AddDate(1, 0, -1)
+	default:
+		return start
+	}
+}
+
+// Label names the period containing date, e.g. 2024-W05, 2024-01, 2024-Q1
+// or 2024; weeks follow ISO 8601.
+func (i Interval) Label(date time.Time) string {
+	switch i {
+	case IntervalWeek:
+		year, week := date.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	case IntervalMonth:
+		return date.Format("2006-01")
+	case IntervalQuarter:
+		return fmt.Sprintf("%d-Q%d", date.Year(), (int(date.Month())+2)/3)
+	case IntervalYear:
+		return date.Format("2006")
+	default:
+		return date.Format("2006-01-02")
+	}
+}
+
+type Aggregation string
+
+const (
+	AggregateLast Aggregation = "last"
+	AggregateMean Aggregation = "mean"
+	AggregateOHLC Aggregation = "ohlc"
+	AggregateMin  Aggregation = "min"
+	AggregateMax  Aggregation = "max"
+)
+
+func ParseAggregation(value string) (Aggregation, error) {
+	switch agg := Aggregation(strings.ToLower(strings.TrimSpace(value))); agg {
+	case "":
+		return AggregateLast, nil
+	case AggregateLast, AggregateMean, AggregateOHLC, AggregateMin, AggregateMax:
+		return agg, nil
+	default:
+		return "", fmt.Errorf("unknown aggregation: %s (use 'last', 'mean', 'ohlc', 'min' or 'max')", value)
+	}
+}
+
+// Resample aggregates daily fixings into one data point per period, dated at
+// the start of the period. With AggregateOHLC the rate is the period's close
+// and the full bar is kept in DataPoint.OHLC.
+func Resample(data *domain.TimeSeriesData, interval Interval, agg Aggregation) *domain.TimeSeriesData {
+	if interval == "" || interval == IntervalDay {
+		return data
+	}
+	if agg == "" {
+		agg = AggregateLast
+	}
+
+	var periods []time.Time
+	values := make(map[time.Time]map[domain.Currency][]float64)
+	for _, dp := range data.DataPoints {
+		period := interval.Start(dp.Date)
+		if values[period] == nil {
+			values[period] = make(map[domain.Currency][]float64, len(data.Targets))
+			periods = append(periods, period)
+		}
+		for _, target := range data.Targets {
+			if rate, exists := dp.Rates[target]; exists {
+				values[period][target] = append(values[period][target], rate)
+			}
+		}
+	}
+
+	resampled := &domain.TimeSeriesData{
+		Base:       data.Base,
+		Targets:    data.Targets,
+		StartDate:  data.StartDate,
+		EndDate:    data.EndDate,
+		DataPoints: make([]domain.DataPoint, 0, len(periods)),
+		Sources:    data.Sources,
+		DerivedVia: data.DerivedVia,
+		Interval:   string(interval),
+	}
+
+	for _, period := range periods {
+		dp := domain.DataPoint{
+			Date:  period,
+			Rates: make(map[domain.Currency]float64, len(values[period])),
+		}
+		for target, rates := range values[period] {
+			bar := ohlc(rates)
+			switch agg {
+			case AggregateMean:
+				sum := 0.0
+				for _, rate := range rates {
+					sum += rate
+				}
+				dp.Rates[target] = sum / float64(len(rates))
+			case AggregateMin:
+				dp.Rates[target] = bar.Low
+			case AggregateMax:
+				dp.Rates[target] = bar.High
+			case AggregateOHLC:
+				if dp.OHLC == nil {
+					dp.OHLC = make(map[domain.Currency]domain.OHLC, len(values[period]))
+				}
+				dp.OHLC[target] = bar
+				dp.Rates[target] = bar.Close
+			default:
+				dp.Rates[target] = bar.Close
+			}
+		}
+		resampled.DataPoints = append(resampled.DataPoints, dp)
+	}
+
+	return resampled
+}
+
+func ohlc(rates []float64) domain.OHLC {
+	bar := domain.OHLC{Open: rates[0], High: rates[0], Low: rates[0], Close: rates[len(rates)-1]}
+	for _, rate := range rates[1:] {
+		if rate > bar.High {
+			bar.High = rate
+		}
+		if rate < bar.Low {
+			bar.Low = rate
+		}
+	}
+	return bar
+}
+
+// CandleResampling returns the interval and aggregation behind a candlestick
+// chart. Daily series are grouped into weeks, as a single fixing per bar would
+// draw flat candles.
+func CandleResampling(interval Interval) (Interval, Aggregation) {
+	if interval == "" || interval == IntervalDay {
+		interval = IntervalWeek
+	}
+	return interval, AggregateOHLC
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/kaze/xrv/internal/domain"
+)
+
+func dailySeries(rates map[string]float64) *domain.TimeSeriesData {
+	observations := make(map[time.Time]map[domain.Currency]float64, len(rates))
+	for date, rate := range rates {
+		observations[day(date)] = map[domain.Currency]float64{"USD": rate}
+	}
+	return buildTimeSeriesData("EUR", []domain.Currency{"USD"}, time.Time{}, time.Time{}, observations)
+}
+
+func TestParseInterval(t *testing.T) {
+	if got, err := ParseInterval(""); err != nil || got != IntervalDay {
+		t.Errorf("ParseInterval(\"\") = %v, %v, want day", got, err)
+	}
+	if got, err := ParseInterval(" Month "); err != nil || got != IntervalMonth {
+		t.Errorf("ParseInterval(Month) = %v, %v, want month", got, err)
+	}
+	if _, err := ParseInterval("fortnight"); err == nil {
+		t.Error("ParseInterval(fortnight) should fail")
+	}
+	if _, err := ParseAggregation("median"); err == nil {
+		t.Error("ParseAggregation(median) should fail")
+	}
+}
+
+func TestInterval_Start(t *testing.T) {
+	tests := []struct {
+		interval Interval
+		date     string
+		want     string
+	}{
+		{IntervalWeek, "2024-01-07", "2024-01-01"},
+		{IntervalWeek, "2024-01-08", "2024-01-08"},
+		{IntervalMonth, "2024-02-29", "2024-02-01"},
+		{IntervalQuarter, "2024-06-30", "2024-04-01"},
+		{IntervalQuarter, "2024-10-01", "2024-10-01"},
+		{IntervalYear, "2024-12-31", "2024-01-01"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.interval.Start(day(tt.date)); !got.Equal(day(tt.want)) {
+			t.Errorf("%s start of %s = %s, want %s", tt.interval, tt.date, got.Format("2006-01-02"), tt.want)
+		}
+	}
+}
+
+func TestResample(t *testing.T) {
+	data := dailySeries(map[string]float64{
+		"2024-01-02": 1.10,
+		"2024-01-15": 1.08,
+		"2024-01-31": 1.09,
+		"2024-02-01": 1.07,
+		"2024-02-28": 1.11,
+	})
+
+	tests := []struct {
+		agg  Aggregation
+		want []float64
+	}{
+		{AggregateLast, []float64{1.09, 1.11}},
+		{AggregateMean, []float64{(1.10 + 1.08 + 1.09) / 3, (1.07 + 1.11) / 2}},
+		{AggregateMin, []float64{1.08, 1.07}},
+		{AggregateMax, []float64{1.10, 1.11}},
+		{AggregateOHLC, []float64{1.09, 1.11}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.agg), func(t *testing.T) {
+			monthly := Resample(data, IntervalMonth, tt.agg)
+			if monthly.Interval != "month" {
+				t.Errorf("Interval = %q, want month", monthly.Interval)
+			}
+			if len(monthly.DataPoints) != len(tt.want) {
+				t.Fatalf("DataPoints = %d, want %d", len(monthly.DataPoints), len(tt.want))
+			}
+			for i, want := range tt.want {
+				if got := monthly.DataPoints[i].Rates["USD"]; math.Abs(got-want) > 1e-12 {
+					t.Errorf("point %d = %v, want %v", i, got, want)
+				}
+			}
+			if !monthly.DataPoints[1].Date.Equal(day("2024-02-01")) {
+				t.Errorf("second point dated %s, want 2024-02-01", monthly.DataPoints[1].Date.Format("2006-01-02"))
+			}
+		})
+	}
+
+	bars := Resample(data, IntervalMonth, AggregateOHLC)
+	want := domain.OHLC{Open: 1.10, High: 1.10, Low: 1.08, Close: 1.09}
+	if got := bars.DataPoints[0].OHLC["USD"]; got != want {
+		t.Errorf("January bar = %+v, want %+v", got, want)
+	}
+
+	if same := Resample(data, IntervalDay, AggregateMean); same != data {
+		t.Error("daily interval should return the series unchanged")
+	}
+}
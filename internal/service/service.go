@@ -2,14 +2,12 @@ package service
 
 import (
 	"context"
-	"crypto/sha256"
-	"encoding/json"
 	"fmt"
 	"sort"
-	"strings"
 	"time"
 
 	"github.com/kaze/xrv/internal/cache"
+	"github.com/kaze/xrv/internal/calendar"
 	"github.com/kaze/xrv/internal/providers"
 	"github.com/kaze/xrv/internal/domain"
 	"github.com/kaze/xrv/internal/statistics"
@@ -21,69 +19,247 @@ type APIClient interface {
 }
 
 type FetchOptions struct {
-	Base      domain.Currency
-	Targets   []domain.Currency
-	StartDate time.Time
-	EndDate   time.Time
-	UseCache  bool
+	Base        domain.Currency
+	Targets     []domain.Currency
+	StartDate   time.Time
+	EndDate     time.Time
+	UseCache    bool
+	Rebase      bool
+	Interval    Interval
+	Aggregation Aggregation
+	Gaps        GapPolicy
+}
+
+// Fetcher fetches time series; *Service satisfies it. Packages that only
+// read rates depend on it rather than on the service.
+type Fetcher interface {
+	FetchTimeSeriesData(ctx context.Context, opts FetchOptions) (*domain.TimeSeriesData, error)
 }
 
 type Service struct {
-	apiClient APIClient
-	cache     cache.Cache
-	
+	apiClient     APIClient
+	cache         cache.Cache
+	statsOptions  statistics.Options
+	corrWindow    int
+	currentDayTTL time.Duration
+	pivot         domain.Currency
+	calendar      calendar.Calendar
 }
 
-func NewService(apiClient APIClient, cache cache.Cache, ) *Service {
-	return &Service{
-		apiClient: apiClient,
-		cache:     cache,
-		
+type Option func(*Service)
+
+// WithMovingAverages replaces the default SMA(20)/SMA(50) pair; an empty
+// list keeps the defaults.
+func WithMovingAverages(specs []statistics.MovingAverageSpec) Option {
+	return func(s *Service) {
+		if len(specs) > 0 {
+			s.statsOptions.MovingAverages = specs
+		}
 	}
 }
 
-func (s *Service) FetchTimeSeriesData(ctx context.Context, opts FetchOptions) (*domain.TimeSeriesData, error) {
-	cacheKey := s.generateCacheKey(opts)
+// WithVolatility sets the rolling volatility windows and the EWMA decay
+// factor, also used for EWMA VaR; empty values keep the defaults of 30 and 90
+// returns and 0.94. The year length follows the interval of each series.
+func WithVolatility(windows []int, lambda float64) Option {
+	return func(s *Service) {
+		if len(windows) > 0 {
+			s.statsOptions.Volatility.Windows = windows
+		}
+		if lambda > 0 && lambda < 1 {
+			s.statsOptions.Volatility.Lambda = lambda
+			s.statsOptions.Risk.Lambda = lambda
+		}
+	}
+}
 
-	if opts.UseCache {
-		if cached, err := s.cache.Get(ctx, cacheKey); err == nil {
-			var data domain.TimeSeriesData
-			if err := json.Unmarshal(cached, &data); err == nil {
-				return &data, nil
-			}
+// WithRisk sets the VaR confidence levels and horizons; empty lists keep the
+// defaults of 95% and 99% over 1 and 10 observations.
+func WithRisk(confidences []float64, horizons []int) Option {
+	return func(s *Service) {
+		if len(confidences) > 0 {
+			s.statsOptions.Risk.Confidences = confidences
+		}
+		if len(horizons) > 0 {
+			s.statsOptions.Risk.Horizons = horizons
+		}
+	}
+}
+
+// WithCorrelationWindow sets the number of returns in each rolling
+// correlation; zero or less keeps the default of 30.
+func WithCorrelationWindow(window int) Option {
+	return func(s *Service) {
+		if window > 0 {
+			s.corrWindow = window
 		}
 	}
+}
 
-	targetsStr := make([]string, len(opts.Targets))
-	for i, t := range opts.Targets {
-		targetsStr[i] = string(t)
+// WithIndicators overrides the indicator settings that are set; zero values
+// keep the defaults.
+func WithIndicators(opts statistics.IndicatorOptions) Option {
+	return func(s *Service) {
+		current := &s.statsOptions.Indicators
+		if opts.RSIPeriod > 0 {
+			current.RSIPeriod = opts.RSIPeriod
+		}
+		if opts.MACDFast > 0 {
+			current.MACDFast = opts.MACDFast
+		}
+		if opts.MACDSlow > 0 {
+			current.MACDSlow = opts.MACDSlow
+		}
+		if opts.MACDSignal > 0 {
+			current.MACDSignal = opts.MACDSignal
+		}
+		if opts.BollingerPeriod > 0 {
+			current.BollingerPeriod = opts.BollingerPeriod
+		}
+		if opts.BollingerWidth > 0 {
+			current.BollingerWidth = opts.BollingerWidth
+		}
+	}
+}
+
+func WithCurrentDayTTL(ttl time.Duration) Option {
+	return func(s *Service) {
+		if ttl > 0 {
+			s.currentDayTTL = ttl
+		}
+	}
+}
+
+func WithPivot(currency domain.Currency) Option {
+	return func(s *Service) {
+		if currency != "" {
+			s.pivot = currency
+		}
+	}
+}
+
+func WithCalendar(cal calendar.Calendar) Option {
+	return func(s *Service) {
+		if cal != nil {
+			s.calendar = cal
+		}
+	}
+}
+
+func NewService(apiClient APIClient, cache cache.Cache, opts ...Option) *Service {
+	s := &Service{
+		apiClient:     apiClient,
+		cache:         cache,
+		statsOptions:  statistics.DefaultOptions(),
+		corrWindow:    30,
+		currentDayTTL: 1 * time.Hour,
+		pivot:         "EUR",
+		calendar:      calendar.TARGET2(),
 	}
 
-	resp, err := s.apiClient.GetTimeSeriesRates(
-		ctx,
-		opts.StartDate,
-		opts.EndDate,
-		string(opts.Base),
-		targetsStr,
-	)
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+func (s *Service) FetchTimeSeriesData(ctx context.Context, opts FetchOptions) (*domain.TimeSeriesData, error) {
+	data, err := s.fetchDaily(ctx, opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch from API: %w", err)
+		return nil, err
+	}
+	data = FillGaps(data, s.calendar, opts.Gaps)
+	return Resample(data, opts.Interval, opts.Aggregation), nil
+}
+
+func (s *Service) fetchDaily(ctx context.Context, opts FetchOptions) (*domain.TimeSeriesData, error) {
+	start := truncateDay(opts.StartDate)
+	end := truncateDay(opts.EndDate)
+	if end.Before(start) {
+		return nil, fmt.Errorf("end date %s is before start date %s", end.Format("2006-01-02"), start.Format("2006-01-02"))
+	}
+
+	if opts.Rebase && s.pivot != "" {
+		return s.fetchRebased(ctx, opts, start, end)
+	}
+
+	if s.canTriangulate(opts) && opts.UseCache && s.crossCached(ctx, opts, start, end) {
+		return s.fetchCross(ctx, opts, start, end)
+	}
+
+	data, err := s.fetchDirect(ctx, opts, nil, start, end)
+	if err != nil && s.canTriangulate(opts) && isUnsupportedPair(err) {
+		return s.fetchCross(ctx, opts, start, end)
 	}
 
-	data := s.transformToTimeSeriesData(resp, opts.Base, opts.Targets)
+	return data, err
+}
+
+// fetchDirect fetches opts.Targets, from the cache where it holds every one
+// of them. The fill currencies are requested and cached along with the days
+// that are fetched, but a day missing them in the cache is still complete.
+func (s *Service) fetchDirect(ctx context.Context, opts FetchOptions, fill []domain.Currency, start, end time.Time) (*domain.TimeSeriesData, error) {
+	observations := make(map[time.Time]map[domain.Currency]float64)
+	sources := make(sourceSet)
+	gaps := []dateRange{{start: start, end: end}}
 
 	if opts.UseCache {
-		if cached, err := json.Marshal(data); err == nil {
-			ttl := s.calculateTTL(opts.EndDate)
-			s.cache.Set(ctx, cacheKey, cached, ttl)
+		gaps = s.loadCachedRates(ctx, opts.Base, opts.Targets, start, end, observations, sources)
+	}
+
+	requested := append(opts.Targets[:len(opts.Targets):len(opts.Targets)], fill...)
+	targetsStr := make([]string, len(requested))
+	for i, t := range requested {
+		targetsStr[i] = string(t)
+	}
+
+	for _, gap := range gaps {
+		resp, err := s.apiClient.GetTimeSeriesRates(
+			ctx,
+			gap.start,
+			gap.end,
+			string(opts.Base),
+			targetsStr,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch from API: %w", err)
+		}
+
+		rates := responseRates(resp, opts.Targets)
+
+		if opts.UseCache {
+			stored := rates
+			if len(fill) > 0 {
+				stored = responseRates(resp, requested)
+			}
+			s.storeRates(ctx, opts.Base, requested, gap, stored, resp.Provider)
+		}
+
+		for date, dayRates := range rates {
+			if date.Before(start) || date.After(end) {
+				continue
+			}
+			if observations[date] == nil {
+				observations[date] = make(map[domain.Currency]float64, len(dayRates))
+			}
+			for currency, rate := range dayRates {
+				observations[date][currency] = rate
+				sources.add(currency, resp.Provider)
+			}
 		}
 	}
 
+	data := buildTimeSeriesData(opts.Base, opts.Targets, start, end, observations)
+	data.Sources = sources.sorted()
+
 	return data, nil
 }
 
 func (s *Service) CalculateStatistics(data *domain.TimeSeriesData) map[string]statistics.Statistics {
 	result := make(map[string]statistics.Statistics)
+	opts := s.statsOptions
+	opts.Volatility.PeriodsPerYear = Interval(data.Interval).PeriodsPerYear()
 
 	for _, target := range data.Targets {
 		rates := make([]float64, 0, len(data.DataPoints))
@@ -94,7 +270,7 @@ func (s *Service) CalculateStatistics(data *domain.TimeSeriesData) map[string]st
 		}
 
 		if len(rates) > 0 {
-			result[string(target)] = statistics.Calculate(rates)
+			result[string(target)] = statistics.CalculateWithOptions(rates, opts)
 		}
 	}
 
@@ -105,24 +281,6 @@ func (s *Service) GetSupportedCurrencies(ctx context.Context) (providers.Currenc
 	return s.apiClient.GetSupportedCurrencies(ctx)
 }
 
-func (s *Service) generateCacheKey(opts FetchOptions) string {
-	targets := make([]string, len(opts.Targets))
-	for i, t := range opts.Targets {
-		targets[i] = string(t)
-	}
-	sort.Strings(targets)
-
-	key := fmt.Sprintf("timeseries:%s:%s:%s:%s",
-		opts.Base,
-		strings.Join(targets, "-"),
-		opts.StartDate.Format("2006-01-02"),
-		opts.EndDate.Format("2006-01-02"),
-	)
-
-	hash := sha256.Sum256([]byte(key))
-	return fmt.Sprintf("%x", hash[:16])
-}
-
 func (s *Service) calculateTTL(endDate time.Time) time.Duration {
 	now := time.Now()
 	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
@@ -132,42 +290,102 @@ func (s *Service) calculateTTL(endDate time.Time) time.Duration {
 		return 0
 	}
 
-	return 1 * time.Hour
+	return s.currentDayTTL
 }
 
-func (s *Service) transformToTimeSeriesData(resp *providers.TimeSeriesResponse, base domain.Currency, targets []domain.Currency) *domain.TimeSeriesData {
-	data := &domain.TimeSeriesData{
-		Base:    base,
-		Targets: targets,
+type dateRange struct {
+	start time.Time
+	end   time.Time
+}
+
+type sourceSet map[domain.Currency]map[string]bool
+
+func (s sourceSet) add(target domain.Currency, source string) {
+	if source == "" {
+		return
+	}
+	if s[target] == nil {
+		s[target] = make(map[string]bool)
 	}
+	s[target][source] = true
+}
 
-	if startDate, err := time.Parse("2006-01-02", resp.StartDate); err == nil {
-		data.StartDate = startDate
+func (s sourceSet) sorted() map[domain.Currency][]string {
+	if len(s) == 0 {
+		return nil
 	}
-	if endDate, err := time.Parse("2006-01-02", resp.EndDate); err == nil {
-		data.EndDate = endDate
+
+	result := make(map[domain.Currency][]string, len(s))
+	for target, names := range s {
+		list := make([]string, 0, len(names))
+		for name := range names {
+			list = append(list, name)
+		}
+		sort.Strings(list)
+		result[target] = list
 	}
+	return result
+}
 
-	dates := make([]string, 0, len(resp.Rates))
-	for date := range resp.Rates {
-		dates = append(dates, date)
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func responseRates(resp *providers.TimeSeriesResponse, targets []domain.Currency) map[time.Time]map[domain.Currency]float64 {
+	wanted := make(map[string]bool, len(targets))
+	for _, t := range targets {
+		wanted[string(t)] = true
 	}
-	sort.Strings(dates)
 
-	data.DataPoints = make([]domain.DataPoint, 0, len(dates))
-	for _, dateStr := range dates {
-		if date, err := time.Parse("2006-01-02", dateStr); err == nil {
-			rates := make(map[domain.Currency]float64)
-			for currency, rate := range resp.Rates[dateStr] {
-				rates[domain.Currency(currency)] = rate
+	rates := make(map[time.Time]map[domain.Currency]float64, len(resp.Rates))
+	for dateStr, dayRates := range resp.Rates {
+		date, err := time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			continue
+		}
+
+		converted := make(map[domain.Currency]float64, len(dayRates))
+		for currency, rate := range dayRates {
+			if wanted[currency] {
+				converted[domain.Currency(currency)] = rate
 			}
+		}
+		if len(converted) > 0 {
+			rates[date] = converted
+		}
+	}
+
+	return rates
+}
+
+func buildTimeSeriesData(base domain.Currency, targets []domain.Currency, start, end time.Time, observations map[time.Time]map[domain.Currency]float64) *domain.TimeSeriesData {
+	data := &domain.TimeSeriesData{
+		Base:      base,
+		Targets:   targets,
+		StartDate: start,
+		EndDate:   end,
+	}
 
-			data.DataPoints = append(data.DataPoints, domain.DataPoint{
-				Date:  date,
-				Rates: rates,
-			})
+	dates := make([]time.Time, 0, len(observations))
+	for date, rates := range observations {
+		if len(rates) > 0 {
+			dates = append(dates, date)
 		}
 	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+
+	data.DataPoints = make([]domain.DataPoint, 0, len(dates))
+	for _, date := range dates {
+		data.DataPoints = append(data.DataPoints, domain.DataPoint{
+			Date:  date,
+			Rates: observations[date],
+		})
+	}
+
+	if len(dates) > 0 {
+		data.StartDate = dates[0]
+		data.EndDate = dates[len(dates)-1]
+	}
 
 	return data
 }
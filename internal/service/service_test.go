@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"math"
 	"testing"
 	"time"
 
@@ -152,6 +153,26 @@ func TestService_CalculateStatistics(t *testing.T) {
 	}
 }
 
+func TestService_CalculateStatistics_AnnualizesByInterval(t *testing.T) {
+	data := dailySeries(map[string]float64{
+		"2024-01-01": 1.00,
+		"2024-01-08": 1.02,
+		"2024-01-15": 0.99,
+	})
+	svc := NewService(nil, nil)
+
+	daily := svc.CalculateStatistics(data)["USD"].Volatility
+	data.Interval = string(IntervalWeek)
+	weekly := svc.CalculateStatistics(data)["USD"].Volatility
+
+	if weekly.Options.PeriodsPerYear != 52 {
+		t.Errorf("PeriodsPerYear = %v, want 52 for weekly bars", weekly.Options.PeriodsPerYear)
+	}
+	if math.Abs(weekly.Annualized/daily.Annualized-math.Sqrt(52.0/252)) > 1e-9 {
+		t.Errorf("weekly volatility should be annualized with √52, got %v vs %v", weekly.Annualized, daily.Annualized)
+	}
+}
+
 func TestService_GetSupportedCurrencies(t *testing.T) {
 	mockAPI := &mockAPIClient{
 		currenciesResponse: providers.CurrenciesResponse{
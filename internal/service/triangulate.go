@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/kaze/xrv/internal/domain"
+	"github.com/kaze/xrv/internal/providers"
+)
+
+func (s *Service) canTriangulate(opts FetchOptions) bool {
+	return s.pivot != "" && opts.Base != s.pivot && len(s.legs(opts)) > 0
+}
+
+func (s *Service) legs(opts FetchOptions) []domain.Currency {
+	seen := map[domain.Currency]bool{s.pivot: true}
+	legs := make([]domain.Currency, 0, len(opts.Targets)+1)
+
+	for _, currency := range append([]domain.Currency{opts.Base}, opts.Targets...) {
+		if seen[currency] {
+			continue
+		}
+		seen[currency] = true
+		legs = append(legs, currency)
+	}
+
+	return legs
+}
+
+func (s *Service) crossCached(ctx context.Context, opts FetchOptions, start, end time.Time) bool {
+	if gaps := s.loadCachedRates(ctx, opts.Base, opts.Targets, start, end,
+		make(map[time.Time]map[domain.Currency]float64), make(sourceSet)); len(gaps) == 0 {
+		return false
+	}
+
+	gaps := s.loadCachedRates(ctx, s.pivot, s.legs(opts), start, end,
+		make(map[time.Time]map[domain.Currency]float64), make(sourceSet))
+	return len(gaps) == 0
+}
+
+func (s *Service) fetchCross(ctx context.Context, opts FetchOptions, start, end time.Time) (*domain.TimeSeriesData, error) {
+	reference, err := s.fetchDirect(ctx, FetchOptions{
+		Base:      s.pivot,
+		Targets:   s.legs(opts),
+		StartDate: start,
+		EndDate:   end,
+		UseCache:  opts.UseCache,
+	}, nil, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	return Rebase(reference, opts.Base, opts.Targets), nil
+}
+
+func isUnsupportedPair(err error) bool {
+	var apiErr *providers.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+
+	switch apiErr.StatusCode {
+	case http.StatusBadRequest, http.StatusNotFound, http.StatusUnprocessableEntity:
+		return true
+	default:
+		return false
+	}
+}
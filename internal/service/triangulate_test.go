@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/kaze/xrv/internal/cache"
+	"github.com/kaze/xrv/internal/domain"
+	"github.com/kaze/xrv/internal/providers"
+)
+
+type pivotOnlyClient struct {
+	dailyAPIClient
+}
+
+func (m *pivotOnlyClient) GetTimeSeriesRates(ctx context.Context, startDate, endDate time.Time, base string, targets []string) (*providers.TimeSeriesResponse, error) {
+	if base != "EUR" {
+		m.requests = append(m.requests, recordedRequest{start: startDate, end: endDate})
+		return nil, &providers.APIError{StatusCode: http.StatusNotFound, Message: "not found"}
+	}
+	return m.dailyAPIClient.GetTimeSeriesRates(ctx, startDate, endDate, base, targets)
+}
+
+func eurRates() map[string]map[string]float64 {
+	return map[string]map[string]float64{
+		"2023-01-02": {"USD": 1.0683, "GBP": 0.88383, "HUF": 400.87},
+		"2023-01-03": {"USD": 1.0545, "GBP": 0.87728, "HUF": 399.90},
+	}
+}
+
+func TestService_Triangulate_FromCachedPivot(t *testing.T) {
+	api := &dailyAPIClient{rates: eurRates()}
+	memCache := cache.NewMemoryCache()
+	defer memCache.Close()
+
+	svc := NewService(api, memCache)
+	ctx := context.Background()
+
+	_, err := svc.FetchTimeSeriesData(ctx, FetchOptions{
+		Base:      "EUR",
+		Targets:   []domain.Currency{"USD", "GBP", "HUF"},
+		StartDate: day("2023-01-02"),
+		EndDate:   day("2023-01-03"),
+		UseCache:  true,
+	})
+	if err != nil {
+		t.Fatalf("FetchTimeSeriesData() error = %v", err)
+	}
+
+	data, err := svc.FetchTimeSeriesData(ctx, FetchOptions{
+		Base:      "USD",
+		Targets:   []domain.Currency{"GBP", "EUR"},
+		StartDate: day("2023-01-02"),
+		EndDate:   day("2023-01-03"),
+		UseCache:  true,
+	})
+	if err != nil {
+		t.Fatalf("FetchTimeSeriesData() error = %v", err)
+	}
+
+	if len(api.requests) != 1 {
+		t.Errorf("API requests = %d, want 1 (cross rates derived from the cached EUR legs)", len(api.requests))
+	}
+	if _, derived := data.DerivedVia["EUR"]; data.DerivedVia["GBP"] != "EUR" || derived {
+		t.Errorf("DerivedVia = %v, want GBP via EUR and EUR as the inverted leg", data.DerivedVia)
+	}
+	if len(data.DataPoints) != 2 {
+		t.Fatalf("DataPoints = %d, want 2", len(data.DataPoints))
+	}
+
+	rates := data.DataPoints[0].Rates
+	if got, want := rates["GBP"], 0.88383/1.0683; math.Abs(got-want) > 1e-12 {
+		t.Errorf("USD/GBP = %v, want %v", got, want)
+	}
+	if got, want := rates["EUR"], 1/1.0683; math.Abs(got-want) > 1e-12 {
+		t.Errorf("USD/EUR = %v, want %v", got, want)
+	}
+}
+
+func TestService_Triangulate_UnsupportedPair(t *testing.T) {
+	api := &pivotOnlyClient{dailyAPIClient{rates: eurRates()}}
+	svc := NewService(api, cache.NewMemoryCache())
+
+	data, err := svc.FetchTimeSeriesData(context.Background(), FetchOptions{
+		Base:      "HUF",
+		Targets:   []domain.Currency{"GBP"},
+		StartDate: day("2023-01-02"),
+		EndDate:   day("2023-01-03"),
+	})
+	if err != nil {
+		t.Fatalf("FetchTimeSeriesData() error = %v", err)
+	}
+
+	if data.DerivedVia["GBP"] != "EUR" {
+		t.Errorf("DerivedVia = %v, want GBP via EUR", data.DerivedVia)
+	}
+	if got, want := data.DataPoints[1].Rates["GBP"], 0.87728/399.90; math.Abs(got-want) > 1e-12 {
+		t.Errorf("HUF/GBP = %v, want %v", got, want)
+	}
+}
+
+func TestService_Triangulate_PrefersDirectCache(t *testing.T) {
+	api := &dailyAPIClient{rates: map[string]map[string]float64{
+		"2023-01-02": {"GBP": 0.83, "USD": 1.0683, "EUR": 0.94},
+	}}
+	memCache := cache.NewMemoryCache()
+	defer memCache.Close()
+
+	svc := NewService(api, memCache)
+	ctx := context.Background()
+
+	opts := FetchOptions{
+		Base:      "USD",
+		Targets:   []domain.Currency{"GBP"},
+		StartDate: day("2023-01-02"),
+		EndDate:   day("2023-01-02"),
+		UseCache:  true,
+	}
+	if _, err := svc.FetchTimeSeriesData(ctx, opts); err != nil {
+		t.Fatalf("FetchTimeSeriesData() error = %v", err)
+	}
+
+	data, err := svc.FetchTimeSeriesData(ctx, opts)
+	if err != nil {
+		t.Fatalf("FetchTimeSeriesData() error = %v", err)
+	}
+	if len(data.DerivedVia) != 0 || data.DataPoints[0].Rates["GBP"] != 0.83 {
+		t.Errorf("directly cached pair should not be derived: %+v", data)
+	}
+	if len(api.requests) != 1 {
+		t.Errorf("API requests = %d, want 1", len(api.requests))
+	}
+}
@@ -1,6 +1,7 @@
 package statistics
 
 import (
+	"math"
 	"testing"
 )
 
@@ -49,17 +50,56 @@ func TestCalculateTrend_Direction(t *testing.T) {
 
 func TestCalculateSMA(t *testing.T) {
 	rates := []float64{1.0, 2.0, 3.0, 4.0, 5.0}
-	sma := calculateSMA(rates, 3)
+	sma := CalculateSMA(rates, 3)
 
-	expected := []float64{2.0, 3.0, 4.0}
+	expected := []float64{math.NaN(), math.NaN(), 2.0, 3.0, 4.0}
 
 	if len(sma) != len(expected) {
 		t.Fatalf("SMA length = %d, want %d", len(sma), len(expected))
 	}
 
 	for i, v := range expected {
+		if math.IsNaN(v) {
+			if !math.IsNaN(sma[i]) {
+				t.Errorf("SMA[%d] = %v, want NaN before the window fills", i, sma[i])
+			}
+			continue
+		}
 		if !floatsAlmostEqual(sma[i], v) {
 			t.Errorf("SMA[%d] = %v, want %v", i, sma[i], v)
 		}
 	}
 }
+
+func TestCalculateWithOptions_MovingAverages(t *testing.T) {
+	rates := make([]float64, 30)
+	for i := range rates {
+		rates[i] = float64(i + 1)
+	}
+
+	stats := CalculateWithOptions(rates, Options{MovingAverages: []MovingAverageSpec{
+		{Kind: SMA, Period: 5}, {Kind: SMA, Period: 10}, {Kind: SMA, Period: 200}, {Kind: EMA, Period: 10},
+	}})
+
+	sma := stats.Trend.MovingAverages[SMA]
+	if len(sma) != 3 || len(stats.Trend.MovingAverages[EMA]) != 1 {
+		t.Fatalf("MovingAverages = %v, want 3 SMAs and 1 EMA", stats.Trend.MovingAverages)
+	}
+
+	if got := len(sma[5]); got != 30 {
+		t.Errorf("SMA[5] length = %d, want 30 (aligned with the rates)", got)
+	}
+
+	if got := sma[200].Last(); !math.IsNaN(got) {
+		t.Errorf("SMA[200] = %v, want undefined for a short series", got)
+	}
+
+	if !floatsAlmostEqual(sma[10][9], 5.5) {
+		t.Errorf("SMA[10][9] = %v, want 5.5", sma[10][9])
+	}
+
+	averages := stats.Trend.Averages()
+	if len(averages) != 4 || averages[0].Spec.String() != "SMA(5)" || averages[3].Spec.String() != "EMA(10)" {
+		t.Errorf("Averages() order = %v", averages)
+	}
+}
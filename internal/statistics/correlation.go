@@ -0,0 +1,155 @@
+package statistics
+
+import (
+	"math"
+	"time"
+)
+
+// Correlations holds the pairwise Pearson correlation of log returns for a
+// set of currencies. Matrix rows and columns follow Currencies; Dates are the
+// dates of the returns, the later of each pair of aligned rates.
+type Correlations struct {
+	Currencies []string
+	Dates      []time.Time
+	Matrix     []Series
+	Window     int
+	Rolling    []RollingCorrelation
+}
+
+// RollingCorrelation is the correlation of two currencies over a trailing
+// window of returns, aligned with Correlations.Dates.
+type RollingCorrelation struct {
+	A, B   string
+	Values Series
+}
+
+// LogReturns returns ln(rate[i] / rate[i-1]), one shorter than the rates.
+func LogReturns(rates []float64) Series {
+	if len(rates) < 2 {
+		return Series{}
+	}
+
+	returns := make(Series, len(rates)-1)
+	for i := 1; i < len(rates); i++ {
+		returns[i-1] = math.Log(rates[i] / rates[i-1])
+	}
+	return returns
+}
+
+// Correlation is the Pearson correlation of two equally long samples, NaN
+// when either is shorter than two values or constant.
+func Correlation(a, b []float64) float64 {
+	var w correlationWindow
+	for i := range a {
+		w.add(a[i], b[i])
+	}
+	return w.value()
+}
+
+// CalculateRollingCorrelation correlates each trailing window of returns in
+// O(n), NaN until the window is full.
+func CalculateRollingCorrelation(a, b []float64, window int) Series {
+	result := nanSeries(len(a))
+	if window < 2 {
+		return result
+	}
+
+	var w correlationWindow
+	for i := range a {
+		w.add(a[i], b[i])
+		if i >= window {
+			w.remove(a[i-window], b[i-window])
+		}
+		if i >= window-1 {
+			result[i] = w.value()
+		}
+	}
+	return result
+}
+
+// CalculateCorrelations correlates the log returns of rates that are aligned
+// on the same dates: rates[i] belongs to currencies[i] and holds one rate per
+// date. A window below two skips the rolling correlations.
+func CalculateCorrelations(currencies []string, dates []time.Time, rates [][]float64, window int) Correlations {
+	returns := make([]Series, len(rates))
+	for i := range rates {
+		returns[i] = LogReturns(rates[i])
+	}
+
+	result := Correlations{
+		Currencies: currencies,
+		Matrix:     make([]Series, len(currencies)),
+	}
+	if len(dates) > 1 {
+		result.Dates = dates[1:]
+	}
+	if window >= 2 {
+		result.Window = window
+	}
+
+	for i := range currencies {
+		result.Matrix[i] = make(Series, len(currencies))
+		result.Matrix[i][i] = Correlation(returns[i], returns[i])
+		for j := 0; j < i; j++ {
+			value := Correlation(returns[i], returns[j])
+			result.Matrix[i][j], result.Matrix[j][i] = value, value
+		}
+	}
+
+	if result.Window > 0 {
+		for i := range currencies {
+			for j := i + 1; j < len(currencies); j++ {
+				result.Rolling = append(result.Rolling, RollingCorrelation{
+					A:      currencies[i],
+					B:      currencies[j],
+					Values: CalculateRollingCorrelation(returns[i], returns[j], window),
+				})
+			}
+		}
+	}
+
+	return result
+}
+
+// correlationWindow keeps the running sums needed for Pearson correlation so
+// values can enter and leave a rolling window in constant time.
+type correlationWindow struct {
+	n                   int
+	sumA, sumB          float64
+	sumAA, sumBB, sumAB float64
+}
+
+func (w *correlationWindow) add(a, b float64) {
+	w.n++
+	w.sumA += a
+	w.sumB += b
+	w.sumAA += a * a
+	w.sumBB += b * b
+	w.sumAB += a * b
+}
+
+func (w *correlationWindow) remove(a, b float64) {
+	w.n--
+	w.sumA -= a
+	w.sumB -= b
+	w.sumAA -= a * a
+	w.sumBB -= b * b
+	w.sumAB -= a * b
+}
+
+func (w *correlationWindow) value() float64 {
+	if w.n < 2 {
+		return math.NaN()
+	}
+
+	n := float64(w.n)
+	covariance := w.sumAB - w.sumA*w.sumB/n
+	varianceA := w.sumAA - w.sumA*w.sumA/n
+	varianceB := w.sumBB - w.sumB*w.sumB/n
+	// Rounding leaves a constant series with a tiny variance rather than zero.
+	if varianceA <= 1e-12*w.sumAA || varianceB <= 1e-12*w.sumBB {
+		return math.NaN()
+	}
+
+	return math.Max(-1, math.Min(1, covariance/math.Sqrt(varianceA*varianceB)))
+}
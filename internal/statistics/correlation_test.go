@@ -0,0 +1,83 @@
+package statistics
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestLogReturns(t *testing.T) {
+	got := LogReturns([]float64{1, 2, 1})
+	assertSeries(t, "LogReturns", got, []float64{math.Log(2), -math.Log(2)})
+
+	if got := LogReturns([]float64{1}); len(got) != 0 {
+		t.Errorf("LogReturns of a single rate = %v, want empty", got)
+	}
+}
+
+func TestCorrelation(t *testing.T) {
+	a := []float64{1, 2, 3, 4, 5}
+
+	if got := Correlation(a, []float64{2, 4, 6, 8, 10}); !floatsAlmostEqual(got, 1) {
+		t.Errorf("Correlation(a, 2a) = %v, want 1", got)
+	}
+	if got := Correlation(a, []float64{5, 4, 3, 2, 1}); !floatsAlmostEqual(got, -1) {
+		t.Errorf("Correlation(a, reversed) = %v, want -1", got)
+	}
+	if got := Correlation(a, []float64{3, 3, 3, 3, 3}); !math.IsNaN(got) {
+		t.Errorf("Correlation with a constant = %v, want NaN", got)
+	}
+}
+
+func TestCalculateRollingCorrelation_MatchesFullWindow(t *testing.T) {
+	a := make([]float64, 60)
+	b := make([]float64, 60)
+	for i := range a {
+		a[i] = math.Sin(float64(i) / 3)
+		b[i] = math.Cos(float64(i) / 5)
+	}
+
+	rolling := CalculateRollingCorrelation(a, b, 20)
+	if !math.IsNaN(rolling[18]) {
+		t.Errorf("rolling[18] = %v, want NaN before the window fills", rolling[18])
+	}
+	for _, i := range []int{19, 40, 59} {
+		if want := Correlation(a[i-19:i+1], b[i-19:i+1]); math.Abs(rolling[i]-want) > 1e-9 {
+			t.Errorf("rolling[%d] = %v, want %v", i, rolling[i], want)
+		}
+	}
+}
+
+func TestCalculateCorrelations(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	dates := make([]time.Time, 6)
+	for i := range dates {
+		dates[i] = start.AddDate(0, 0, i)
+	}
+	usd := []float64{1.00, 1.02, 1.01, 1.05, 1.04, 1.08}
+	// GBP moves as USD squared, so its log returns are exactly twice as large.
+	gbp := make([]float64, len(usd))
+	for i, rate := range usd {
+		gbp[i] = rate * rate
+	}
+	jpy := []float64{160, 158, 159, 155, 156, 152}
+
+	got := CalculateCorrelations([]string{"USD", "GBP", "JPY"}, dates, [][]float64{usd, gbp, jpy}, 3)
+
+	if len(got.Dates) != 5 || !got.Dates[0].Equal(dates[1]) {
+		t.Errorf("Dates = %v, want the dates of the 5 returns", got.Dates)
+	}
+	if !floatsAlmostEqual(got.Matrix[0][0], 1) || !floatsAlmostEqual(got.Matrix[0][1], 1) {
+		t.Errorf("USD row = %v, want 1 against itself and GBP", got.Matrix[0])
+	}
+	if got.Matrix[0][2] >= 0 || got.Matrix[0][2] != got.Matrix[2][0] {
+		t.Errorf("USD/JPY = %v / %v, want a symmetric negative correlation", got.Matrix[0][2], got.Matrix[2][0])
+	}
+
+	if len(got.Rolling) != 3 || got.Rolling[0].A != "USD" || got.Rolling[0].B != "GBP" {
+		t.Fatalf("Rolling = %+v, want the three pairs in order", got.Rolling)
+	}
+	if values := got.Rolling[0].Values; len(values) != 5 || !math.IsNaN(values[1]) || !floatsAlmostEqual(values[2], 1) {
+		t.Errorf("USD/GBP rolling = %v, want NaN, NaN, 1, ...", values)
+	}
+}
@@ -0,0 +1,57 @@
+package statistics
+
+// DrawdownStats describes the largest peak-to-trough decline of a series.
+// Positions and durations count observations, so they are fixings for daily
+// data and bars for resampled series. Recovery is -1 while the rate is still
+// below the peak.
+type DrawdownStats struct {
+	MaxDrawdown    float64
+	Peak           int
+	Trough         int
+	Recovery       int
+	Duration       int
+	TimeToRecovery int
+	Recovered      bool
+	Underwater     Series
+}
+
+// CalculateDrawdown finds the deepest decline below a running peak. The
+// underwater series holds, for every rate, the percentage below the highest
+// rate seen so far (zero at a new high); MaxDrawdown is its minimum.
+func CalculateDrawdown(rates []float64) DrawdownStats {
+	if len(rates) == 0 {
+		return DrawdownStats{Recovery: -1}
+	}
+
+	underwater := make(Series, len(rates))
+	peak, peakIndex := rates[0], 0
+	stats := DrawdownStats{Underwater: underwater}
+	for i, rate := range rates {
+		if rate >= peak {
+			peak, peakIndex = rate, i
+		}
+		if peak != 0 {
+			underwater[i] = (rate/peak - 1) * 100
+		}
+		if underwater[i] < stats.MaxDrawdown {
+			stats.MaxDrawdown = underwater[i]
+			stats.Peak, stats.Trough = peakIndex, i
+		}
+	}
+
+	stats.Duration = stats.Trough - stats.Peak
+	stats.Recovery = -1
+	if stats.MaxDrawdown == 0 {
+		stats.Recovery, stats.Recovered = stats.Trough, true
+		return stats
+	}
+	for i := stats.Trough + 1; i < len(rates); i++ {
+		if rates[i] >= rates[stats.Peak] {
+			stats.Recovery, stats.Recovered = i, true
+			stats.TimeToRecovery = i - stats.Trough
+			break
+		}
+	}
+
+	return stats
+}
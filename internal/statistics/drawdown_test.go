@@ -0,0 +1,41 @@
+package statistics
+
+import "testing"
+
+func TestCalculateDrawdown(t *testing.T) {
+	// Peak 1.20 at index 1, trough 0.90 at index 3, back above 1.20 at index 6.
+	rates := []float64{1.00, 1.20, 1.00, 0.90, 1.05, 1.15, 1.25, 1.10}
+	got := CalculateDrawdown(rates)
+
+	if !floatsAlmostEqual(got.MaxDrawdown, -25) {
+		t.Errorf("MaxDrawdown = %v, want -25", got.MaxDrawdown)
+	}
+	if got.Peak != 1 || got.Trough != 3 || got.Duration != 2 {
+		t.Errorf("Peak, Trough, Duration = %d, %d, %d, want 1, 3, 2", got.Peak, got.Trough, got.Duration)
+	}
+	if !got.Recovered || got.Recovery != 6 || got.TimeToRecovery != 3 {
+		t.Errorf("Recovery = %d after %d (recovered %v), want 6 after 3", got.Recovery, got.TimeToRecovery, got.Recovered)
+	}
+
+	want := []float64{0, 0, -100.0 / 6, -25, -12.5, -100.0 / 24, 0, (1.10/1.25 - 1) * 100}
+	assertSeries(t, "Underwater", got.Underwater, want)
+}
+
+func TestCalculateDrawdown_NotRecovered(t *testing.T) {
+	got := CalculateDrawdown([]float64{1.0, 0.8, 0.9})
+
+	if got.Recovered || got.Recovery != -1 || got.TimeToRecovery != 0 {
+		t.Errorf("drawdown should still be open, got %+v", got)
+	}
+	if !floatsAlmostEqual(got.MaxDrawdown, -20) {
+		t.Errorf("MaxDrawdown = %v, want -20", got.MaxDrawdown)
+	}
+}
+
+func TestCalculateDrawdown_Rising(t *testing.T) {
+	got := CalculateDrawdown([]float64{1.0, 1.1, 1.2})
+
+	if got.MaxDrawdown != 0 || !got.Recovered || got.Duration != 0 {
+		t.Errorf("a rising series has no drawdown, got %+v", got)
+	}
+}
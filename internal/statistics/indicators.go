@@ -0,0 +1,189 @@
+package statistics
+
+import (
+	"encoding/json"
+	"math"
+)
+
+// Series is aligned index for index with the rates it was computed from.
+// Values before an indicator has enough history are NaN and encode as null.
+type Series []float64
+
+func (s Series) MarshalJSON() ([]byte, error) {
+	values := make([]*float64, len(s))
+	for i := range s {
+		if !math.IsNaN(s[i]) {
+			values[i] = &s[i]
+		}
+	}
+	return json.Marshal(values)
+}
+
+// Last returns the most recent defined value, or NaN when there is none.
+func (s Series) Last() float64 {
+	for i := len(s) - 1; i >= 0; i-- {
+		if !math.IsNaN(s[i]) {
+			return s[i]
+		}
+	}
+	return math.NaN()
+}
+
+type MACD struct {
+	Line      Series
+	Signal    Series
+	Histogram Series
+}
+
+type BollingerBands struct {
+	Middle Series
+	Upper  Series
+	Lower  Series
+}
+
+type IndicatorStats struct {
+	RSI       Series
+	MACD      MACD
+	Bollinger BollingerBands
+	Options   IndicatorOptions
+}
+
+type IndicatorOptions struct {
+	RSIPeriod       int
+	MACDFast        int
+	MACDSlow        int
+	MACDSignal      int
+	BollingerPeriod int
+	BollingerWidth  float64
+}
+
+func DefaultIndicatorOptions() IndicatorOptions {
+	return IndicatorOptions{
+		RSIPeriod:       14,
+		MACDFast:        12,
+		MACDSlow:        26,
+		MACDSignal:      9,
+		BollingerPeriod: 20,
+		BollingerWidth:  2,
+	}
+}
+
+func CalculateIndicators(rates []float64, opts IndicatorOptions) IndicatorStats {
+	if len(rates) == 0 {
+		return IndicatorStats{}
+	}
+
+	return IndicatorStats{
+		RSI:       CalculateRSI(rates, opts.RSIPeriod),
+		MACD:      CalculateMACD(rates, opts.MACDFast, opts.MACDSlow, opts.MACDSignal),
+		Bollinger: CalculateBollinger(rates, opts.BollingerPeriod, opts.BollingerWidth),
+		Options:   opts,
+	}
+}
+
+// CalculateRSI uses Wilder's smoothing of average gains and losses.
+func CalculateRSI(rates []float64, period int) Series {
+	result := nanSeries(len(rates))
+	if period <= 0 || len(rates) <= period {
+		return result
+	}
+
+	var gain, loss float64
+	for i := 1; i <= period; i++ {
+		change := rates[i] - rates[i-1]
+		if change > 0 {
+			gain += change
+		} else {
+			loss -= change
+		}
+	}
+	gain /= float64(period)
+	loss /= float64(period)
+	result[period] = rsi(gain, loss)
+
+	for i := period + 1; i < len(rates); i++ {
+		change := rates[i] - rates[i-1]
+		up, down := 0.0, 0.0
+		if change > 0 {
+			up = change
+		} else {
+			down = -change
+		}
+		gain = (gain*float64(period-1) + up) / float64(period)
+		loss = (loss*float64(period-1) + down) / float64(period)
+		result[i] = rsi(gain, loss)
+	}
+
+	return result
+}
+
+func rsi(gain, loss float64) float64 {
+	if loss == 0 {
+		if gain == 0 {
+			return 50
+		}
+		return 100
+	}
+	return 100 - 100/(1+gain/loss)
+}
+
+func CalculateMACD(rates []float64, fast, slow, signal int) MACD {
+	fastEMA := ema(rates, fast)
+	slowEMA := ema(rates, slow)
+
+	line := nanSeries(len(rates))
+	for i := range rates {
+		line[i] = fastEMA[i] - slowEMA[i]
+	}
+
+	signalLine := ema(line, signal)
+	histogram := nanSeries(len(rates))
+	for i := range rates {
+		histogram[i] = line[i] - signalLine[i]
+	}
+
+	return MACD{Line: line, Signal: signalLine, Histogram: histogram}
+}
+
+// CalculateBollinger returns a simple moving average with bands width
+// population standard deviations above and below it.
+func CalculateBollinger(rates []float64, period int, width float64) BollingerBands {
+	bands := BollingerBands{
+		Middle: nanSeries(len(rates)),
+		Upper:  nanSeries(len(rates)),
+		Lower:  nanSeries(len(rates)),
+	}
+	if period <= 0 || len(rates) < period {
+		return bands
+	}
+
+	var sum, sumSq float64
+	for i, rate := range rates {
+		sum += rate
+		sumSq += rate * rate
+		if i >= period {
+			old := rates[i-period]
+			sum -= old
+			sumSq -= old * old
+		}
+		if i < period-1 {
+			continue
+		}
+
+		mean := sum / float64(period)
+		deviation := math.Sqrt(math.Max(sumSq/float64(period)-mean*mean, 0))
+		bands.Middle[i] = mean
+		bands.Upper[i] = mean + width*deviation
+		bands.Lower[i] = mean - width*deviation
+	}
+
+	return bands
+}
+
+func nanSeries(n int) Series {
+	s := make(Series, n)
+	for i := range s {
+		s[i] = math.NaN()
+	}
+	return s
+}
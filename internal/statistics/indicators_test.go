@@ -0,0 +1,82 @@
+package statistics
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func assertSeries(t *testing.T, name string, got Series, want []float64) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("%s length = %d, want %d", name, len(got), len(want))
+	}
+	for i := range want {
+		if math.IsNaN(want[i]) != math.IsNaN(got[i]) || (!math.IsNaN(want[i]) && math.Abs(got[i]-want[i]) > 1e-9) {
+			t.Errorf("%s[%d] = %v, want %v", name, i, got[i], want[i])
+		}
+	}
+}
+
+func TestCalculateEMA(t *testing.T) {
+	nan := math.NaN()
+	got := CalculateEMA([]float64{1, 2, 3, 4, 5}, 3)
+	// Seeded with the average of 1, 2, 3; alpha = 0.5.
+	assertSeries(t, "EMA", got, []float64{nan, nan, 2, 3, 4})
+}
+
+func TestCalculateRSI(t *testing.T) {
+	nan := math.NaN()
+
+	rising := CalculateRSI([]float64{1, 2, 3, 4}, 2)
+	assertSeries(t, "RSI rising", rising, []float64{nan, nan, 100, 100})
+
+	// Average gain 0.5 and loss 0.25 give RS 2; a further loss of 1 smooths
+	// them to 0.25 and 0.625, RS 0.4.
+	mixed := CalculateRSI([]float64{10, 11, 10.5, 9.5}, 2)
+	assertSeries(t, "RSI mixed", mixed, []float64{nan, nan, 100 - 100/(1+2.0), 100 - 100/(1+0.4)})
+}
+
+func TestCalculateMACD(t *testing.T) {
+	rates := make([]float64, 40)
+	for i := range rates {
+		rates[i] = 1 + float64(i)*0.01
+	}
+
+	macd := CalculateMACD(rates, 12, 26, 9)
+	if !math.IsNaN(macd.Line[24]) || math.IsNaN(macd.Line[25]) {
+		t.Errorf("MACD line should start at index 25: %v", macd.Line[20:27])
+	}
+	if !math.IsNaN(macd.Signal[32]) || math.IsNaN(macd.Signal[33]) {
+		t.Errorf("signal line should start at index 33: %v", macd.Signal[30:35])
+	}
+	if macd.Line.Last() <= 0 {
+		t.Errorf("MACD line = %v, want positive for a rising series", macd.Line.Last())
+	}
+	if got := macd.Histogram.Last(); math.Abs(got-(macd.Line.Last()-macd.Signal.Last())) > 1e-12 {
+		t.Errorf("histogram = %v, want line minus signal", got)
+	}
+}
+
+func TestCalculateBollinger(t *testing.T) {
+	nan := math.NaN()
+	bands := CalculateBollinger([]float64{1, 3, 1, 3}, 2, 2)
+
+	assertSeries(t, "middle", bands.Middle, []float64{nan, 2, 2, 2})
+	assertSeries(t, "upper", bands.Upper, []float64{nan, 4, 4, 4})
+	assertSeries(t, "lower", bands.Lower, []float64{nan, 0, 0, 0})
+}
+
+func TestSeries_MarshalJSON(t *testing.T) {
+	out, err := json.Marshal(Series{math.NaN(), 1.5})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if string(out) != "[null,1.5]" {
+		t.Errorf("Marshal() = %s, want [null,1.5]", out)
+	}
+
+	if _, err := json.Marshal(Calculate([]float64{1, 2, 3})); err != nil {
+		t.Errorf("statistics with undefined indicators should marshal: %v", err)
+	}
+}
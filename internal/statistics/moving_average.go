@@ -0,0 +1,198 @@
+package statistics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type MovingAverageKind string
+
+const (
+	SMA MovingAverageKind = "sma"
+	EMA MovingAverageKind = "ema"
+	WMA MovingAverageKind = "wma"
+)
+
+var kindOrder = map[MovingAverageKind]int{SMA: 0, EMA: 1, WMA: 2}
+
+type MovingAverageSpec struct {
+	Kind   MovingAverageKind
+	Period int
+}
+
+func (s MovingAverageSpec) String() string {
+	return fmt.Sprintf("%s(%d)", strings.ToUpper(string(s.Kind)), s.Period)
+}
+
+type MovingAverage struct {
+	Spec   MovingAverageSpec
+	Values Series
+}
+
+// ParseMovingAverage accepts a kind followed by a period, e.g. "sma20",
+// "EMA:50" or "wma 10".
+func ParseMovingAverage(value string) (MovingAverageSpec, error) {
+	trimmed := strings.ToLower(strings.TrimSpace(value))
+	digits := strings.IndexAny(trimmed, "0123456789")
+	if digits < 0 {
+		return MovingAverageSpec{}, fmt.Errorf("moving average %q has no period", value)
+	}
+
+	kind := MovingAverageKind(strings.TrimRight(trimmed[:digits], ":( "))
+	if _, known := kindOrder[kind]; !known {
+		return MovingAverageSpec{}, fmt.Errorf("unknown moving average %q (use sma, ema or wma followed by a period)", value)
+	}
+
+	period, err := strconv.Atoi(strings.TrimRight(trimmed[digits:], ")"))
+	if err != nil || period <= 0 {
+		return MovingAverageSpec{}, fmt.Errorf("invalid moving average period in %q", value)
+	}
+
+	return MovingAverageSpec{Kind: kind, Period: period}, nil
+}
+
+func ParseMovingAverages(values []string) ([]MovingAverageSpec, error) {
+	specs := make([]MovingAverageSpec, 0, len(values))
+	for _, value := range values {
+		if strings.TrimSpace(value) == "" {
+			continue
+		}
+		spec, err := ParseMovingAverage(value)
+		if err != nil {
+			return nil, err
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+func CalculateMovingAverage(rates []float64, spec MovingAverageSpec) Series {
+	switch spec.Kind {
+	case EMA:
+		return CalculateEMA(rates, spec.Period)
+	case WMA:
+		return CalculateWMA(rates, spec.Period)
+	default:
+		return CalculateSMA(rates, spec.Period)
+	}
+}
+
+// CalculateSMA keeps a running sum over the window, so it runs in O(n)
+// whatever the period.
+func CalculateSMA(rates []float64, period int) Series {
+	result := nanSeries(len(rates))
+	if period <= 0 || len(rates) < period {
+		return result
+	}
+
+	sum := 0.0
+	for i, rate := range rates {
+		sum += rate
+		if i >= period {
+			sum -= rates[i-period]
+		}
+		if i >= period-1 {
+			result[i] = sum / float64(period)
+		}
+	}
+
+	return result
+}
+
+// CalculateWMA weights the most recent rate by period, the one before by
+// period-1 and so on. Moving the window forward adds period times the new
+// rate and subtracts the plain sum of the previous window, which keeps it
+// O(n).
+func CalculateWMA(rates []float64, period int) Series {
+	result := nanSeries(len(rates))
+	if period <= 0 || len(rates) < period {
+		return result
+	}
+
+	denominator := float64(period*(period+1)) / 2
+	var sum, weighted float64
+	for i := 0; i < period; i++ {
+		sum += rates[i]
+		weighted += float64(i+1) * rates[i]
+	}
+	result[period-1] = weighted / denominator
+
+	for i := period; i < len(rates); i++ {
+		weighted += float64(period)*rates[i] - sum
+		sum += rates[i] - rates[i-period]
+		result[i] = weighted / denominator
+	}
+
+	return result
+}
+
+// CalculateEMA seeds the exponential moving average with the simple average
+// of the first period values, so the first defined value is at period-1.
+func CalculateEMA(rates []float64, period int) Series {
+	return ema(rates, period)
+}
+
+// ema skips leading NaN values, which lets it smooth other indicators.
+func ema(values []float64, period int) Series {
+	result := nanSeries(len(values))
+
+	start := 0
+	for start < len(values) && math.IsNaN(values[start]) {
+		start++
+	}
+	if period <= 0 || len(values)-start < period {
+		return result
+	}
+
+	sum := 0.0
+	for _, v := range values[start : start+period] {
+		sum += v
+	}
+	current := sum / float64(period)
+	result[start+period-1] = current
+
+	alpha := 2 / float64(period+1)
+	for i := start + period; i < len(values); i++ {
+		current = alpha*values[i] + (1-alpha)*current
+		result[i] = current
+	}
+
+	return result
+}
+
+func calculateMovingAverages(rates []float64, specs []MovingAverageSpec) map[MovingAverageKind]map[int]Series {
+	averages := make(map[MovingAverageKind]map[int]Series, len(specs))
+	for _, spec := range specs {
+		if averages[spec.Kind] == nil {
+			averages[spec.Kind] = make(map[int]Series)
+		}
+		averages[spec.Kind][spec.Period] = CalculateMovingAverage(rates, spec)
+	}
+	return averages
+}
+
+// Averages lists the moving averages ordered by kind (SMA, EMA, WMA) and
+// period.
+func (t TrendStats) Averages() []MovingAverage {
+	var averages []MovingAverage
+	for kind, periods := range t.MovingAverages {
+		for period, values := range periods {
+			averages = append(averages, MovingAverage{
+				Spec:   MovingAverageSpec{Kind: kind, Period: period},
+				Values: values,
+			})
+		}
+	}
+
+	sort.Slice(averages, func(i, j int) bool {
+		a, b := averages[i].Spec, averages[j].Spec
+		if a.Kind != b.Kind {
+			return kindOrder[a.Kind] < kindOrder[b.Kind]
+		}
+		return a.Period < b.Period
+	})
+	return averages
+}
@@ -0,0 +1,56 @@
+package statistics
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCalculateWMA(t *testing.T) {
+	nan := math.NaN()
+	got := CalculateWMA([]float64{1, 2, 3, 4, 10}, 3)
+	// (1*1 + 2*2 + 3*3) / 6, (2 + 6 + 12) / 6, (3 + 8 + 30) / 6
+	assertSeries(t, "WMA", got, []float64{nan, nan, 14.0 / 6, 20.0 / 6, 41.0 / 6})
+}
+
+func TestCalculateSMA_MatchesNaive(t *testing.T) {
+	rates := make([]float64, 300)
+	for i := range rates {
+		rates[i] = 1 + math.Sin(float64(i)/7)
+	}
+
+	got := CalculateSMA(rates, 50)
+	for i := 49; i < len(rates); i++ {
+		sum := 0.0
+		for _, rate := range rates[i-49 : i+1] {
+			sum += rate
+		}
+		if math.Abs(got[i]-sum/50) > 1e-9 {
+			t.Fatalf("SMA[%d] = %v, want %v", i, got[i], sum/50)
+		}
+	}
+}
+
+func TestParseMovingAverage(t *testing.T) {
+	tests := []struct {
+		value string
+		want  MovingAverageSpec
+	}{
+		{"sma20", MovingAverageSpec{Kind: SMA, Period: 20}},
+		{"EMA:50", MovingAverageSpec{Kind: EMA, Period: 50}},
+		{" wma 10 ", MovingAverageSpec{Kind: WMA, Period: 10}},
+		{"SMA(200)", MovingAverageSpec{Kind: SMA, Period: 200}},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseMovingAverage(tt.value)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseMovingAverage(%q) = %v, %v, want %v", tt.value, got, err, tt.want)
+		}
+	}
+
+	for _, value := range []string{"sma", "hma20", "ema0", "ema-5"} {
+		if _, err := ParseMovingAverage(value); err == nil {
+			t.Errorf("ParseMovingAverage(%q) should fail", value)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package statistics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+type RiskMethod string
+
+const (
+	RiskHistorical RiskMethod = "historical"
+	RiskParametric RiskMethod = "parametric"
+	RiskEWMA       RiskMethod = "ewma"
+)
+
+// RiskOptions lists the confidence levels (e.g. 0.99) and horizons, in
+// observations, to estimate; Lambda is the EWMA decay factor.
+type RiskOptions struct {
+	Confidences []float64
+	Horizons    []int
+	Lambda      float64
+}
+
+func DefaultRiskOptions() RiskOptions {
+	return RiskOptions{
+		Confidences: []float64{0.95, 0.99},
+		Horizons:    []int{1, 10},
+		Lambda:      0.94,
+	}
+}
+
+// RiskEstimate is a Value-at-Risk and Expected Shortfall pair, both in
+// percent of the position's value and positive for a loss.
+type RiskEstimate struct {
+	Method     RiskMethod
+	Confidence float64
+	Horizon    int
+	VaR        float64
+	ES         float64
+}
+
+// ConfidencePercent returns the confidence level as a percentage, e.g. 99.
+func (e RiskEstimate) ConfidencePercent() float64 {
+	return math.Round(e.Confidence*10000) / 100
+}
+
+// RiskStats holds the estimates for every method, confidence and horizon in
+// that order. The position is a holding of the target currency valued in the
+// base currency, so it loses when the rate rises.
+type RiskStats struct {
+	Observations int
+	Estimates    []RiskEstimate
+}
+
+// Find returns the estimate for a method, confidence and horizon.
+func (r RiskStats) Find(method RiskMethod, confidence float64, horizon int) (RiskEstimate, bool) {
+	for _, estimate := range r.Estimates {
+		if estimate.Method == method && estimate.Confidence == confidence && estimate.Horizon == horizon {
+			return estimate, true
+		}
+	}
+	return RiskEstimate{}, false
+}
+
+func ValidateRiskOptions(opts RiskOptions) error {
+	for _, confidence := range opts.Confidences {
+		if confidence <= 0.5 || confidence >= 1 {
+			return fmt.Errorf("confidence level %g must lie between 0.5 and 1", confidence)
+		}
+	}
+	for _, horizon := range opts.Horizons {
+		if horizon <= 0 {
+			return fmt.Errorf("horizon %d must be at least one observation", horizon)
+		}
+	}
+	return nil
+}
+
+// CalculateRisk estimates VaR and ES from the log returns of the rates.
+// One-period figures are scaled to longer horizons with the square root of
+// time. Historical estimates take the empirical loss quantile and the mean
+// loss beyond it; parametric ones assume normal returns with the sample mean
+// and deviation; EWMA ones assume zero-mean normal returns with the latest
+// RiskMetrics volatility. Estimates need at least two returns.
+func CalculateRisk(rates []float64, opts RiskOptions) RiskStats {
+	returns := LogReturns(rates)
+	stats := RiskStats{Observations: len(returns)}
+	if len(returns) < 2 || ValidateRiskOptions(opts) != nil {
+		return stats
+	}
+
+	// Holding the target currency loses the rate's log return.
+	losses := make([]float64, len(returns))
+	copy(losses, returns)
+	sort.Float64s(losses)
+
+	mean, deviation := meanStdDev(returns)
+	ewma := ewmaVolatility(returns, opts.Lambda, 1).Last()
+
+	normal := distuv.UnitNormal
+	for _, method := range []RiskMethod{RiskHistorical, RiskParametric, RiskEWMA} {
+		for _, confidence := range opts.Confidences {
+			var value, shortfall float64
+			z := normal.Quantile(confidence)
+			tail := normal.Prob(z) / (1 - confidence)
+
+			switch method {
+			case RiskHistorical:
+				value, shortfall = historicalTail(losses, confidence)
+			case RiskParametric:
+				value, shortfall = mean+z*deviation, mean+tail*deviation
+			case RiskEWMA:
+				if math.IsNaN(ewma) {
+					continue
+				}
+				value, shortfall = z*ewma, tail*ewma
+			}
+
+			for _, horizon := range opts.Horizons {
+				scale := math.Sqrt(float64(horizon))
+				stats.Estimates = append(stats.Estimates, RiskEstimate{
+					Method:     method,
+					Confidence: confidence,
+					Horizon:    horizon,
+					VaR:        lossPercent(value * scale),
+					ES:         lossPercent(shortfall * scale),
+				})
+			}
+		}
+	}
+
+	return stats
+}
+
+// historicalTail returns the loss at the confidence quantile of the sorted
+// losses and the mean of the losses at or beyond it.
+func historicalTail(sorted []float64, confidence float64) (float64, float64) {
+	// The epsilon keeps 0.95 * 20 from rounding up past 19.
+	index := int(math.Ceil(confidence*float64(len(sorted))-1e-9)) - 1
+	if index < 0 {
+		index = 0
+	}
+
+	var sum float64
+	for _, loss := range sorted[index:] {
+		sum += loss
+	}
+	return sorted[index], sum / float64(len(sorted)-index)
+}
+
+// lossPercent turns a log loss into the percentage of value lost.
+func lossPercent(logLoss float64) float64 {
+	return (1 - math.Exp(-logLoss)) * 100
+}
+
+func meanStdDev(values []float64) (float64, float64) {
+	var mean float64
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	return mean, sampleStdDev(values)
+}
@@ -0,0 +1,89 @@
+package statistics
+
+import (
+	"math"
+	"testing"
+)
+
+// ratesFromReturns builds a rate series whose log returns are exactly the
+// given values.
+func ratesFromReturns(returns []float64) []float64 {
+	rates := []float64{1}
+	for _, r := range returns {
+		rates = append(rates, rates[len(rates)-1]*math.Exp(r))
+	}
+	return rates
+}
+
+func TestCalculateRisk_Historical(t *testing.T) {
+	// Twenty returns from -0.9% to +1.0%: the rate rising is a loss for the
+	// holder of the target currency.
+	returns := make([]float64, 20)
+	for i := range returns {
+		returns[i] = float64(i-9) / 1000
+	}
+	risk := CalculateRisk(ratesFromReturns(returns), RiskOptions{
+		Confidences: []float64{0.9},
+		Horizons:    []int{1, 4},
+		Lambda:      0.94,
+	})
+
+	if risk.Observations != 20 || len(risk.Estimates) != 6 {
+		t.Fatalf("Observations = %d, estimates = %d, want 20 and 6", risk.Observations, len(risk.Estimates))
+	}
+
+	// The 90% quantile of 20 sorted losses is the 18th: 0.8%; the mean of
+	// the 18th to 20th is 0.9%.
+	one, _ := risk.Find(RiskHistorical, 0.9, 1)
+	if want := lossPercent(0.008); !floatsAlmostEqual(one.VaR, want) {
+		t.Errorf("historical VaR = %v, want %v", one.VaR, want)
+	}
+	if want := lossPercent(0.009); !floatsAlmostEqual(one.ES, want) {
+		t.Errorf("historical ES = %v, want %v", one.ES, want)
+	}
+
+	four, _ := risk.Find(RiskHistorical, 0.9, 4)
+	if want := lossPercent(0.016); !floatsAlmostEqual(four.VaR, want) {
+		t.Errorf("4-period VaR = %v, want %v (√4 scaling)", four.VaR, want)
+	}
+}
+
+func TestCalculateRisk_Parametric(t *testing.T) {
+	returns := []float64{0.01, -0.01, 0.02, -0.02, 0.005, -0.005}
+	risk := CalculateRisk(ratesFromReturns(returns), RiskOptions{Confidences: []float64{0.99}, Horizons: []int{1}, Lambda: 0.94})
+
+	mean, deviation := meanStdDev(returns)
+	got, exists := risk.Find(RiskParametric, 0.99, 1)
+	if !exists {
+		t.Fatal("missing parametric estimate")
+	}
+	if want := lossPercent(mean + 2.3263478740408408*deviation); math.Abs(got.VaR-want) > 1e-9 {
+		t.Errorf("parametric VaR = %v, want %v", got.VaR, want)
+	}
+	if got.ES <= got.VaR {
+		t.Errorf("ES = %v should exceed VaR = %v", got.ES, got.VaR)
+	}
+
+	ewma, exists := risk.Find(RiskEWMA, 0.99, 1)
+	if !exists || ewma.VaR <= 0 {
+		t.Errorf("EWMA estimate = %+v, want a positive VaR", ewma)
+	}
+}
+
+func TestCalculateRisk_TooShort(t *testing.T) {
+	if risk := CalculateRisk([]float64{1, 1.01}, DefaultRiskOptions()); len(risk.Estimates) != 0 {
+		t.Errorf("one return should give no estimates, got %v", risk.Estimates)
+	}
+}
+
+func TestValidateRiskOptions(t *testing.T) {
+	if err := ValidateRiskOptions(DefaultRiskOptions()); err != nil {
+		t.Errorf("defaults should be valid: %v", err)
+	}
+	if err := ValidateRiskOptions(RiskOptions{Confidences: []float64{95}}); err == nil {
+		t.Error("a confidence of 95 should be rejected (use 0.95)")
+	}
+	if err := ValidateRiskOptions(RiskOptions{Horizons: []int{0}}); err == nil {
+		t.Error("a zero horizon should be rejected")
+	}
+}
@@ -14,32 +14,67 @@ type BasicStats struct {
 	Median  float64
 }
 
+// VolatilityStats keeps the level-based dispersion (StdDev, Variance,
+// CoefficientOfVar) and simple AvgDailyReturn for compatibility; the other
+// fields are log-return volatilities in percent, see VolatilityOptions.
 type VolatilityStats struct {
 	StdDev           float64
 	Variance         float64
 	CoefficientOfVar float64
 	AvgDailyReturn   float64
+	Realized         float64
+	Annualized       float64
+	Rolling          map[int]Series
+	EWMA             Series
+	Options          VolatilityOptions
 }
 
+// TrendStats carries each configured moving average by kind and period,
+// aligned with the input rates.
 type TrendStats struct {
-	Direction     string
-	Slope         float64
-	PercentChange float64
-	SMA20         []float64
-	SMA50         []float64
+	Direction      string
+	Slope          float64
+	PercentChange  float64
+	MovingAverages map[MovingAverageKind]map[int]Series
 }
 
 type Statistics struct {
 	Basic      BasicStats
 	Volatility VolatilityStats
 	Trend      TrendStats
+	Drawdown   DrawdownStats
+	Risk       RiskStats
+	Indicators IndicatorStats
+}
+
+type Options struct {
+	MovingAverages []MovingAverageSpec
+	Indicators     IndicatorOptions
+	Volatility     VolatilityOptions
+	Risk           RiskOptions
+}
+
+func DefaultOptions() Options {
+	return Options{
+		MovingAverages: []MovingAverageSpec{{Kind: SMA, Period: 20}, {Kind: SMA, Period: 50}},
+		Indicators:     DefaultIndicatorOptions(),
+		Volatility:     DefaultVolatilityOptions(),
+		Risk:           DefaultRiskOptions(),
+	}
 }
 
 func Calculate(rates []float64) Statistics {
+	return CalculateWithOptions(rates, DefaultOptions())
+}
+
+func CalculateWithOptions(rates []float64, opts Options) Statistics {
 	return Statistics{
 		Basic:      CalculateBasic(rates),
-		Volatility: CalculateVolatility(rates),
-		Trend:      CalculateTrend(rates),
+		Volatility: calculateVolatility(rates, opts.Volatility),
+		Trend:      calculateTrend(rates, opts.MovingAverages),
+		Drawdown:   CalculateDrawdown(rates),
+		Risk:       CalculateRisk(rates, opts.Risk),
+		Indicators: CalculateIndicators(rates, opts.Indicators),
 	}
 }
 
@@ -70,6 +105,10 @@ func CalculateBasic(rates []float64) BasicStats {
 }
 
 func CalculateVolatility(rates []float64) VolatilityStats {
+	return calculateVolatility(rates, DefaultVolatilityOptions())
+}
+
+func calculateVolatility(rates []float64, opts VolatilityOptions) VolatilityStats {
 	if len(rates) == 0 {
 		return VolatilityStats{}
 	}
@@ -89,15 +128,21 @@ func CalculateVolatility(rates []float64) VolatilityStats {
 		avgDailyReturn = stat.Mean(dailyReturns, nil)
 	}
 
-	return VolatilityStats{
+	stats := VolatilityStats{
 		StdDev:           stdDev,
 		Variance:         variance,
 		CoefficientOfVar: coefficientOfVar,
 		AvgDailyReturn:   avgDailyReturn,
 	}
+	calculateLogVolatility(&stats, rates, opts)
+	return stats
 }
 
 func CalculateTrend(rates []float64) TrendStats {
+	return calculateTrend(rates, DefaultOptions().MovingAverages)
+}
+
+func calculateTrend(rates []float64, averages []MovingAverageSpec) TrendStats {
 	if len(rates) == 0 {
 		return TrendStats{}
 	}
@@ -116,15 +161,11 @@ func CalculateTrend(rates []float64) TrendStats {
 		percentChange = ((rates[len(rates)-1] - rates[0]) / rates[0]) * 100
 	}
 
-	sma20 := calculateSMA(rates, 20)
-	sma50 := calculateSMA(rates, 50)
-
 	return TrendStats{
-		Direction:     direction,
-		Slope:         slope,
-		PercentChange: percentChange,
-		SMA20:         sma20,
-		SMA50:         sma50,
+		Direction:      direction,
+		Slope:          slope,
+		PercentChange:  percentChange,
+		MovingAverages: calculateMovingAverages(rates, averages),
 	}
 }
 
@@ -167,20 +208,3 @@ func calculateSlope(rates []float64) float64 {
 
 	return numerator / denominator
 }
-
-func calculateSMA(rates []float64, period int) []float64 {
-	if len(rates) < period || period <= 0 {
-		return []float64{}
-	}
-
-	sma := make([]float64, len(rates)-period+1)
-	for i := 0; i <= len(rates)-period; i++ {
-		sum := 0.0
-		for j := 0; j < period; j++ {
-			sum += rates[i+j]
-		}
-		sma[i] = sum / float64(period)
-	}
-
-	return sma
-}
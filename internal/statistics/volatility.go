@@ -0,0 +1,102 @@
+package statistics
+
+import "math"
+
+// VolatilityOptions configures the log-return volatility measures.
+// PeriodsPerYear scales per-observation volatility to a year: 252 for daily
+// fixings (also used when it is zero), 52 for weekly bars and so on.
+type VolatilityOptions struct {
+	Windows        []int
+	Lambda         float64
+	PeriodsPerYear float64
+}
+
+func DefaultVolatilityOptions() VolatilityOptions {
+	return VolatilityOptions{
+		Windows:        []int{30, 90},
+		Lambda:         0.94,
+		PeriodsPerYear: 252,
+	}
+}
+
+// calculateLogVolatility fills the log-return measures of stats. All of them
+// are annualized percentages except Realized, which is per observation; the
+// rolling and EWMA series are aligned with the rates and NaN until defined,
+// while Realized and Annualized stay zero below two returns.
+func calculateLogVolatility(stats *VolatilityStats, rates []float64, opts VolatilityOptions) {
+	if opts.PeriodsPerYear <= 0 {
+		opts.PeriodsPerYear = 252
+	}
+	returns := LogReturns(rates)
+	annualize := 100 * math.Sqrt(opts.PeriodsPerYear)
+
+	if len(returns) >= 2 {
+		stats.Realized = sampleStdDev(returns) * 100
+		stats.Annualized = sampleStdDev(returns) * annualize
+	}
+
+	stats.Rolling = make(map[int]Series, len(opts.Windows))
+	for _, window := range opts.Windows {
+		if window >= 2 {
+			stats.Rolling[window] = rollingVolatility(returns, window, annualize)
+		}
+	}
+
+	stats.EWMA = ewmaVolatility(returns, opts.Lambda, annualize)
+	stats.Options = opts
+}
+
+// rollingVolatility is the sample standard deviation of each trailing window
+// of returns, kept in O(n) with running sums. Index i covers the returns
+// ending at rate i.
+func rollingVolatility(returns Series, window int, scale float64) Series {
+	result := nanSeries(len(returns) + 1)
+	var sum, sumSquares float64
+	for i, r := range returns {
+		sum += r
+		sumSquares += r * r
+		if i >= window {
+			old := returns[i-window]
+			sum -= old
+			sumSquares -= old * old
+		}
+		if i >= window-1 {
+			n := float64(window)
+			variance := math.Max(0, (sumSquares-sum*sum/n)/(n-1))
+			result[i+1] = math.Sqrt(variance) * scale
+		}
+	}
+	return result
+}
+
+// ewmaVolatility follows RiskMetrics: σ²(t) = λσ²(t-1) + (1-λ)r²(t), seeded
+// with the first squared return and assuming a zero mean return.
+func ewmaVolatility(returns Series, lambda, scale float64) Series {
+	result := nanSeries(len(returns) + 1)
+	if len(returns) == 0 || lambda <= 0 || lambda >= 1 {
+		return result
+	}
+
+	variance := returns[0] * returns[0]
+	for i, r := range returns {
+		if i > 0 {
+			variance = lambda*variance + (1-lambda)*r*r
+		}
+		result[i+1] = math.Sqrt(variance) * scale
+	}
+	return result
+}
+
+func sampleStdDev(values []float64) float64 {
+	var mean float64
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+
+	var sumSquares float64
+	for _, v := range values {
+		sumSquares += (v - mean) * (v - mean)
+	}
+	return math.Sqrt(sumSquares / float64(len(values)-1))
+}
@@ -0,0 +1,69 @@
+package statistics
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCalculateVolatility_LogReturns(t *testing.T) {
+	// Alternating moves of 1% up and back down.
+	rates := []float64{1.00, 1.01, 1.00, 1.01, 1.00}
+	stats := CalculateVolatility(rates)
+
+	up := math.Log(1.01)
+	returns := []float64{up, -up, up, -up}
+	want := sampleStdDev(returns) * 100
+	if !floatsAlmostEqual(stats.Realized, want) {
+		t.Errorf("Realized = %v, want %v", stats.Realized, want)
+	}
+	if !floatsAlmostEqual(stats.Annualized, want*math.Sqrt(252)) {
+		t.Errorf("Annualized = %v, want %v", stats.Annualized, want*math.Sqrt(252))
+	}
+	if stats.StdDev == 0 || stats.AvgDailyReturn == 0 {
+		t.Errorf("level-based fields should still be filled, got %+v", stats)
+	}
+}
+
+func TestCalculateVolatility_PeriodsPerYear(t *testing.T) {
+	rates := []float64{1.00, 1.02, 0.99, 1.03}
+	daily := calculateVolatility(rates, VolatilityOptions{PeriodsPerYear: 252})
+	weekly := calculateVolatility(rates, VolatilityOptions{PeriodsPerYear: 52})
+
+	if !floatsAlmostEqual(daily.Realized, weekly.Realized) {
+		t.Errorf("Realized should not depend on the year length")
+	}
+	if !floatsAlmostEqual(weekly.Annualized/daily.Annualized, math.Sqrt(52.0/252)) {
+		t.Errorf("Annualized ratio = %v, want √(52/252)", weekly.Annualized/daily.Annualized)
+	}
+}
+
+func TestRollingVolatility_MatchesNaive(t *testing.T) {
+	rates := make([]float64, 120)
+	for i := range rates {
+		rates[i] = 1 + 0.05*math.Sin(float64(i)/4) + 0.01*math.Cos(float64(i))
+	}
+	returns := LogReturns(rates)
+	rolling := rollingVolatility(returns, 30, 1)
+
+	if len(rolling) != len(rates) {
+		t.Fatalf("rolling length = %d, want %d (aligned with the rates)", len(rolling), len(rates))
+	}
+	if !math.IsNaN(rolling[29]) || math.IsNaN(rolling[30]) {
+		t.Errorf("rolling should start at rate 30, the end of the first 30 returns")
+	}
+	for _, i := range []int{30, 77, 119} {
+		if want := sampleStdDev(returns[i-30 : i]); math.Abs(rolling[i]-want) > 1e-12 {
+			t.Errorf("rolling[%d] = %v, want %v", i, rolling[i], want)
+		}
+	}
+}
+
+func TestEWMAVolatility(t *testing.T) {
+	returns := Series{0.01, 0.02, 0}
+	got := ewmaVolatility(returns, 0.9, 1)
+
+	v1 := 0.0001
+	v2 := 0.9*v1 + 0.1*0.0004
+	v3 := 0.9 * v2
+	assertSeries(t, "EWMA", got, []float64{math.NaN(), math.Sqrt(v1), math.Sqrt(v2), math.Sqrt(v3)})
+}
@@ -5,7 +5,50 @@ window.initializeChart = function(containerId, config) {
         return null;
     }
 
-    const chart = echarts.init(container);
+    // Indicator panes (RSI, MACD, drawdown) stack below the main chart and share its
+    // dates; the slider zooms all of them together.
+    const panes = config.panes || [];
+    if (panes.length) {
+        container.style.height = (600 + panes.length * 150) + 'px';
+    }
+
+    const chart = echarts.init(container, config.theme || null);
+    const paneHeight = 16;
+    const paneGap = 4;
+    const sliderSpace = 10;
+    const grids = [{
+        top: '20%',
+        bottom: (sliderSpace + panes.length * (paneHeight + paneGap) + (panes.length ? paneGap : 0)) + '%'
+    }].concat(panes.map(function(_, i) {
+        return {
+            bottom: (sliderSpace + (panes.length - 1 - i) * (paneHeight + paneGap)) + '%',
+            height: paneHeight + '%'
+        };
+    }));
+
+    function toSeries(s, axisIndex) {
+        const series = {
+            name: s.name,
+            type: s.type,
+            data: s.type === 'candlestick' ? s.candles : s.data,
+            smooth: s.smooth,
+            connectNulls: false,
+            xAxisIndex: axisIndex,
+            yAxisIndex: axisIndex
+        };
+        if (s.dashed) {
+            series.lineStyle = { type: 'dashed', width: 1 };
+            series.showSymbol = false;
+        }
+        if (s.area) {
+            series.areaStyle = { opacity: 0.3 };
+            series.showSymbol = false;
+        }
+        if (s.stack) {
+            series.stack = s.stack;
+        }
+        return series;
+    }
 
     const option = {
         title: {
@@ -20,7 +63,8 @@ window.initializeChart = function(containerId, config) {
         legend: {
             data: config.legend.data,
             show: config.legend.show,
-            top: config.legend.top
+            top: config.legend.top,
+            selected: config.legend.selected || {}
         },
         toolbox: {
             show: config.toolbox.show,
@@ -40,26 +84,43 @@ window.initializeChart = function(containerId, config) {
             return {
                 type: dz.type,
                 start: dz.start,
-                end: dz.end
+                end: dz.end,
+                xAxisIndex: [0].concat(panes.map(function(_, i) { return i + 1; }))
             };
         }),
-        xAxis: {
+        grid: grids,
+        xAxis: [{
             type: config.xAxis.type,
             name: config.xAxis.name,
             data: config.xAxis.data
-        },
-        yAxis: {
+        }].concat(panes.map(function(pane, i) {
+            return {
+                type: config.xAxis.type,
+                data: config.xAxis.data,
+                gridIndex: i + 1,
+                axisLabel: { show: false }
+            };
+        })),
+        yAxis: [{
             type: config.yAxis.type,
-            name: config.yAxis.name
-        },
-        series: config.series.map(function(s) {
+            name: config.yAxis.name,
+            scale: true
+        }].concat(panes.map(function(pane, i) {
             return {
-                name: s.name,
-                type: s.type,
-                data: s.data,
-                smooth: s.smooth
+                type: 'value',
+                name: pane.name,
+                gridIndex: i + 1,
+                scale: true,
+                splitNumber: 2
             };
-        })
+        })),
+        series: config.series.map(function(s) {
+            return toSeries(s, 0);
+        }).concat(panes.reduce(function(all, pane, i) {
+            return all.concat(pane.series.map(function(s) {
+                return toSeries(s, i + 1);
+            }));
+        }, []))
     };
 
     chart.setOption(option);
@@ -71,6 +132,46 @@ window.initializeChart = function(containerId, config) {
     return chart;
 };
 
+window.initializeHeatmap = function(containerId, config) {
+    const container = document.getElementById(containerId);
+    if (!container) {
+        return null;
+    }
+
+    const chart = echarts.init(container, config.theme || null);
+    chart.setOption({
+        title: { text: config.title, left: 'center', textStyle: { fontSize: 14 } },
+        tooltip: {
+            formatter: function(p) {
+                return config.currencies[p.value[1]] + ' / ' + config.currencies[p.value[0]] + ': ' + p.value[2].toFixed(2);
+            }
+        },
+        grid: { top: 50, bottom: 60, left: 60, right: 20 },
+        xAxis: { type: 'category', data: config.currencies, splitArea: { show: true } },
+        yAxis: { type: 'category', data: config.currencies, splitArea: { show: true } },
+        visualMap: {
+            min: -1,
+            max: 1,
+            calculable: true,
+            orient: 'horizontal',
+            left: 'center',
+            bottom: 0,
+            inRange: { color: ['#d73027', '#f7f7f7', '#1a9850'] }
+        },
+        series: [{
+            type: 'heatmap',
+            data: config.data,
+            label: { show: true, formatter: function(p) { return p.value[2].toFixed(2); } }
+        }]
+    });
+
+    window.addEventListener('resize', function() {
+        chart.resize();
+    });
+
+    return chart;
+};
+
 window.destroyChart = function(containerId) {
     const container = document.getElementById(containerId);
     if (container) {
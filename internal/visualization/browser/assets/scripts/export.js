@@ -10,6 +10,10 @@ window.exportChart = function(format) {
     const currencies = formData.get('currencies');
     const from = formData.get('from');
     const to = formData.get('to');
+    const interval = formData.get('interval') || 'day';
+    const aggregation = formData.get('aggregation') || 'last';
+    const chart = formData.get('chart') || 'line';
+    const gaps = formData.get('gaps') || '';
 
     if (!currencies || !from || !to) {
         alert('Please fill in all required fields before exporting');
@@ -17,10 +21,10 @@ window.exportChart = function(format) {
     }
 
     if (format === 'csv') {
-        const url = '/export/csv?base=' + encodeURIComponent(base) + '&currencies=' + encodeURIComponent(currencies) + '&from=' + encodeURIComponent(from) + '&to=' + encodeURIComponent(to);
+        const url = '/export/csv?base=' + encodeURIComponent(base) + '&currencies=' + encodeURIComponent(currencies) + '&from=' + encodeURIComponent(from) + '&to=' + encodeURIComponent(to) + '&interval=' + encodeURIComponent(interval) + '&aggregation=' + encodeURIComponent(aggregation) + '&chart=' + encodeURIComponent(chart) + '&gaps=' + encodeURIComponent(gaps);
         window.location.href = url;
     } else if (format === 'json') {
-        const url = '/export/json?base=' + encodeURIComponent(base) + '&currencies=' + encodeURIComponent(currencies) + '&from=' + encodeURIComponent(from) + '&to=' + encodeURIComponent(to);
+        const url = '/export/json?base=' + encodeURIComponent(base) + '&currencies=' + encodeURIComponent(currencies) + '&from=' + encodeURIComponent(from) + '&to=' + encodeURIComponent(to) + '&interval=' + encodeURIComponent(interval) + '&aggregation=' + encodeURIComponent(aggregation) + '&chart=' + encodeURIComponent(chart) + '&gaps=' + encodeURIComponent(gaps);
         window.location.href = url;
     } else if (format === 'image') {
         exportChartImage();
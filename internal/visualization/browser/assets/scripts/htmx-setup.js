@@ -13,6 +13,7 @@ document.addEventListener('htmx:afterSwap', function(event) {
 document.addEventListener('htmx:beforeSwap', function(event) {
     if (event.detail.target.id === 'chartContainer') {
         window.destroyChart('chartCanvas');
+        window.destroyChart('correlationCanvas');
     }
 });
 
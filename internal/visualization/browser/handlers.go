@@ -16,10 +16,11 @@ import (
 
 type Handlers struct {
 	svc *service.Service
+	settings
 }
 
-func NewHandlers(svc *service.Service) *Handlers {
-	return &Handlers{svc: svc}
+func NewHandlers(svc *service.Service, opts ...Option) *Handlers {
+	return &Handlers{svc: svc, settings: newSettings(opts)}
 }
 
 func (h *Handlers) HandleChartUpdate(w http.ResponseWriter, r *http.Request) {
@@ -60,6 +61,12 @@ func (h *Handlers) HandleChartUpdate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	opts, err := h.fetchOptions(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	targets := strings.Split(currenciesStr, ",")
 	targetCurrencies := make([]domain.Currency, 0, len(targets))
 	for _, t := range targets {
@@ -75,13 +82,11 @@ func (h *Handlers) HandleChartUpdate(w http.ResponseWriter, r *http.Request) {
 	}
 
 	ctx := context.Background()
-	data, err := h.svc.FetchTimeSeriesData(ctx, service.FetchOptions{
-		Base:      domain.Currency(base),
-		Targets:   targetCurrencies,
-		StartDate: startDate,
-		EndDate:   endDate,
-		UseCache:  true,
-	})
+	opts.Base = domain.Currency(base)
+	opts.Targets = targetCurrencies
+	opts.StartDate = startDate
+	opts.EndDate = endDate
+	data, err := h.svc.FetchTimeSeriesData(ctx, opts)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Failed to fetch data: %v", err), http.StatusInternalServerError)
 		return
@@ -98,6 +103,7 @@ func (h *Handlers) HandleChartUpdate(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, fmt.Sprintf("Failed to transform data: %v", err), http.StatusInternalServerError)
 		return
 	}
+	config.Theme = h.theme
 
 	configJSON, err := json.Marshal(config)
 	if err != nil {
@@ -105,15 +111,26 @@ func (h *Handlers) HandleChartUpdate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	correlations := h.svc.CalculateCorrelations(data)
+	heatmapJSON, err := marshalHeatmap(TransformCorrelationHeatmap(&correlations), h.theme)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to marshal heatmap: %v", err), http.StatusInternalServerError)
+		return
+	}
+
 	type TemplateData struct {
 		ChartConfigJSON template.JS
+		HeatmapJSON     template.JS
 		Statistics      map[string]statistics.Statistics
+		Series          map[string]seriesInfo
 	}
 
 	w.Header().Set("Content-Type", "text/html")
 	templates.ExecuteTemplate(w, "chart", TemplateData{
 		ChartConfigJSON: template.JS(configJSON),
+		HeatmapJSON:     heatmapJSON,
 		Statistics:      stats,
+		Series:          describeSeries(data),
 	})
 }
 
@@ -144,6 +161,12 @@ func (h *Handlers) HandleStatisticsRefresh(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	opts, err := h.fetchOptions(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	targets := strings.Split(currenciesStr, ",")
 	targetCurrencies := make([]domain.Currency, 0, len(targets))
 	for _, t := range targets {
@@ -154,13 +177,11 @@ func (h *Handlers) HandleStatisticsRefresh(w http.ResponseWriter, r *http.Reques
 	}
 
 	ctx := context.Background()
-	data, err := h.svc.FetchTimeSeriesData(ctx, service.FetchOptions{
-		Base:      domain.Currency(base),
-		Targets:   targetCurrencies,
-		StartDate: startDate,
-		EndDate:   endDate,
-		UseCache:  true,
-	})
+	opts.Base = domain.Currency(base)
+	opts.Targets = targetCurrencies
+	opts.StartDate = startDate
+	opts.EndDate = endDate
+	data, err := h.svc.FetchTimeSeriesData(ctx, opts)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Failed to fetch data: %v", err), http.StatusInternalServerError)
 		return
@@ -170,12 +191,119 @@ func (h *Handlers) HandleStatisticsRefresh(w http.ResponseWriter, r *http.Reques
 
 	type TemplateData struct {
 		Statistics map[string]statistics.Statistics
+		Series     map[string]seriesInfo
 	}
 
 	w.Header().Set("Content-Type", "text/html")
 	templates.ExecuteTemplate(w, "statistics", TemplateData{
 		Statistics: stats,
+		Series:     describeSeries(data),
+	})
+}
+
+// HandleAverages renders the period averages tab for the chart form's
+// currencies and dates. Inverted rates are averaged as inverted daily
+// fixings rather than inverted averages.
+func (h *Handlers) HandleAverages(w http.ResponseWriter, r *http.Request) {
+	base := r.FormValue("base")
+	if base == "" {
+		base = "USD"
+	}
+
+	currenciesStr := r.FormValue("currencies")
+	if currenciesStr == "" {
+		http.Error(w, "Currencies required", http.StatusBadRequest)
+		return
+	}
+
+	startDate, err := time.Parse("2006-01-02", r.FormValue("from"))
+	if err != nil {
+		http.Error(w, "Invalid start date", http.StatusBadRequest)
+		return
+	}
+
+	endDate, err := time.Parse("2006-01-02", r.FormValue("to"))
+	if err != nil {
+		http.Error(w, "Invalid end date", http.StatusBadRequest)
+		return
+	}
+
+	period := service.IntervalMonth
+	if value := r.FormValue("period"); value != "" {
+		if period, err = service.ParseInterval(value); err != nil || period == service.IntervalDay {
+			http.Error(w, "Period must be week, month, quarter or year", http.StatusBadRequest)
+			return
+		}
+	}
+
+	var targetCurrencies []domain.Currency
+	for _, t := range strings.Split(currenciesStr, ",") {
+		if trimmed := strings.TrimSpace(t); trimmed != "" {
+			targetCurrencies = append(targetCurrencies, domain.Currency(trimmed))
+		}
+	}
+
+	data, err := h.svc.FetchTimeSeriesData(context.Background(), service.FetchOptions{
+		Base:      domain.Currency(base),
+		Targets:   targetCurrencies,
+		StartDate: startDate,
+		EndDate:   endDate,
+		UseCache:  true,
+		Rebase:    h.rebase,
+		Gaps:      service.GapOmit,
 	})
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to fetch data: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	if r.FormValue("invert") == "true" {
+		data = invertRates(data)
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	templates.ExecuteTemplate(w, "averages", h.svc.CalculatePeriodAverages(data, period))
+}
+
+// fetchOptions reads the resampling and gap settings shared by the chart,
+// statistics and export requests. The gap policy falls back to the server's.
+func (h *Handlers) fetchOptions(r *http.Request) (service.FetchOptions, error) {
+	opts := service.FetchOptions{UseCache: true, Rebase: h.rebase, Gaps: h.gaps}
+
+	interval, err := service.ParseInterval(r.FormValue("interval"))
+	if err != nil {
+		return opts, err
+	}
+	aggregation, err := service.ParseAggregation(r.FormValue("aggregation"))
+	if err != nil {
+		return opts, err
+	}
+	if r.FormValue("chart") == "candle" {
+		interval, aggregation = service.CandleResampling(interval)
+	}
+	opts.Interval, opts.Aggregation = interval, aggregation
+
+	if value := r.FormValue("gaps"); value != "" {
+		if opts.Gaps, err = service.ParseGapPolicy(value); err != nil {
+			return opts, err
+		}
+	}
+
+	return opts, nil
+}
+
+// marshalHeatmap returns an empty script value for a nil heatmap, which the
+// chart template skips.
+func marshalHeatmap(heatmap *EChartsHeatmap, theme string) (template.JS, error) {
+	if heatmap == nil {
+		return "", nil
+	}
+	heatmap.Theme = theme
+	heatmapJSON, err := json.Marshal(heatmap)
+	if err != nil {
+		return "", err
+	}
+	return template.JS(heatmapJSON), nil
 }
 
 func invertRates(data *domain.TimeSeriesData) *domain.TimeSeriesData {
@@ -185,6 +313,9 @@ func invertRates(data *domain.TimeSeriesData) *domain.TimeSeriesData {
 		StartDate:  data.StartDate,
 		EndDate:    data.EndDate,
 		DataPoints: make([]domain.DataPoint, len(data.DataPoints)),
+		Sources:    data.Sources,
+		DerivedVia: data.DerivedVia,
+		Interval:   data.Interval,
 	}
 
 	for i, dp := range data.DataPoints {
@@ -198,6 +329,12 @@ func invertRates(data *domain.TimeSeriesData) *domain.TimeSeriesData {
 			Date:  dp.Date,
 			Rates: invertedRates,
 		}
+		if dp.OHLC != nil {
+			inverted.DataPoints[i].OHLC = make(map[domain.Currency]domain.OHLC, len(dp.OHLC))
+			for currency, bar := range dp.OHLC {
+				inverted.DataPoints[i].OHLC[currency] = bar.Invert()
+			}
+		}
 	}
 
 	return inverted
@@ -230,6 +367,12 @@ func (h *Handlers) HandleExportCSV(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	opts, err := h.fetchOptions(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	targets := strings.Split(currenciesStr, ",")
 	targetCurrencies := make([]domain.Currency, 0, len(targets))
 	for _, t := range targets {
@@ -240,13 +383,11 @@ func (h *Handlers) HandleExportCSV(w http.ResponseWriter, r *http.Request) {
 	}
 
 	ctx := context.Background()
-	data, err := h.svc.FetchTimeSeriesData(ctx, service.FetchOptions{
-		Base:      domain.Currency(base),
-		Targets:   targetCurrencies,
-		StartDate: startDate,
-		EndDate:   endDate,
-		UseCache:  true,
-	})
+	opts.Base = domain.Currency(base)
+	opts.Targets = targetCurrencies
+	opts.StartDate = startDate
+	opts.EndDate = endDate
+	data, err := h.svc.FetchTimeSeriesData(ctx, opts)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Failed to fetch data: %v", err), http.StatusInternalServerError)
 		return
@@ -259,8 +400,14 @@ func (h *Handlers) HandleExportCSV(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/csv")
 	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
 
+	bars := opts.Aggregation == service.AggregateOHLC && data.Interval != ""
+
 	w.Write([]byte("Date"))
 	for _, currency := range data.Targets {
+		if bars {
+			w.Write([]byte(fmt.Sprintf(",%[1]s_open,%[1]s_high,%[1]s_low,%[1]s_close", currency)))
+			continue
+		}
 		w.Write([]byte(fmt.Sprintf(",%s", currency)))
 	}
 	w.Write([]byte("\n"))
@@ -268,6 +415,14 @@ func (h *Handlers) HandleExportCSV(w http.ResponseWriter, r *http.Request) {
 	for _, dp := range data.DataPoints {
 		w.Write([]byte(dp.Date.Format("2006-01-02")))
 		for _, currency := range data.Targets {
+			if bars {
+				if bar, exists := dp.OHLC[currency]; exists {
+					w.Write([]byte(fmt.Sprintf(",%.6f,%.6f,%.6f,%.6f", bar.Open, bar.High, bar.Low, bar.Close)))
+				} else {
+					w.Write([]byte(",,,,"))
+				}
+				continue
+			}
 			if rate, exists := dp.Rates[currency]; exists {
 				w.Write([]byte(fmt.Sprintf(",%.6f", rate)))
 			} else {
@@ -305,6 +460,12 @@ func (h *Handlers) HandleExportJSON(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	opts, err := h.fetchOptions(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	targets := strings.Split(currenciesStr, ",")
 	targetCurrencies := make([]domain.Currency, 0, len(targets))
 	for _, t := range targets {
@@ -315,13 +476,11 @@ func (h *Handlers) HandleExportJSON(w http.ResponseWriter, r *http.Request) {
 	}
 
 	ctx := context.Background()
-	data, err := h.svc.FetchTimeSeriesData(ctx, service.FetchOptions{
-		Base:      domain.Currency(base),
-		Targets:   targetCurrencies,
-		StartDate: startDate,
-		EndDate:   endDate,
-		UseCache:  true,
-	})
+	opts.Base = domain.Currency(base)
+	opts.Targets = targetCurrencies
+	opts.StartDate = startDate
+	opts.EndDate = endDate
+	data, err := h.svc.FetchTimeSeriesData(ctx, opts)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Failed to fetch data: %v", err), http.StatusInternalServerError)
 		return
@@ -330,12 +489,16 @@ func (h *Handlers) HandleExportJSON(w http.ResponseWriter, r *http.Request) {
 	stats := h.svc.CalculateStatistics(data)
 
 	type ExportData struct {
-		Base       string                             `json:"base"`
-		Targets    []string                           `json:"targets"`
-		StartDate  string                             `json:"start_date"`
-		EndDate    string                             `json:"end_date"`
-		Data       []domain.DataPoint                 `json:"data"`
-		Statistics map[string]statistics.Statistics   `json:"statistics"`
+		Base         string                              `json:"base"`
+		Targets      []string                            `json:"targets"`
+		StartDate    string                              `json:"start_date"`
+		EndDate      string                              `json:"end_date"`
+		Data         []domain.DataPoint                  `json:"data"`
+		Statistics   map[string]statistics.Statistics    `json:"statistics"`
+		Sources      map[domain.Currency][]string        `json:"sources,omitempty"`
+		DerivedVia   map[domain.Currency]domain.Currency `json:"derived_via,omitempty"`
+		Interval     string                              `json:"interval,omitempty"`
+		Correlations *statistics.Correlations            `json:"correlations,omitempty"`
 	}
 
 	targets_str := make([]string, len(data.Targets))
@@ -350,6 +513,13 @@ func (h *Handlers) HandleExportJSON(w http.ResponseWriter, r *http.Request) {
 		EndDate:    data.EndDate.Format("2006-01-02"),
 		Data:       data.DataPoints,
 		Statistics: stats,
+		Sources:    data.Sources,
+		DerivedVia: data.DerivedVia,
+		Interval:   data.Interval,
+	}
+	if len(data.Targets) > 1 {
+		correlations := h.svc.CalculateCorrelations(data)
+		exportData.Correlations = &correlations
 	}
 
 	filename := fmt.Sprintf("xrv-data-%s-%s.json", 
@@ -1,6 +1,7 @@
 package browser
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -200,6 +201,47 @@ func TestHandleStatisticsRefresh(t *testing.T) {
 	}
 }
 
+func TestHandleAverages(t *testing.T) {
+	mockAPI := &mockAPIClient{
+		timeSeriesResponse: &providers.TimeSeriesResponse{
+			Base:      "EUR",
+			StartDate: "2024-01-02",
+			EndDate:   "2024-02-01",
+			Rates: map[string]map[string]float64{
+				"2024-01-02": {"USD": 1.00},
+				"2024-01-31": {"USD": 1.20},
+				"2024-02-01": {"USD": 1.10},
+			},
+		},
+	}
+
+	memCache := cache.NewMemoryCache()
+	defer memCache.Close()
+
+	handlers := NewHandlers(service.NewService(mockAPI, memCache))
+
+	req := httptest.NewRequest(http.MethodGet, "/htmx/averages?base=EUR&currencies=USD&from=2024-01-01&to=2024-02-29&period=month", nil)
+	w := httptest.NewRecorder()
+	handlers.HandleAverages(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	body := w.Body.String()
+	for _, want := range []string{"EUR/USD", "2024-01", "1.200000", `class="partial"`, `<option value="month" selected>`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("response does not contain %q", want)
+		}
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/htmx/averages?base=EUR&currencies=USD&from=2024-01-01&to=2024-02-29&period=day", nil)
+	w = httptest.NewRecorder()
+	handlers.HandleAverages(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("daily period: Status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
 func TestInvertRates_Function(t *testing.T) {
 	data := &domain.TimeSeriesData{
 		Base:    "USD",
@@ -280,6 +322,47 @@ func TestHandleExportCSV(t *testing.T) {
 	}
 }
 
+func TestHandleExportCSV_Resampled(t *testing.T) {
+	mockAPI := &mockAPIClient{
+		timeSeriesResponse: &providers.TimeSeriesResponse{
+			Base:      "USD",
+			StartDate: "2024-01-01",
+			EndDate:   "2024-02-29",
+			Rates: map[string]map[string]float64{
+				"2024-01-02": {"EUR": 0.91},
+				"2024-01-31": {"EUR": 0.92},
+				"2024-02-01": {"EUR": 0.93},
+			},
+		},
+	}
+
+	memCache := cache.NewMemoryCache()
+	defer memCache.Close()
+
+	handlers := NewHandlers(service.NewService(mockAPI, memCache))
+
+	req := httptest.NewRequest(http.MethodGet, "/export/csv?base=USD&currencies=EUR&from=2024-01-01&to=2024-02-29&interval=month&aggregation=ohlc", nil)
+	w := httptest.NewRecorder()
+
+	handlers.HandleExportCSV(w, req)
+
+	want := "Date,EUR_open,EUR_high,EUR_low,EUR_close\n" +
+		"2024-01-01,0.910000,0.920000,0.910000,0.920000\n" +
+		"2024-02-01,0.930000,0.930000,0.930000,0.930000\n"
+	if got := w.Body.String(); got != want {
+		t.Errorf("CSV =\n%s\nwant\n%s", got, want)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/export/csv?base=USD&currencies=EUR&from=2024-01-01&to=2024-02-29&interval=fortnight", nil)
+	w = httptest.NewRecorder()
+
+	handlers.HandleExportCSV(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Status = %d, want %d for an unknown interval", w.Code, http.StatusBadRequest)
+	}
+}
+
 func TestHandleExportJSON(t *testing.T) {
 	mockAPI := &mockAPIClient{
 		timeSeriesResponse: &providers.TimeSeriesResponse{
@@ -329,6 +412,45 @@ func TestHandleExportJSON(t *testing.T) {
 	}
 }
 
+func TestHandleExportJSON_Correlations(t *testing.T) {
+	mockAPI := &mockAPIClient{
+		timeSeriesResponse: &providers.TimeSeriesResponse{
+			Base:      "USD",
+			StartDate: "2024-01-01",
+			EndDate:   "2024-01-03",
+			Rates: map[string]map[string]float64{
+				"2024-01-01": {"EUR": 0.85, "GBP": 0.78},
+				"2024-01-02": {"EUR": 0.86, "GBP": 0.79},
+				"2024-01-03": {"EUR": 0.84, "GBP": 0.77},
+			},
+		},
+	}
+
+	memCache := cache.NewMemoryCache()
+	defer memCache.Close()
+
+	handlers := NewHandlers(service.NewService(mockAPI, memCache))
+
+	req := httptest.NewRequest(http.MethodGet, "/export/json?base=USD&currencies=EUR,GBP&from=2024-01-01&to=2024-01-03", nil)
+	w := httptest.NewRecorder()
+
+	handlers.HandleExportJSON(w, req)
+
+	var export struct {
+		Correlations struct {
+			Currencies []string
+			Matrix     [][]*float64
+		} `json:"correlations"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &export); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, w.Body.String())
+	}
+	matrix := export.Correlations.Matrix
+	if len(matrix) != 2 || matrix[0][1] == nil || *matrix[0][1] < 0.99 {
+		t.Errorf("Correlations = %+v, want EUR and GBP moving together", export.Correlations)
+	}
+}
+
 func TestHandleExportCSV_MissingParams(t *testing.T) {
 	memCache := cache.NewMemoryCache()
 	defer memCache.Close()
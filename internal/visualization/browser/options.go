@@ -0,0 +1,51 @@
+package browser
+
+import (
+	"github.com/kaze/xrv/internal/service"
+	"github.com/kaze/xrv/internal/statistics"
+)
+
+type settings struct {
+	theme        string
+	rebase       bool
+	gaps         service.GapPolicy
+	correlations *statistics.Correlations
+}
+
+type Option func(*settings)
+
+func WithTheme(theme string) Option {
+	return func(s *settings) {
+		if theme != "default" {
+			s.theme = theme
+		}
+	}
+}
+
+func WithRebase(enabled bool) Option {
+	return func(s *settings) {
+		s.rebase = enabled
+	}
+}
+
+func WithGapPolicy(policy service.GapPolicy) Option {
+	return func(s *settings) {
+		s.gaps = policy
+	}
+}
+
+// WithCorrelations adds a correlation heatmap to a rendered chart. The
+// server computes correlations per request and ignores it.
+func WithCorrelations(c statistics.Correlations) Option {
+	return func(s *settings) {
+		s.correlations = &c
+	}
+}
+
+func newSettings(opts []Option) settings {
+	var s settings
+	for _, opt := range opts {
+		opt(&s)
+	}
+	return s
+}
@@ -0,0 +1,147 @@
+package browser
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"math"
+	"net/http"
+
+	"github.com/kaze/xrv/internal/portfolio"
+	"github.com/kaze/xrv/internal/statistics"
+)
+
+// TransformPortfolioConfig stacks the value of each currency as an area so
+// that the top edge traces the portfolio total.
+func TransformPortfolioConfig(v *portfolio.Valuation) (*EChartsConfig, error) {
+	if v == nil {
+		return nil, fmt.Errorf("valuation cannot be nil")
+	}
+
+	dates := make([]string, len(v.Dates))
+	for i, date := range v.Dates {
+		dates[i] = date.Format("2006-01-02")
+	}
+
+	legendData := make([]string, 0, len(v.ByCurrency))
+	series := make([]EChartsSeries, 0, len(v.ByCurrency))
+	for _, contribution := range v.ByCurrency {
+		name := fmt.Sprintf("%s (%.1f%%)", contribution.Currency, contribution.Share)
+		legendData = append(legendData, name)
+		series = append(series, EChartsSeries{
+			Name:  name,
+			Type:  "line",
+			Data:  seriesPointers(contribution.Values),
+			Area:  true,
+			Stack: "value",
+		})
+	}
+
+	config := &EChartsConfig{
+		Title: EChartsTitle{
+			Text: fmt.Sprintf("Portfolio value in %s", v.Reporting),
+			Subtext: fmt.Sprintf("%s to %s",
+				v.StartDate.Format("2006-01-02"),
+				v.EndDate.Format("2006-01-02")),
+		},
+		Tooltip: EChartsTooltip{
+			Trigger: "axis",
+			Show:    true,
+		},
+		Legend: EChartsLegend{
+			Data: legendData,
+			Show: true,
+			Top:  "10%",
+		},
+		XAxis: EChartsXAxis{
+			Type: "category",
+			Name: "Date",
+			Data: dates,
+		},
+		YAxis: EChartsYAxis{
+			Type: "value",
+			Name: string(v.Reporting),
+		},
+		Series: series,
+		Toolbox: EChartsToolbox{
+			Show: true,
+			Feature: &EChartsToolboxFeature{
+				SaveAsImage: &EChartsToolboxFeatureSaveAsImage{
+					Show:  true,
+					Type:  "png",
+					Title: "Save",
+				},
+				DataZoom: &EChartsToolboxFeatureDataZoom{
+					Show: true,
+					Title: map[string]string{
+						"zoom": "Zoom",
+						"back": "Reset",
+					},
+				},
+			},
+		},
+		DataZoom: []EChartsDataZoom{
+			{
+				Type:  "slider",
+				Start: 0,
+				End:   100,
+			},
+		},
+	}
+
+	return config, nil
+}
+
+// RenderPortfolio serves the stacked value chart and a summary of every
+// position until the process is interrupted.
+func (r *Renderer) RenderPortfolio(v *portfolio.Valuation) error {
+	http.HandleFunc("/", func(w http.ResponseWriter, req *http.Request) {
+		r.renderPortfolio(w, v)
+	})
+
+	url := fmt.Sprintf("http://localhost:%d", r.port)
+
+	fmt.Printf("\n🌐 Starting browser visualization server...\n")
+	fmt.Printf("📊 Opening %s in your browser\n\n", url)
+	fmt.Println("Press Ctrl+C to stop the server")
+
+	go r.openBrowser(url)
+
+	return http.ListenAndServe(fmt.Sprintf(":%d", r.port), nil)
+}
+
+func (r *Renderer) renderPortfolio(w io.Writer, v *portfolio.Valuation) {
+	config, err := TransformPortfolioConfig(v)
+	if err != nil {
+		fmt.Fprintf(w, "Error transforming data: %v", err)
+		return
+	}
+	config.Theme = r.theme
+
+	configJSON, err := json.Marshal(config)
+	if err != nil {
+		fmt.Fprintf(w, "Error marshaling config: %v", err)
+		return
+	}
+
+	type TemplateData struct {
+		ChartConfigJSON template.JS
+		Valuation       *portfolio.Valuation
+	}
+
+	templates.ExecuteTemplate(w, "portfolio-page", TemplateData{
+		ChartConfigJSON: template.JS(configJSON),
+		Valuation:       v,
+	})
+}
+
+func seriesPointers(values statistics.Series) []*float64 {
+	points := make([]*float64, len(values))
+	for i := range values {
+		if !math.IsNaN(values[i]) {
+			points[i] = &values[i]
+		}
+	}
+	return points
+}
@@ -0,0 +1,70 @@
+package browser
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kaze/xrv/internal/portfolio"
+	"github.com/kaze/xrv/internal/statistics"
+)
+
+func testValuation() *portfolio.Valuation {
+	return &portfolio.Valuation{
+		Reporting: "EUR",
+		StartDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		Dates: []time.Time{
+			time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		},
+		Positions: []portfolio.Position{
+			{Holding: portfolio.Holding{Currency: "USD", Amount: 100}, Value: 90, Rate: 0.9, Share: 75, CostSource: portfolio.CostFromStart},
+		},
+		ByCurrency: []portfolio.Contribution{
+			{Currency: "USD", Values: statistics.Series{92, 90}, Value: 90, Share: 75},
+			{Currency: "GBP", Values: statistics.Series{math.NaN(), 30}, Value: 30, Share: 25},
+		},
+		Total:      statistics.Series{math.NaN(), 120},
+		TotalValue: 120,
+	}
+}
+
+func TestTransformPortfolioConfig(t *testing.T) {
+	config, err := TransformPortfolioConfig(testValuation())
+	if err != nil {
+		t.Fatalf("TransformPortfolioConfig() error = %v", err)
+	}
+
+	if len(config.XAxis.Data) != 2 || config.YAxis.Name != "EUR" {
+		t.Errorf("axes = %+v / %+v", config.XAxis, config.YAxis)
+	}
+	if len(config.Series) != 2 {
+		t.Fatalf("series = %d, want one per currency", len(config.Series))
+	}
+	usd, gbp := config.Series[0], config.Series[1]
+	if usd.Name != "USD (75.0%)" || usd.Stack != "value" || !usd.Area {
+		t.Errorf("USD series = %+v, want a stacked area", usd)
+	}
+	if gbp.Data[0] != nil || *gbp.Data[1] != 30 {
+		t.Errorf("GBP data = %v, want a gap before the first value", gbp.Data)
+	}
+
+	if _, err := TransformPortfolioConfig(nil); err == nil {
+		t.Error("TransformPortfolioConfig(nil) should fail")
+	}
+}
+
+func TestRenderPortfolio(t *testing.T) {
+	var buf bytes.Buffer
+	NewRenderer(0).renderPortfolio(&buf, testValuation())
+
+	body := buf.String()
+	for _, want := range []string{"Portfolio value in EUR", "Total (EUR)", "120.00", "Cost basis (start)"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("page does not contain %q", want)
+		}
+	}
+}
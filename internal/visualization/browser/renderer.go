@@ -16,13 +16,14 @@ import (
 
 type Renderer struct {
 	port int
+	settings
 }
 
-func NewRenderer(port int) *Renderer {
+func NewRenderer(port int, opts ...Option) *Renderer {
 	if port <= 0 {
 		port = 8080
 	}
-	return &Renderer{port: port}
+	return &Renderer{port: port, settings: newSettings(opts)}
 }
 
 func (r *Renderer) Render(data *domain.TimeSeriesData, stats map[string]statistics.Statistics) error {
@@ -47,6 +48,7 @@ func (r *Renderer) renderChartOnly(w io.Writer, data *domain.TimeSeriesData, sta
 		fmt.Fprintf(w, "Error transforming data: %v", err)
 		return
 	}
+	config.Theme = r.theme
 
 	configJSON, err := json.Marshal(config)
 	if err != nil {
@@ -54,14 +56,24 @@ func (r *Renderer) renderChartOnly(w io.Writer, data *domain.TimeSeriesData, sta
 		return
 	}
 
+	heatmapJSON, err := marshalHeatmap(TransformCorrelationHeatmap(r.correlations), r.theme)
+	if err != nil {
+		fmt.Fprintf(w, "Error marshaling heatmap: %v", err)
+		return
+	}
+
 	type TemplateData struct {
 		ChartConfigJSON template.JS
+		HeatmapJSON     template.JS
 		Statistics      map[string]statistics.Statistics
+		Series          map[string]seriesInfo
 	}
 
 	templates.ExecuteTemplate(w, "chart-page", TemplateData{
 		ChartConfigJSON: template.JS(configJSON),
+		HeatmapJSON:     heatmapJSON,
 		Statistics:      stats,
+		Series:          describeSeries(data),
 	})
 }
 
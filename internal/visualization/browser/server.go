@@ -32,9 +32,11 @@ type Server struct {
 	port      int
 	svc       *service.Service
 	apiClient providers.APIClient
+	opts      []Option
+	settings
 }
 
-func NewServer(port int, svc *service.Service, apiClient providers.APIClient) *Server {
+func NewServer(port int, svc *service.Service, apiClient providers.APIClient, opts ...Option) *Server {
 	if port <= 0 {
 		port = 8080
 	}
@@ -42,6 +44,8 @@ func NewServer(port int, svc *service.Service, apiClient providers.APIClient) *S
 		port:      port,
 		svc:       svc,
 		apiClient: apiClient,
+		opts:      opts,
+		settings:  newSettings(opts),
 	}
 }
 
@@ -51,7 +55,7 @@ func (s *Server) Start() error {
 		return fmt.Errorf("failed to load assets: %w", err)
 	}
 
-	handlers := NewHandlers(s.svc)
+	handlers := NewHandlers(s.svc, s.opts...)
 
 	http.Handle("/assets/", http.StripPrefix("/assets/", http.FileServer(assetFS)))
 	http.HandleFunc("/", s.handleIndex)
@@ -59,6 +63,7 @@ func (s *Server) Start() error {
 	http.HandleFunc("/currencies", s.handleCurrencies)
 	http.HandleFunc("/htmx/chart", handlers.HandleChartUpdate)
 	http.HandleFunc("/htmx/statistics", handlers.HandleStatisticsRefresh)
+	http.HandleFunc("/htmx/averages", handlers.HandleAverages)
 	http.HandleFunc("/export/csv", handlers.HandleExportCSV)
 	http.HandleFunc("/export/json", handlers.HandleExportJSON)
 
@@ -116,6 +121,7 @@ func (s *Server) handleVisualize(w http.ResponseWriter, r *http.Request) {
 		StartDate: startDate,
 		EndDate:   endDate,
 		UseCache:  true,
+		Rebase:    s.rebase,
 	})
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Failed to fetch data: %v", err), http.StatusInternalServerError)
@@ -133,6 +139,7 @@ func (s *Server) handleVisualize(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, fmt.Sprintf("Failed to transform data: %v", err), http.StatusInternalServerError)
 		return
 	}
+	config.Theme = s.theme
 
 	configJSON, err := json.Marshal(config)
 	if err != nil {
@@ -140,15 +147,26 @@ func (s *Server) handleVisualize(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	correlations := s.svc.CalculateCorrelations(data)
+	heatmapJSON, err := marshalHeatmap(TransformCorrelationHeatmap(&correlations), s.theme)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to marshal heatmap: %v", err), http.StatusInternalServerError)
+		return
+	}
+
 	type TemplateData struct {
 		ChartConfigJSON template.JS
+		HeatmapJSON     template.JS
 		Statistics      map[string]statistics.Statistics
+		Series          map[string]seriesInfo
 	}
 
 	w.Header().Set("Content-Type", "text/html")
 	templates.ExecuteTemplate(w, "chart-page", TemplateData{
 		ChartConfigJSON: template.JS(configJSON),
+		HeatmapJSON:     heatmapJSON,
 		Statistics:      stats,
+		Series:          describeSeries(data),
 	})
 }
 
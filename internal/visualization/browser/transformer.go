@@ -2,6 +2,9 @@ package browser
 
 import (
 	"fmt"
+	"math"
+	"sort"
+	"strings"
 
 	"github.com/kaze/xrv/internal/domain"
 	"github.com/kaze/xrv/internal/statistics"
@@ -18,9 +21,10 @@ type EChartsTooltip struct {
 }
 
 type EChartsLegend struct {
-	Data []string `json:"data"`
-	Show bool     `json:"show"`
-	Top  string   `json:"top"`
+	Data     []string        `json:"data"`
+	Show     bool            `json:"show"`
+	Top      string          `json:"top"`
+	Selected map[string]bool `json:"selected,omitempty"`
 }
 
 type EChartsXAxis struct {
@@ -34,11 +38,34 @@ type EChartsYAxis struct {
 	Name string `json:"name"`
 }
 
+// EChartsSeries holds nil entries for dates without a rate so that ECharts
+// draws a gap instead of a drop to zero.
 type EChartsSeries struct {
-	Name   string    `json:"name"`
-	Type   string    `json:"type"`
-	Data   []float64 `json:"data"`
-	Smooth bool      `json:"smooth"`
+	Name    string        `json:"name"`
+	Type    string        `json:"type"`
+	Data    []*float64    `json:"data"`
+	Candles []*[4]float64 `json:"candles,omitempty"`
+	Smooth  bool          `json:"smooth"`
+	Dashed  bool          `json:"dashed,omitempty"`
+	Area    bool          `json:"area,omitempty"`
+	Stack   string        `json:"stack,omitempty"`
+}
+
+// EChartsPane is an extra grid below the main chart that shares its dates,
+// used for indicators on a different scale such as RSI, MACD, volatility and
+// drawdown.
+type EChartsPane struct {
+	Name   string          `json:"name"`
+	Series []EChartsSeries `json:"series"`
+}
+
+// EChartsHeatmap is the correlation matrix as heatmap cells of
+// [column, row, value]; undefined correlations have no cell.
+type EChartsHeatmap struct {
+	Title      string       `json:"title"`
+	Currencies []string     `json:"currencies"`
+	Data       [][3]float64 `json:"data"`
+	Theme      string       `json:"theme,omitempty"`
 }
 
 type EChartsToolboxFeatureSaveAsImage struct {
@@ -75,8 +102,10 @@ type EChartsConfig struct {
 	XAxis    EChartsXAxis      `json:"xAxis"`
 	YAxis    EChartsYAxis      `json:"yAxis"`
 	Series   []EChartsSeries   `json:"series"`
+	Panes    []EChartsPane     `json:"panes,omitempty"`
 	Toolbox  EChartsToolbox    `json:"toolbox"`
 	DataZoom []EChartsDataZoom `json:"dataZoom"`
+	Theme    string            `json:"theme,omitempty"`
 }
 
 func TransformToEChartsConfig(data *domain.TimeSeriesData, stats map[string]statistics.Statistics) (*EChartsConfig, error) {
@@ -93,10 +122,10 @@ func TransformToEChartsConfig(data *domain.TimeSeriesData, stats map[string]stat
 	series := make([]EChartsSeries, 0, len(data.Targets))
 
 	for _, target := range data.Targets {
-		rates := make([]float64, len(data.DataPoints))
+		rates := make([]*float64, len(data.DataPoints))
 		for i, dp := range data.DataPoints {
 			if rate, exists := dp.Rates[target]; exists {
-				rates[i] = rate
+				rates[i] = &rate
 			}
 		}
 
@@ -108,6 +137,16 @@ func TransformToEChartsConfig(data *domain.TimeSeriesData, stats map[string]stat
 
 		legendData = append(legendData, seriesName)
 
+		if candles := candlestickData(data, target); candles != nil {
+			series = append(series, EChartsSeries{
+				Name:    seriesName,
+				Type:    "candlestick",
+				Data:    rates,
+				Candles: candles,
+			})
+			continue
+		}
+
 		series = append(series, EChartsSeries{
 			Name:   seriesName,
 			Type:   "line",
@@ -116,21 +155,45 @@ func TransformToEChartsConfig(data *domain.TimeSeriesData, stats map[string]stat
 		})
 	}
 
+	overlays, panes := indicatorSeries(data, stats)
+	for _, pane := range []EChartsPane{volatilityPane(data, stats), drawdownPane(data, stats)} {
+		if len(pane.Series) > 0 {
+			panes = append(panes, pane)
+		}
+	}
+	var selected map[string]bool
+	for _, overlay := range overlays {
+		legendData = append(legendData, overlay.Name)
+		if len(data.Targets) > 1 {
+			if selected == nil {
+				selected = make(map[string]bool, len(overlays))
+			}
+			selected[overlay.Name] = false
+		}
+	}
+	series = append(series, overlays...)
+
+	subtext := fmt.Sprintf("%s to %s", 
+		data.StartDate.Format("2006-01-02"), 
+		data.EndDate.Format("2006-01-02"))
+	if data.Interval != "" {
+		subtext = fmt.Sprintf("%s (per %s)", subtext, data.Interval)
+	}
+
 	config := &EChartsConfig{
 		Title: EChartsTitle{
 			Text:    fmt.Sprintf("%s Exchange Rates", data.Base),
-			Subtext: fmt.Sprintf("%s to %s", 
-				data.StartDate.Format("2006-01-02"), 
-				data.EndDate.Format("2006-01-02")),
+			Subtext: subtext,
 		},
 		Tooltip: EChartsTooltip{
 			Trigger: "axis",
 			Show:    true,
 		},
 		Legend: EChartsLegend{
-			Data: legendData,
-			Show: true,
-			Top:  "10%",
+			Data:     legendData,
+			Show:     true,
+			Top:      "10%",
+			Selected: selected,
 		},
 		XAxis: EChartsXAxis{
 			Type: "category",
@@ -142,6 +205,7 @@ func TransformToEChartsConfig(data *domain.TimeSeriesData, stats map[string]stat
 			Name: "Exchange Rate",
 		},
 		Series: series,
+		Panes:  panes,
 		Toolbox: EChartsToolbox{
 			Show: true,
 			Feature: &EChartsToolboxFeature{
@@ -170,3 +234,187 @@ func TransformToEChartsConfig(data *domain.TimeSeriesData, stats map[string]stat
 
 	return config, nil
 }
+
+// indicatorSeries returns the moving average and Bollinger overlays for the
+// main chart, hidden by default when several targets share it, and the RSI
+// and MACD panes.
+func indicatorSeries(data *domain.TimeSeriesData, stats map[string]statistics.Statistics) ([]EChartsSeries, []EChartsPane) {
+	var overlays []EChartsSeries
+	rsi := EChartsPane{Name: "RSI"}
+	macd := EChartsPane{Name: "MACD"}
+
+	for _, target := range data.Targets {
+		stat, exists := stats[string(target)]
+		if !exists {
+			continue
+		}
+		indicators := stat.Indicators
+		line := func(name string, values statistics.Series, dashed bool) EChartsSeries {
+			return EChartsSeries{
+				Name:   fmt.Sprintf("%s %s", target, name),
+				Type:   "line",
+				Data:   alignIndicator(data, target, values),
+				Dashed: dashed,
+			}
+		}
+
+		for _, average := range stat.Trend.Averages() {
+			overlays = append(overlays, line(average.Spec.String(), average.Values, true))
+		}
+		if len(indicators.Bollinger.Upper) > 0 {
+			overlays = append(overlays,
+				line("BB upper", indicators.Bollinger.Upper, true),
+				line("BB lower", indicators.Bollinger.Lower, true),
+			)
+		}
+
+		if len(indicators.RSI) > 0 {
+			rsi.Series = append(rsi.Series, line("RSI", indicators.RSI, false))
+		}
+		if len(indicators.MACD.Line) > 0 {
+			histogram := line("MACD histogram", indicators.MACD.Histogram, false)
+			histogram.Type = "bar"
+			macd.Series = append(macd.Series,
+				line("MACD", indicators.MACD.Line, false),
+				line("MACD signal", indicators.MACD.Signal, true),
+				histogram,
+			)
+		}
+	}
+
+	var panes []EChartsPane
+	for _, pane := range []EChartsPane{rsi, macd} {
+		if len(pane.Series) > 0 {
+			panes = append(panes, pane)
+		}
+	}
+	return overlays, panes
+}
+
+// TransformCorrelationHeatmap returns nil when there is nothing to compare.
+func TransformCorrelationHeatmap(c *statistics.Correlations) *EChartsHeatmap {
+	if c == nil || len(c.Currencies) < 2 {
+		return nil
+	}
+
+	heatmap := &EChartsHeatmap{
+		Title:      fmt.Sprintf("Correlation of log returns (%d returns)", len(c.Dates)),
+		Currencies: c.Currencies,
+	}
+	for row, values := range c.Matrix {
+		for column, value := range values {
+			if !math.IsNaN(value) {
+				heatmap.Data = append(heatmap.Data, [3]float64{float64(column), float64(row), math.Round(value*100) / 100})
+			}
+		}
+	}
+	return heatmap
+}
+
+// volatilityPane draws each target's rolling and EWMA volatility, annualized
+// in percent.
+func volatilityPane(data *domain.TimeSeriesData, stats map[string]statistics.Statistics) EChartsPane {
+	pane := EChartsPane{Name: "Volatility %"}
+	for _, target := range data.Targets {
+		stat, exists := stats[string(target)]
+		if !exists {
+			continue
+		}
+		vol := stat.Volatility
+
+		windows := make([]int, 0, len(vol.Rolling))
+		for window := range vol.Rolling {
+			windows = append(windows, window)
+		}
+		sort.Ints(windows)
+		for _, window := range windows {
+			pane.Series = append(pane.Series, EChartsSeries{
+				Name: fmt.Sprintf("%s vol(%d)", target, window),
+				Type: "line",
+				Data: alignIndicator(data, target, vol.Rolling[window]),
+			})
+		}
+		if len(vol.EWMA) > 0 {
+			pane.Series = append(pane.Series, EChartsSeries{
+				Name:   fmt.Sprintf("%s EWMA vol", target),
+				Type:   "line",
+				Data:   alignIndicator(data, target, vol.EWMA),
+				Dashed: true,
+			})
+		}
+	}
+	return pane
+}
+
+// drawdownPane shades each target's underwater curve: the percentage below
+// its running peak, zero at every new high.
+func drawdownPane(data *domain.TimeSeriesData, stats map[string]statistics.Statistics) EChartsPane {
+	pane := EChartsPane{Name: "Drawdown %"}
+	for _, target := range data.Targets {
+		stat, exists := stats[string(target)]
+		if !exists || len(stat.Drawdown.Underwater) == 0 {
+			continue
+		}
+		pane.Series = append(pane.Series, EChartsSeries{
+			Name: fmt.Sprintf("%s drawdown", target),
+			Type: "line",
+			Data: alignIndicator(data, target, stat.Drawdown.Underwater),
+			Area: true,
+		})
+	}
+	return pane
+}
+
+// alignIndicator maps an indicator computed over the target's observed rates
+// back onto the chart's dates, leaving dates without a rate empty.
+func alignIndicator(data *domain.TimeSeriesData, target domain.Currency, values statistics.Series) []*float64 {
+	aligned := make([]*float64, len(data.DataPoints))
+	next := 0
+	for i, dp := range data.DataPoints {
+		if _, exists := dp.Rates[target]; !exists {
+			continue
+		}
+		if next < len(values) && !math.IsNaN(values[next]) {
+			aligned[i] = &values[next]
+		}
+		next++
+	}
+	return aligned
+}
+
+// candlestickData returns the target's bars in ECharts order (open, close,
+// low, high), or nil when the series was not aggregated into OHLC bars.
+func candlestickData(data *domain.TimeSeriesData, target domain.Currency) []*[4]float64 {
+	var candles []*[4]float64
+	for i, dp := range data.DataPoints {
+		bar, exists := dp.OHLC[target]
+		if !exists {
+			continue
+		}
+		if candles == nil {
+			candles = make([]*[4]float64, len(data.DataPoints))
+		}
+		candles[i] = &[4]float64{bar.Open, bar.Close, bar.Low, bar.High}
+	}
+	return candles
+}
+
+type seriesInfo struct {
+	Sources    string
+	DerivedVia string
+}
+
+func describeSeries(data *domain.TimeSeriesData) map[string]seriesInfo {
+	if len(data.Sources) == 0 && len(data.DerivedVia) == 0 {
+		return nil
+	}
+
+	series := make(map[string]seriesInfo, len(data.Targets))
+	for _, target := range data.Targets {
+		series[string(target)] = seriesInfo{
+			Sources:    strings.Join(data.Sources[target], ", "),
+			DerivedVia: string(data.DerivedVia[target]),
+		}
+	}
+	return series
+}
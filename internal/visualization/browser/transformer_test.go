@@ -2,6 +2,8 @@ package browser
 
 import (
 	"encoding/json"
+	"math"
+	"strings"
 	"testing"
 	"time"
 
@@ -235,3 +237,123 @@ func TestTransformToEChartsConfig_SeriesNames(t *testing.T) {
 		t.Error("GBP series name should contain currency code, average, and trend")
 	}
 }
+
+func TestTransformToEChartsConfig_Candlesticks(t *testing.T) {
+	data := &domain.TimeSeriesData{
+		Base:     "USD",
+		Targets:  []domain.Currency{"EUR"},
+		Interval: "week",
+		DataPoints: []domain.DataPoint{
+			{
+				Date:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+				Rates: map[domain.Currency]float64{"EUR": 0.91},
+				OHLC:  map[domain.Currency]domain.OHLC{"EUR": {Open: 0.90, High: 0.92, Low: 0.89, Close: 0.91}},
+			},
+		},
+	}
+
+	config, err := TransformToEChartsConfig(data, nil)
+	if err != nil {
+		t.Fatalf("TransformToEChartsConfig() error = %v", err)
+	}
+
+	series := config.Series[0]
+	if series.Type != "candlestick" {
+		t.Errorf("Type = %s, want candlestick", series.Type)
+	}
+	if want := [4]float64{0.90, 0.91, 0.89, 0.92}; len(series.Candles) != 1 || *series.Candles[0] != want {
+		t.Errorf("Candles = %v, want [%v] (open, close, low, high)", series.Candles, want)
+	}
+	if !contains(config.Title.Subtext, "per week") {
+		t.Errorf("Subtext = %q, want the interval", config.Title.Subtext)
+	}
+}
+
+func TestTransformToEChartsConfig_Indicators(t *testing.T) {
+	data := &domain.TimeSeriesData{Base: "USD", Targets: []domain.Currency{"EUR"}}
+	var rates []float64
+	for i := 0; i < 40; i++ {
+		rate := 0.9 + float64(i%5)*0.01
+		rates = append(rates, rate)
+		data.DataPoints = append(data.DataPoints, domain.DataPoint{
+			Date:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, i),
+			Rates: map[domain.Currency]float64{"EUR": rate},
+		})
+	}
+	// A date without a EUR rate must not shift the indicators.
+	data.DataPoints = append(data.DataPoints[:10], append([]domain.DataPoint{{
+		Date:  time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC),
+		Rates: map[domain.Currency]float64{},
+	}}, data.DataPoints[10:]...)...)
+
+	stats := map[string]statistics.Statistics{"EUR": statistics.Calculate(rates)}
+
+	config, err := TransformToEChartsConfig(data, stats)
+	if err != nil {
+		t.Fatalf("TransformToEChartsConfig() error = %v", err)
+	}
+
+	names := make(map[string]EChartsSeries)
+	for _, s := range config.Series {
+		names[s.Name] = s
+	}
+	upper, exists := names["EUR BB upper"]
+	if !exists || !upper.Dashed {
+		t.Fatalf("expected a dashed Bollinger overlay, got %v", config.Legend.Data)
+	}
+	sma, exists := names["EUR SMA(20)"]
+	if !exists || !sma.Dashed {
+		t.Fatalf("expected a dashed SMA(20) overlay, got %v", config.Legend.Data)
+	}
+	if sma.Data[18] != nil || sma.Data[20] == nil {
+		t.Errorf("SMA(20) should start at the 20th rate, aligned with the dates")
+	}
+	if upper.Data[18] != nil || upper.Data[20] == nil || upper.Data[10] != nil {
+		t.Errorf("Bollinger band should start at the 20th rate and skip the empty date")
+	}
+	if got, want := *upper.Data[20], stats["EUR"].Indicators.Bollinger.Upper[19]; got != want {
+		t.Errorf("upper band on the 20th rate = %v, want %v", got, want)
+	}
+
+	paneNames := make([]string, len(config.Panes))
+	for i, pane := range config.Panes {
+		paneNames[i] = pane.Name
+	}
+	if got := strings.Join(paneNames, ", "); got != "RSI, MACD, Volatility %, Drawdown %" {
+		t.Fatalf("Panes = %s, want RSI, MACD, volatility and drawdown", got)
+	}
+	volatility := config.Panes[2].Series
+	if len(volatility) != 3 || volatility[0].Name != "EUR vol(30)" || volatility[2].Name != "EUR EWMA vol" {
+		t.Errorf("volatility pane should hold vol(30), vol(90) and EWMA, got %d series", len(volatility))
+	}
+	if volatility[0].Data[30] != nil || volatility[0].Data[31] == nil {
+		t.Errorf("vol(30) should start after 30 returns, skipping the empty date")
+	}
+	drawdown := config.Panes[3].Series[0]
+	if !drawdown.Area || drawdown.Data[10] != nil || drawdown.Data[0] == nil || *drawdown.Data[0] != 0 {
+		t.Errorf("drawdown should be a shaded area starting at zero and skipping the empty date")
+	}
+	if len(config.Panes[1].Series) != 3 || config.Panes[1].Series[2].Type != "bar" {
+		t.Errorf("MACD pane should hold line, signal and histogram bars")
+	}
+	if len(config.Legend.Selected) != 0 {
+		t.Errorf("overlays of a single target should be visible, got %v", config.Legend.Selected)
+	}
+}
+
+func TestTransformCorrelationHeatmap(t *testing.T) {
+	if TransformCorrelationHeatmap(&statistics.Correlations{Currencies: []string{"USD"}}) != nil {
+		t.Error("a single currency should have no heatmap")
+	}
+
+	heatmap := TransformCorrelationHeatmap(&statistics.Correlations{
+		Currencies: []string{"USD", "JPY"},
+		Matrix:     []statistics.Series{{1, -0.456}, {-0.456, math.NaN()}},
+	})
+	if heatmap == nil || len(heatmap.Data) != 3 {
+		t.Fatalf("heatmap = %+v, want three defined cells", heatmap)
+	}
+	if want := [3]float64{1, 0, -0.46}; heatmap.Data[1] != want {
+		t.Errorf("cell = %v, want %v (column, row, rounded value)", heatmap.Data[1], want)
+	}
+}
@@ -0,0 +1,90 @@
+package terminal
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/kaze/xrv/internal/domain"
+)
+
+const (
+	candleUp   = '█'
+	candleDown = '░'
+	candleWick = '│'
+)
+
+// plotCandles draws one column per bar; when there are more bars than fit in
+// width only the most recent ones are drawn. Rising bars have a solid body,
+// falling bars a shaded one.
+func plotCandles(bars []domain.OHLC, height, width int, caption string) string {
+	if len(bars) == 0 {
+		return ""
+	}
+	if height < 2 {
+		height = 2
+	}
+
+	low, high := bars[0].Low, bars[0].High
+	for _, bar := range bars {
+		low = math.Min(low, bar.Low)
+		high = math.Max(high, bar.High)
+	}
+
+	labelWidth := len(fmt.Sprintf("%.4f", high))
+	if w := len(fmt.Sprintf("%.4f", low)); w > labelWidth {
+		labelWidth = w
+	}
+
+	if columns := width - labelWidth - 2; columns > 0 && len(bars) > columns {
+		bars = bars[len(bars)-columns:]
+	}
+
+	span := high - low
+	row := func(value float64) int {
+		if span == 0 {
+			return height / 2
+		}
+		return int(math.Round((high - value) / span * float64(height-1)))
+	}
+
+	var b strings.Builder
+	for r := 0; r < height; r++ {
+		label := high
+		if span != 0 {
+			label = high - float64(r)*span/float64(height-1)
+		}
+		fmt.Fprintf(&b, "%*.4f ┤", labelWidth, label)
+
+		for _, bar := range bars {
+			top, bottom := row(math.Max(bar.Open, bar.Close)), row(math.Min(bar.Open, bar.Close))
+			switch {
+			case r >= top && r <= bottom && bar.Close >= bar.Open:
+				b.WriteRune(candleUp)
+			case r >= top && r <= bottom:
+				b.WriteRune(candleDown)
+			case r >= row(bar.High) && r <= row(bar.Low):
+				b.WriteRune(candleWick)
+			default:
+				b.WriteByte(' ')
+			}
+		}
+		b.WriteByte('\n')
+	}
+
+	if caption != "" {
+		fmt.Fprintf(&b, "%s%s", strings.Repeat(" ", labelWidth+2), caption)
+	}
+
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func (r *Renderer) extractBars(data *domain.TimeSeriesData, currency domain.Currency) []domain.OHLC {
+	bars := make([]domain.OHLC, 0, len(data.DataPoints))
+	for _, dp := range data.DataPoints {
+		if bar, exists := dp.OHLC[currency]; exists {
+			bars = append(bars, bar)
+		}
+	}
+	return bars
+}
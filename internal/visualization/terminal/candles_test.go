@@ -0,0 +1,44 @@
+package terminal
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kaze/xrv/internal/domain"
+)
+
+func TestPlotCandles(t *testing.T) {
+	bars := []domain.OHLC{
+		{Open: 1.0, High: 4.0, Low: 0.0, Close: 3.0},
+		{Open: 3.0, High: 3.0, Low: 1.0, Close: 2.0},
+	}
+
+	got := plotCandles(bars, 5, 80, "EUR/USD")
+	want := strings.Join([]string{
+		"4.0000 ┤│ ",
+		"3.0000 ┤█░",
+		"2.0000 ┤█░",
+		"1.0000 ┤█│",
+		"0.0000 ┤│ ",
+		"        EUR/USD",
+	}, "\n")
+	if got != want {
+		t.Errorf("plotCandles() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestPlotCandles_KeepsLatestBars(t *testing.T) {
+	bars := make([]domain.OHLC, 20)
+	for i := range bars {
+		v := float64(i)
+		bars[i] = domain.OHLC{Open: v, High: v + 1, Low: v, Close: v + 1}
+	}
+
+	lines := strings.Split(plotCandles(bars, 4, 15, ""), "\n")
+	for _, line := range lines {
+		plot := line[strings.Index(line, "┤")+len("┤"):]
+		if n := len([]rune(plot)); n != 15-len("20.0000")-2 {
+			t.Errorf("row %q has %d columns, want %d", line, n, 15-len("20.0000")-2)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package terminal
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/kaze/xrv/internal/statistics"
+)
+
+const ansiReset = "\033[0m"
+
+// RenderCorrelations prints the correlation matrix as a table colored from
+// red (moving against each other) to green (moving together), followed by
+// the latest rolling correlation of each pair.
+func (r *Renderer) RenderCorrelations(c statistics.Correlations) {
+	if len(c.Currencies) < 2 {
+		return
+	}
+
+	fmt.Printf("━━━ Correlation of log returns (%d returns) ━━━\n", len(c.Dates))
+	fmt.Print(formatCorrelationTable(c))
+
+	if len(c.Rolling) > 0 {
+		fmt.Println()
+		fmt.Printf("Rolling %d-return correlation, latest:\n", c.Window)
+		for _, pair := range c.Rolling {
+			value := pair.Values.Last()
+			fmt.Printf("  %s/%s  %s\n", pair.A, pair.B, colorCorrelation(value, formatIndicator("%+.2f", value)))
+		}
+	}
+	fmt.Println()
+}
+
+func formatCorrelationTable(c statistics.Correlations) string {
+	var b strings.Builder
+	b.WriteString("      ")
+	for _, currency := range c.Currencies {
+		fmt.Fprintf(&b, " %6s", currency)
+	}
+	b.WriteString("\n")
+
+	for i, currency := range c.Currencies {
+		fmt.Fprintf(&b, "%-6s", currency)
+		for _, value := range c.Matrix[i] {
+			cell := fmt.Sprintf("%6s", formatIndicator("%+.2f", value))
+			b.WriteString(" " + colorCorrelation(value, cell))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// colorCorrelation picks a brighter shade the stronger the correlation and
+// leaves weak or undefined values uncolored.
+func colorCorrelation(value float64, text string) string {
+	if math.IsNaN(value) || math.Abs(value) < 0.3 {
+		return text
+	}
+
+	code := "32"
+	if value < 0 {
+		code = "31"
+	}
+	if math.Abs(value) >= 0.7 {
+		code = "1;" + code
+	}
+	return "\033[" + code + "m" + text + ansiReset
+}
@@ -0,0 +1,53 @@
+package terminal
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/guptarohit/asciigraph"
+	"github.com/kaze/xrv/internal/portfolio"
+)
+
+// RenderPortfolio charts the total value of the portfolio and lists each
+// position with its share, cost basis and unrealized FX gain or loss.
+func (r *Renderer) RenderPortfolio(v *portfolio.Valuation) {
+	fmt.Println()
+	fmt.Printf("💼 Portfolio in %s\n", v.Reporting)
+	fmt.Printf("📅 %s to %s\n", v.StartDate.Format("2006-01-02"), v.EndDate.Format("2006-01-02"))
+	fmt.Println()
+
+	if len(v.Total) > 1 {
+		fmt.Println(asciigraph.Plot(v.Total,
+			asciigraph.Height(r.height),
+			asciigraph.Width(r.width),
+			asciigraph.Caption(fmt.Sprintf("Total value (%s)", v.Reporting)),
+		))
+		fmt.Println()
+	}
+
+	fmt.Print(formatPortfolioTable(v))
+	fmt.Println()
+	fmt.Printf("  Value:     %.2f %s (%+.2f over the period)\n", v.TotalValue, v.Reporting, v.Change)
+	fmt.Printf("  Cost:      %.2f %s\n", v.TotalCost, v.Reporting)
+	fmt.Printf("  Gain/loss: %+.2f %s\n", v.TotalGainLoss, v.Reporting)
+	fmt.Println()
+}
+
+func formatPortfolioTable(v *portfolio.Valuation) string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "CURRENCY\tAMOUNT\tRATE\tVALUE\tSHARE\tCOST\tGAIN/LOSS\t\t")
+	for _, p := range v.Positions {
+		if p.Unpriced {
+			fmt.Fprintf(w, "%s\t%.2f\t-\t-\t-\t%.2f (%s)\tno fixing\t\t\n",
+				p.Holding.Currency, p.Holding.Amount, p.CostBasis, p.CostSource)
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%.2f\t%.4f\t%.2f\t%.1f%%\t%.2f (%s)\t%+.2f\t%+.2f%%\t\n",
+			p.Holding.Currency, p.Holding.Amount, p.Rate, p.Value, p.Share,
+			p.CostBasis, p.CostSource, p.GainLoss, p.GainLossPercent)
+	}
+	w.Flush()
+	return b.String()
+}
@@ -2,7 +2,10 @@ package terminal
 
 import (
 	"fmt"
+	"math"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/guptarohit/asciigraph"
 	"github.com/kaze/xrv/internal/domain"
@@ -10,27 +13,68 @@ import (
 )
 
 type Renderer struct {
-	height int
-	width  int
+	height         int
+	width          int
+	showVolatility bool
+	showTrends     bool
+	showIndicators bool
+	candles        bool
 }
 
-func NewRenderer(height, width int) *Renderer {
+type Option func(*Renderer)
+
+func WithVolatility(show bool) Option {
+	return func(r *Renderer) {
+		r.showVolatility = show
+	}
+}
+
+func WithTrends(show bool) Option {
+	return func(r *Renderer) {
+		r.showTrends = show
+	}
+}
+
+func WithIndicators(show bool) Option {
+	return func(r *Renderer) {
+		r.showIndicators = show
+	}
+}
+
+func WithCandles(show bool) Option {
+	return func(r *Renderer) {
+		r.candles = show
+	}
+}
+
+func NewRenderer(height, width int, opts ...Option) *Renderer {
 	if height <= 0 {
 		height = 20
 	}
 	if width <= 0 {
 		width = 80
 	}
-	return &Renderer{
-		height: height,
-		width:  width,
+	r := &Renderer{
+		height:         height,
+		width:          width,
+		showVolatility: true,
+		showTrends:     true,
+		showIndicators: true,
+	}
+	for _, opt := range opts {
+		opt(r)
 	}
+	return r
 }
 
 func (r *Renderer) Render(data *domain.TimeSeriesData, stats map[string]statistics.Statistics) error {
 	fmt.Println()
 	fmt.Printf("📊 %s to %s\n", data.Base, strings.Join(r.currenciesToStrings(data.Targets), ", "))
-	fmt.Printf("📅 %s to %s\n", data.StartDate.Format("2006-01-02"), data.EndDate.Format("2006-01-02"))
+	if data.Interval != "" {
+		fmt.Printf("📅 %s to %s (per %s)\n", data.StartDate.Format("2006-01-02"), data.EndDate.Format("2006-01-02"), data.Interval)
+	} else {
+		fmt.Printf("📅 %s to %s\n", data.StartDate.Format("2006-01-02"), data.EndDate.Format("2006-01-02"))
+	}
 	fmt.Println()
 
 	for _, target := range data.Targets {
@@ -40,17 +84,45 @@ func (r *Renderer) Render(data *domain.TimeSeriesData, stats map[string]statisti
 		}
 
 		fmt.Printf("━━━ %s ━━━\n", target)
+		if sources := data.Sources[target]; len(sources) > 0 {
+			fmt.Printf("🔌 Source: %s\n", strings.Join(sources, ", "))
+		}
+		if via, derived := data.DerivedVia[target]; derived {
+			fmt.Printf("🔀 Derived via %s\n", via)
+		}
 
-		graph := asciigraph.Plot(rates,
-			asciigraph.Height(r.height),
-			asciigraph.Width(r.width),
-			asciigraph.Caption(fmt.Sprintf("%s/%s", data.Base, target)),
-		)
+		caption := fmt.Sprintf("%s/%s", data.Base, target)
+		var graph string
+		if bars := r.extractBars(data, target); r.candles && len(bars) > 0 {
+			graph = plotCandles(bars, r.height, r.width, caption)
+		} else {
+			series, colors, legends := [][]float64{rates}, []asciigraph.AnsiColor{asciigraph.Default}, []string{caption}
+			if stat, exists := stats[string(target)]; exists && r.showTrends {
+				for i, average := range stat.Trend.Averages() {
+					values := alignAverage(rates, average.Values)
+					if values == nil {
+						continue
+					}
+					series = append(series, values)
+					colors = append(colors, averageColors[i%len(averageColors)])
+					legends = append(legends, average.Spec.String())
+				}
+			}
+			opts := []asciigraph.Option{
+				asciigraph.Height(r.height),
+				asciigraph.Width(r.width),
+				asciigraph.Caption(caption),
+			}
+			if len(series) > 1 {
+				opts = append(opts, asciigraph.SeriesColors(colors...), asciigraph.SeriesLegends(legends...))
+			}
+			graph = asciigraph.PlotMany(series, opts...)
+		}
 		fmt.Println(graph)
 		fmt.Println()
 
 		if stat, exists := stats[string(target)]; exists {
-			r.displayStats(stat, string(target))
+			r.displayStats(stat, r.observedDates(data, target))
 		}
 		fmt.Println()
 	}
@@ -58,28 +130,156 @@ func (r *Renderer) Render(data *domain.TimeSeriesData, stats map[string]statisti
 	return nil
 }
 
-func (r *Renderer) displayStats(stat statistics.Statistics, currency string) {
+func (r *Renderer) displayStats(stat statistics.Statistics, dates []time.Time) {
 	fmt.Println("📈 Statistics:")
 	fmt.Printf("  Min:     %.4f\n", stat.Basic.Min)
 	fmt.Printf("  Max:     %.4f\n", stat.Basic.Max)
 	fmt.Printf("  Average: %.4f\n", stat.Basic.Average)
 	fmt.Printf("  Median:  %.4f\n", stat.Basic.Median)
+	if r.showVolatility {
+		fmt.Println()
+		fmt.Println("📊 Volatility:")
+		fmt.Printf("  StdDev:  %.4f\n", stat.Volatility.StdDev)
+		fmt.Printf("  Coeff:   %.2f%%\n", stat.Volatility.CoefficientOfVar)
+		r.displayLogVolatility(stat.Volatility)
+	}
+	if r.showTrends {
+		fmt.Println()
+		fmt.Println("📉 Trend:")
+		fmt.Printf("  Direction: %s\n", stat.Trend.Direction)
+		fmt.Printf("  Change:    %.2f%%\n", stat.Trend.PercentChange)
+		for _, average := range stat.Trend.Averages() {
+			fmt.Printf("  %-10s %s\n", average.Spec.String()+":", formatIndicator("%.4f", average.Values.Last()))
+		}
+	}
+	r.displayDrawdown(stat.Drawdown, dates)
+	if r.showIndicators {
+		r.displayIndicators(stat.Indicators)
+	}
+}
+
+// displayLogVolatility lists the annualized log-return volatilities, the
+// rolling ones at their latest value.
+func (r *Renderer) displayLogVolatility(vol statistics.VolatilityStats) {
+	fmt.Printf("  Annualized: %.2f%% (%.4f%% per period)\n", vol.Annualized, vol.Realized)
+
+	windows := make([]int, 0, len(vol.Rolling))
+	for window := range vol.Rolling {
+		windows = append(windows, window)
+	}
+	sort.Ints(windows)
+	for _, window := range windows {
+		fmt.Printf("  Rolling(%d): %s\n", window, formatIndicator("%.2f%%", vol.Rolling[window].Last()))
+	}
+	fmt.Printf("  EWMA(λ=%g): %s\n", vol.Options.Lambda, formatIndicator("%.2f%%", vol.EWMA.Last()))
+}
+
+// displayDrawdown names the peak, trough and recovery by date; dates holds
+// the dates of the observed rates the statistics were computed from.
+func (r *Renderer) displayDrawdown(dd statistics.DrawdownStats, dates []time.Time) {
+	if dd.Peak >= len(dates) || dd.Trough >= len(dates) || dd.Recovery >= len(dates) {
+		return
+	}
+	day := func(i int) string { return dates[i].Format("2006-01-02") }
+
 	fmt.Println()
-	fmt.Println("📊 Volatility:")
-	fmt.Printf("  StdDev:  %.4f\n", stat.Volatility.StdDev)
-	fmt.Printf("  Coeff:   %.2f%%\n", stat.Volatility.CoefficientOfVar)
+	fmt.Println("🌊 Drawdown:")
+	fmt.Printf("  Max:       %.2f%%\n", dd.MaxDrawdown)
+	if dd.MaxDrawdown == 0 {
+		return
+	}
+	fmt.Printf("  Peak:      %s\n", day(dd.Peak))
+	fmt.Printf("  Trough:    %s (%d periods)\n", day(dd.Trough), dd.Duration)
+	if dd.Recovered {
+		fmt.Printf("  Recovered: %s (%d periods)\n", day(dd.Recovery), dd.TimeToRecovery)
+	} else {
+		fmt.Printf("  Recovered: not yet (%.2f%% below the peak)\n", -dd.Underwater.Last())
+	}
+}
+
+func (r *Renderer) displayIndicators(ind statistics.IndicatorStats) {
+	opts := ind.Options
 	fmt.Println()
-	fmt.Println("📉 Trend:")
-	fmt.Printf("  Direction: %s\n", stat.Trend.Direction)
-	fmt.Printf("  Change:    %.2f%%\n", stat.Trend.PercentChange)
+	fmt.Println("📐 Indicators:")
+	fmt.Printf("  RSI(%d):     %s\n", opts.RSIPeriod, formatIndicator("%.2f", ind.RSI.Last()))
+	fmt.Printf("  MACD(%d,%d,%d): %s / signal %s / hist %s\n",
+		opts.MACDFast, opts.MACDSlow, opts.MACDSignal,
+		formatIndicator("%.4f", ind.MACD.Line.Last()),
+		formatIndicator("%.4f", ind.MACD.Signal.Last()),
+		formatIndicator("%.4f", ind.MACD.Histogram.Last()),
+	)
+	fmt.Printf("  Bollinger(%d, %gσ): %s – %s\n",
+		opts.BollingerPeriod, opts.BollingerWidth,
+		formatIndicator("%.4f", ind.Bollinger.Lower.Last()),
+		formatIndicator("%.4f", ind.Bollinger.Upper.Last()),
+	)
+}
+
+// averageColors tells the moving average overlays apart from the rates,
+// which keep the terminal's default color.
+var averageColors = []asciigraph.AnsiColor{
+	asciigraph.Yellow, asciigraph.Cyan, asciigraph.Magenta, asciigraph.Green, asciigraph.Blue,
+}
+
+// alignAverage maps a moving average computed over the observed rates onto
+// the plotted dates, which hold NaN where no rate was published. It returns
+// nil when the average has no values yet.
+func alignAverage(rates []float64, values statistics.Series) []float64 {
+	aligned := make([]float64, len(rates))
+	next, defined := 0, false
+	for i, rate := range rates {
+		aligned[i] = math.NaN()
+		if math.IsNaN(rate) {
+			continue
+		}
+		if next < len(values) && !math.IsNaN(values[next]) {
+			aligned[i] = values[next]
+			defined = true
+		}
+		next++
+	}
+	if !defined {
+		return nil
+	}
+	return aligned
+}
+
+// formatIndicator prints n/a for indicators without enough history.
+func formatIndicator(format string, value float64) string {
+	if math.IsNaN(value) {
+		return "n/a"
+	}
+	return fmt.Sprintf(format, value)
 }
 
+// observedDates returns the dates on which the currency has a rate, matching
+// the rates the statistics were computed from.
+func (r *Renderer) observedDates(data *domain.TimeSeriesData, currency domain.Currency) []time.Time {
+	dates := make([]time.Time, 0, len(data.DataPoints))
+	for _, dp := range data.DataPoints {
+		if _, exists := dp.Rates[currency]; exists {
+			dates = append(dates, dp.Date)
+		}
+	}
+	return dates
+}
+
+// extractRates returns NaN for dates without a rate, which asciigraph draws
+// as a gap. It returns nil when the currency has no rates at all.
 func (r *Renderer) extractRates(data *domain.TimeSeriesData, currency domain.Currency) []float64 {
 	rates := make([]float64, 0, len(data.DataPoints))
+	observed := false
 	for _, dp := range data.DataPoints {
-		if rate, exists := dp.Rates[currency]; exists {
-			rates = append(rates, rate)
+		rate, exists := dp.Rates[currency]
+		if !exists {
+			rates = append(rates, math.NaN())
+			continue
 		}
+		rates = append(rates, rate)
+		observed = true
+	}
+	if !observed {
+		return nil
 	}
 	return rates
 }
@@ -0,0 +1,90 @@
+package terminal
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kaze/xrv/internal/alerts"
+	"github.com/kaze/xrv/internal/portfolio"
+	"github.com/kaze/xrv/internal/statistics"
+	"github.com/kaze/xrv/internal/watch"
+)
+
+func TestAlignAverage(t *testing.T) {
+	nan := math.NaN()
+	rates := []float64{1, nan, 2, 3, nan, 4}
+	average := statistics.Series{nan, 1.5, 2.5, 3.5}
+
+	got := alignAverage(rates, average)
+	want := []float64{nan, nan, 1.5, 2.5, nan, 3.5}
+	for i := range want {
+		if math.IsNaN(want[i]) != math.IsNaN(got[i]) || (!math.IsNaN(want[i]) && got[i] != want[i]) {
+			t.Fatalf("alignAverage() = %v, want %v", got, want)
+		}
+	}
+
+	if got := alignAverage(rates, statistics.Series{nan, nan, nan, nan}); got != nil {
+		t.Errorf("alignAverage() = %v, want nil for an average without values", got)
+	}
+}
+
+func TestFormatCorrelationTable(t *testing.T) {
+	nan := math.NaN()
+	table := formatCorrelationTable(statistics.Correlations{
+		Currencies: []string{"USD", "JPY"},
+		Matrix:     []statistics.Series{{1, -0.85}, {-0.85, nan}},
+	})
+
+	if !strings.Contains(table, "\033[1;31m -0.85"+ansiReset) {
+		t.Errorf("strong negative correlation should be bold red:\n%q", table)
+	}
+	if !strings.Contains(table, "\033[1;32m +1.00"+ansiReset) {
+		t.Errorf("diagonal should be bold green:\n%q", table)
+	}
+	if !strings.Contains(table, "   n/a") {
+		t.Errorf("undefined correlation should print n/a:\n%q", table)
+	}
+}
+
+func TestFormatPortfolioTable(t *testing.T) {
+	table := formatPortfolioTable(&portfolio.Valuation{
+		Reporting: "EUR",
+		Positions: []portfolio.Position{
+			{Holding: portfolio.Holding{Currency: "USD", Amount: 1000}, Rate: 0.9, Value: 900, Share: 100, CostBasis: 920, CostSource: portfolio.CostFromAcquired, GainLoss: -20, GainLossPercent: -2.17},
+		},
+	})
+
+	lines := strings.Split(strings.TrimSpace(table), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("table has %d lines, want a header and one position:\n%s", len(lines), table)
+	}
+	for _, want := range []string{"USD", "1000.00", "0.9000", "900.00", "100.0%", "920.00 (acquired)", "-20.00", "-2.17%"} {
+		if !strings.Contains(lines[1], want) {
+			t.Errorf("row %q does not contain %q", lines[1], want)
+		}
+	}
+}
+
+func TestFormatWatch(t *testing.T) {
+	alert := formatWatchAlert(alerts.Alert{
+		Rule:    alerts.Rule{Name: "Forint above 400"},
+		Date:    time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		Message: "EUR/HUF at 401.2000 rose above 400",
+	})
+	if !strings.HasPrefix(alert, "\033[1;33m") || !strings.Contains(alert, "2024-03-01  Forint above 400: EUR/HUF at 401.2000 rose above 400") {
+		t.Errorf("alert = %q, want a highlighted line", alert)
+	}
+
+	status := formatWatchStatus(watch.Update{
+		Polled: time.Date(2024, 3, 1, 15, 0, 0, 0, time.UTC),
+		Next:   time.Date(2024, 3, 1, 15, 10, 0, 0, time.UTC),
+		Err:    errors.New("failed to fetch data: timeout"),
+	})
+	lines := strings.Split(strings.TrimSpace(status), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], "timeout") || !strings.Contains(lines[1], "Latest fixing none yet") {
+		t.Errorf("status = %q, want the error and the poll times", status)
+	}
+}
@@ -0,0 +1,55 @@
+package terminal
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kaze/xrv/internal/alerts"
+	"github.com/kaze/xrv/internal/domain"
+	"github.com/kaze/xrv/internal/statistics"
+	"github.com/kaze/xrv/internal/watch"
+)
+
+// clearScreen moves the cursor home and clears the screen, so each poll
+// redraws the chart in place.
+const clearScreen = "\033[H\033[2J"
+
+// RenderWatch redraws the screen with the chart of data, the alerts fired so
+// far (oldest first) and the status of the last poll u. data may be from an
+// earlier poll when u failed, and nil when none succeeded yet.
+func (r *Renderer) RenderWatch(data *domain.TimeSeriesData, stats map[string]statistics.Statistics, fired []alerts.Alert, u watch.Update) error {
+	fmt.Print(clearScreen)
+	if data != nil {
+		if err := r.Render(data, stats); err != nil {
+			return err
+		}
+	}
+
+	for _, alert := range fired {
+		fmt.Println(formatWatchAlert(alert))
+	}
+	if len(fired) > 0 {
+		fmt.Println()
+	}
+	fmt.Print(formatWatchStatus(u))
+	return nil
+}
+
+func formatWatchAlert(alert alerts.Alert) string {
+	return fmt.Sprintf("\033[1;33m🔔 %s  %s: %s%s", alert.Date.Format("2006-01-02"), alert.Rule.String(), alert.Message, ansiReset)
+}
+
+func formatWatchStatus(u watch.Update) string {
+	var b strings.Builder
+	if u.Err != nil {
+		fmt.Fprintf(&b, "\033[31m⚠️  %v%s\n", u.Err, ansiReset)
+	}
+
+	latest := "none yet"
+	if !u.Latest.IsZero() {
+		latest = u.Latest.Format("2006-01-02")
+	}
+	fmt.Fprintf(&b, "🔄 Latest fixing %s · polled %s · next poll %s · Ctrl+C to stop\n",
+		latest, u.Polled.Local().Format("15:04:05"), u.Next.Local().Format("Mon 15:04 MST"))
+	return b.String()
+}
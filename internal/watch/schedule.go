@@ -0,0 +1,69 @@
+package watch
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kaze/xrv/internal/calendar"
+)
+
+// Schedule decides when to poll for reference rates, which are published once
+// per business day at about the same local time (around 16:00 CET for the
+// ECB).
+type Schedule struct {
+	Calendar    calendar.Calendar
+	Location    *time.Location
+	Publication time.Duration // after local midnight
+	Retry       time.Duration
+}
+
+// NewSchedule parses the publication time as HH:MM in the named timezone.
+func NewSchedule(cal calendar.Calendar, timezone, publication string, retry time.Duration) (Schedule, error) {
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return Schedule{}, fmt.Errorf("invalid timezone: %w", err)
+	}
+	clock, err := time.Parse("15:04", publication)
+	if err != nil {
+		return Schedule{}, fmt.Errorf("publication time must be HH:MM, got %q", publication)
+	}
+	if retry <= 0 {
+		return Schedule{}, fmt.Errorf("retry interval must be positive, got %s", retry)
+	}
+
+	return Schedule{
+		Calendar:    cal,
+		Location:    location,
+		Publication: time.Duration(clock.Hour())*time.Hour + time.Duration(clock.Minute())*time.Minute,
+		Retry:       retry,
+	}, nil
+}
+
+// Next returns when to poll after now, given the date of the latest fixing
+// held. On a business day whose fixing is missing it waits for the
+// publication and then retries every Retry until the fixing arrives;
+// otherwise it waits for the publication on the next business day.
+func (s Schedule) Next(now, latest time.Time) time.Time {
+	local := now.In(s.Location)
+	today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+
+	if s.Calendar.IsBusinessDay(today) && latest.Before(today) {
+		if published := s.publication(today); now.Before(published) {
+			return published
+		}
+		return now.Add(s.Retry)
+	}
+
+	day := today.AddDate(0, 0, 1)
+	for !s.Calendar.IsBusinessDay(day) {
+		day = day.AddDate(0, 0, 1)
+	}
+	return s.publication(day)
+}
+
+// publication returns the publication time on day in local wall-clock time,
+// so it stays put across daylight saving changes.
+func (s Schedule) publication(day time.Time) time.Time {
+	minutes := int(s.Publication / time.Minute)
+	return time.Date(day.Year(), day.Month(), day.Day(), 0, minutes, 0, 0, s.Location)
+}
@@ -0,0 +1,66 @@
+package watch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kaze/xrv/internal/calendar"
+)
+
+func TestSchedule_Next(t *testing.T) {
+	schedule, err := NewSchedule(calendar.TARGET2(), "Europe/Berlin", "16:10", 15*time.Minute)
+	if err != nil {
+		t.Fatalf("NewSchedule() error = %v", err)
+	}
+	berlin := schedule.Location
+
+	at := func(s string) time.Time {
+		t.Helper()
+		parsed, err := time.ParseInLocation("2006-01-02 15:04", s, berlin)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+	day := func(s string) time.Time {
+		parsed, _ := time.Parse("2006-01-02", s)
+		return parsed
+	}
+
+	tests := []struct {
+		name   string
+		now    time.Time
+		latest time.Time
+		want   time.Time
+	}{
+		{"before publication", at("2024-03-01 10:00"), day("2024-02-29"), at("2024-03-01 16:10")},
+		{"fixing late", at("2024-03-01 17:00"), day("2024-02-29"), at("2024-03-01 17:15")},
+		{"fixing in", at("2024-03-01 17:00"), day("2024-03-01"), at("2024-03-04 16:10")},
+		{"weekend", at("2024-03-02 09:00"), day("2024-03-01"), at("2024-03-04 16:10")},
+		{"after midnight UTC", at("2024-03-01 00:30"), day("2024-02-29"), at("2024-03-01 16:10")},
+		{"Easter and summer time", at("2024-03-28 16:30"), day("2024-03-28"), at("2024-04-02 16:10")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := schedule.Next(tt.now, tt.latest); !got.Equal(tt.want) {
+				t.Errorf("Next() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	if got := at("2024-04-02 16:10").UTC().Hour(); got != 14 {
+		t.Errorf("publication in CEST is at %d:10 UTC, want 14:10", got)
+	}
+}
+
+func TestNewSchedule_Invalid(t *testing.T) {
+	for _, args := range [][2]string{{"Europe/Atlantis", "16:00"}, {"Europe/Berlin", "4pm"}} {
+		if _, err := NewSchedule(calendar.Weekends(), args[0], args[1], time.Minute); err == nil {
+			t.Errorf("NewSchedule(%q, %q) should fail", args[0], args[1])
+		}
+	}
+	if _, err := NewSchedule(calendar.Weekends(), "UTC", "16:00", 0); err == nil {
+		t.Error("NewSchedule() without a retry interval should fail")
+	}
+}
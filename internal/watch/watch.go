@@ -0,0 +1,125 @@
+package watch
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kaze/xrv/internal/alerts"
+	"github.com/kaze/xrv/internal/domain"
+	"github.com/kaze/xrv/internal/service"
+)
+
+type Options struct {
+	Base     domain.Currency
+	Targets  []domain.Currency
+	Days     int // calendar days of the rolling window
+	UseCache bool
+	Rebase   bool
+	Gaps     service.GapPolicy
+	Rules    []alerts.Rule
+	Schedule Schedule
+}
+
+// Update is the outcome of one poll.
+type Update struct {
+	Polled time.Time
+	Data   *domain.TimeSeriesData
+	// Latest is the date of the latest fixing and Fresh whether it is newer
+	// than at the previous poll.
+	Latest time.Time
+	Fresh  bool
+	// Alerts holds the rules that fired on a fixing they have not fired on
+	// before.
+	Alerts []alerts.Alert
+	Next   time.Time
+	Err    error
+}
+
+type Watcher struct {
+	src    service.Fetcher
+	opts   Options
+	latest time.Time
+	fired  map[string]time.Time
+}
+
+// New watches the rates of src. With UseCache only the days missing from the
+// cache are fetched, so each poll after the first requests little more than
+// the new fixing.
+func New(src service.Fetcher, opts Options) *Watcher {
+	return &Watcher{src: src, opts: opts, fired: make(map[string]time.Time)}
+}
+
+// Poll fetches the window ending at now and checks the rules. A failed poll
+// keeps the previous state and is retried after Schedule.Retry.
+func (w *Watcher) Poll(ctx context.Context, now time.Time) Update {
+	update := Update{Polled: now, Latest: w.latest}
+
+	data, err := w.src.FetchTimeSeriesData(ctx, service.FetchOptions{
+		Base:      w.opts.Base,
+		Targets:   w.opts.Targets,
+		StartDate: now.AddDate(0, 0, -w.opts.Days),
+		EndDate:   now,
+		UseCache:  w.opts.UseCache,
+		Rebase:    w.opts.Rebase,
+		Gaps:      w.opts.Gaps,
+	})
+	if err != nil {
+		update.Err = fmt.Errorf("failed to fetch data: %w", err)
+		update.Next = now.Add(w.opts.Schedule.Retry)
+		return update
+	}
+	update.Data = data
+
+	var triggered []alerts.Alert
+	if len(w.opts.Rules) > 0 {
+		triggered, err = alerts.Check(ctx, w.src, w.opts.Rules, alerts.CheckOptions{
+			EndDate:  now,
+			UseCache: w.opts.UseCache,
+			Rebase:   w.opts.Rebase,
+		})
+		if err != nil {
+			update.Err = fmt.Errorf("failed to check alerts: %w", err)
+			update.Next = now.Add(w.opts.Schedule.Retry)
+			return update
+		}
+	}
+
+	if n := len(data.DataPoints); n > 0 && data.DataPoints[n-1].Date.After(w.latest) {
+		update.Latest = data.DataPoints[n-1].Date
+		update.Fresh = true
+		w.latest = update.Latest
+	}
+
+	for _, alert := range triggered {
+		key := alert.Rule.String()
+		if date, exists := w.fired[key]; exists && !alert.Date.After(date) {
+			continue
+		}
+		w.fired[key] = alert.Date
+		update.Alerts = append(update.Alerts, alert)
+	}
+
+	update.Next = w.opts.Schedule.Next(now, update.Latest)
+	return update
+}
+
+// Run polls on the schedule and passes every update to fn until ctx is
+// cancelled.
+func (w *Watcher) Run(ctx context.Context, fn func(Update)) {
+	for {
+		update := w.Poll(ctx, time.Now())
+		if ctx.Err() != nil {
+			return
+		}
+		fn(update)
+
+		timer := time.NewTimer(time.Until(update.Next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
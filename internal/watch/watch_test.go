@@ -0,0 +1,156 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kaze/xrv/internal/alerts"
+	"github.com/kaze/xrv/internal/cache"
+	"github.com/kaze/xrv/internal/calendar"
+	"github.com/kaze/xrv/internal/domain"
+	"github.com/kaze/xrv/internal/providers"
+	"github.com/kaze/xrv/internal/service"
+)
+
+type fakeSource struct {
+	rates []float64
+	err   error
+	calls int
+}
+
+func (f *fakeSource) FetchTimeSeriesData(ctx context.Context, opts service.FetchOptions) (*domain.TimeSeriesData, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	data := &domain.TimeSeriesData{Base: opts.Base, Targets: opts.Targets}
+	for i, rate := range f.rates {
+		data.DataPoints = append(data.DataPoints, domain.DataPoint{
+			Date:  time.Date(2024, 2, 27, 0, 0, 0, 0, time.UTC).AddDate(0, 0, i),
+			Rates: map[domain.Currency]float64{"HUF": rate},
+		})
+	}
+	return data, nil
+}
+
+func TestWatcher_Poll(t *testing.T) {
+	schedule := Schedule{Calendar: calendar.Weekends(), Location: time.UTC, Publication: 16 * time.Hour, Retry: time.Minute}
+	above := 400.0
+	src := &fakeSource{rates: []float64{398, 399, 401}}
+	w := New(src, Options{
+		Base:     "EUR",
+		Targets:  []domain.Currency{"HUF"},
+		Days:     30,
+		UseCache: true,
+		Rules:    []alerts.Rule{{Base: "EUR", Target: "HUF", Type: alerts.RuleThreshold, Above: &above}},
+		Schedule: schedule,
+	})
+
+	now := time.Date(2024, 2, 29, 17, 0, 0, 0, time.UTC)
+	update := w.Poll(context.Background(), now)
+	if update.Err != nil {
+		t.Fatalf("Poll() error = %v", update.Err)
+	}
+	if !update.Fresh || !update.Latest.Equal(time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Latest = %s, Fresh = %v, want the new fixing of 2024-02-29", update.Latest, update.Fresh)
+	}
+	if len(update.Alerts) != 1 || update.Alerts[0].Rate != 401 {
+		t.Errorf("Alerts = %+v, want the crossing of 400", update.Alerts)
+	}
+	if want := time.Date(2024, 3, 1, 16, 0, 0, 0, time.UTC); !update.Next.Equal(want) {
+		t.Errorf("Next = %s, want %s", update.Next, want)
+	}
+
+	update = w.Poll(context.Background(), now.Add(time.Hour))
+	if update.Fresh || len(update.Alerts) != 0 {
+		t.Errorf("second poll = %+v, want no new fixing and no repeated alert", update)
+	}
+
+	src.rates = append(src.rates, 399, 402)
+	update = w.Poll(context.Background(), now.AddDate(0, 0, 2))
+	if !update.Fresh || len(update.Alerts) != 1 || update.Alerts[0].Rate != 402 {
+		t.Errorf("third poll = %+v, want a new crossing", update)
+	}
+
+	src.err = errors.New("connection refused")
+	update = w.Poll(context.Background(), now)
+	if update.Err == nil || !update.Next.Equal(now.Add(time.Minute)) || !update.Latest.Equal(time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("failed poll = %+v, want a retry after a minute with the latest fixing kept", update)
+	}
+}
+
+func TestWatcher_Poll_ECBFeedUpdated(t *testing.T) {
+	feed := filepath.Join(t.TempDir(), "eurofxref-hist.csv")
+	write := func(content string, modified time.Time) {
+		t.Helper()
+		if err := os.WriteFile(feed, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write feed: %v", err)
+		}
+		if err := os.Chtimes(feed, modified, modified); err != nil {
+			t.Fatalf("failed to touch feed: %v", err)
+		}
+	}
+
+	published := time.Date(2024, 2, 28, 15, 0, 0, 0, time.UTC)
+	write("Date,HUF,\n2024-02-28,390.1,\n2024-02-27,389.5,\n", published)
+
+	memCache := cache.NewMemoryCache()
+	defer memCache.Close()
+	svc := service.NewService(providers.NewECBClient(feed, time.Second), memCache)
+
+	w := New(svc, Options{
+		Base:     "EUR",
+		Targets:  []domain.Currency{"HUF"},
+		Days:     7,
+		Schedule: Schedule{Calendar: calendar.Weekends(), Location: time.UTC, Publication: 16 * time.Hour, Retry: time.Minute},
+	})
+
+	now := time.Date(2024, 2, 29, 16, 0, 0, 0, time.UTC)
+	update := w.Poll(context.Background(), now)
+	if update.Err != nil {
+		t.Fatalf("Poll() error = %v", update.Err)
+	}
+	if !update.Latest.Equal(time.Date(2024, 2, 28, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("Latest = %s, want 2024-02-28 before publication", update.Latest)
+	}
+
+	write("Date,HUF,\n2024-02-29,391.7,\n2024-02-28,390.1,\n2024-02-27,389.5,\n", published.AddDate(0, 0, 1))
+
+	update = w.Poll(context.Background(), now.Add(time.Minute))
+	if update.Err != nil {
+		t.Fatalf("Poll() error = %v", update.Err)
+	}
+	if !update.Fresh || !update.Latest.Equal(time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Latest = %s, Fresh = %v, want the fixing published between polls", update.Latest, update.Fresh)
+	}
+}
+
+func TestWatcher_RunStopsOnCancel(t *testing.T) {
+	src := &fakeSource{err: errors.New("offline")}
+	w := New(src, Options{Schedule: Schedule{Calendar: calendar.EveryDay(), Location: time.UTC, Retry: time.Millisecond}})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	updates := 0
+	done := make(chan struct{})
+	go func() {
+		w.Run(ctx, func(update Update) {
+			if updates++; updates == 3 {
+				cancel()
+			}
+		})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run() did not return after cancel")
+	}
+	if updates != 3 {
+		t.Errorf("updates = %d, want 3", updates)
+	}
+}